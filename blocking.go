@@ -0,0 +1,160 @@
+package redis
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BlockingPool has dedicated connections for blocking commands, such that
+// these never stall the pipeline of a Client. Connections are launched on
+// demand. The pool is independent of the Key and Value types, such that views
+// of any type can share it.
+type blockingPool struct {
+	// Number of connection state changes from SELECT and RESET, with
+	// atomic access. It comes first for 64-bit alignment.
+	epoch uint64
+
+	// Each pipeline is used by one command at a time. Nil entries are
+	// free slots for a connection yet to be launched.
+	idle chan *pipeline
+
+	mutex    sync.Mutex
+	launched map[*pipeline]uint64 // epoch on launch, for Close
+	closed   bool
+}
+
+func newBlockingPool(size int) *blockingPool {
+	p := &blockingPool{
+		idle:     make(chan *pipeline, size),
+		launched: make(map[*pipeline]uint64),
+	}
+	for i := 0; i < size; i++ {
+		p.idle <- nil
+	}
+	return p
+}
+
+// Close terminates all connections launched.
+func (p *blockingPool) close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	for launched := range p.launched {
+		launched.closePipeline()
+	}
+}
+
+// Invalidate makes each connection launched retire on its next acquire, such
+// that replacements connect with the database from SELECT, and in the clean
+// state from RESET.
+func (p *blockingPool) invalidate() {
+	atomic.AddUint64(&p.epoch, 1)
+}
+
+// Retire closes a pipeline from idle when it was launched before invalidate.
+// The return is true when the slot is free for a new launch.
+func (p *blockingPool) retire(idle *pipeline) bool {
+	p.mutex.Lock()
+	epoch, ok := p.launched[idle]
+	if !ok || epoch == atomic.LoadUint64(&p.epoch) {
+		p.mutex.Unlock()
+		return false
+	}
+	delete(p.launched, idle)
+	p.mutex.Unlock()
+
+	idle.closePipeline()
+	return true
+}
+
+// Blocker acquires a dedicated Client, with the context and the Key prefix of
+// c, if any. The pipeline must be returned to the pool.
+func (c *Client[Key, Value]) blocker() (*Client[Key, Value], error) {
+	var done <-chan struct{}
+	if c.ctx != nil {
+		done = c.ctx.Done()
+	}
+
+	var p *pipeline
+	select {
+	case p = <-c.blocking.idle:
+		break
+	case <-done:
+		return nil, c.ctx.Err()
+	}
+	if p != nil && c.blocking.retire(p) {
+		p = nil // replace
+	}
+
+	config := c.ClientConfig
+	config.PoolSize = 0
+	config.PingInterval = 0
+	config.BlockingPoolSize = -1
+
+	launch := p == nil
+	if launch {
+		// share database switches from SELECT
+		p = newPipeline(c.config, c.target, cap(c.readQueue))
+	}
+	b := &Client[Key, Value]{
+		ClientConfig: config,
+		pipeline:     p,
+		ctx:          c.ctx,
+		keyPrefix:    c.keyPrefix,
+	}
+	if !launch {
+		return b, nil
+	}
+
+	c.blocking.mutex.Lock()
+	defer c.blocking.mutex.Unlock()
+	if c.blocking.closed {
+		c.blocking.idle <- nil // free slot
+		return nil, ErrClosed
+	}
+	c.blocking.launched[p] = atomic.LoadUint64(&c.blocking.epoch)
+	go (&Client[Key, Value]{ClientConfig: config, pipeline: p}).connectOrClosed()
+	return b, nil
+}
+
+// CommandBlockingMap is like commandMap, yet it executes on a dedicated
+// connection, unless disabled by ClientConfig BlockingPoolSize.
+func (c *Client[Key, Value]) commandBlockingMap(req *request) ([]Key, []Value, error) {
+	if c.blocking == nil {
+		return c.commandMap(req)
+	}
+
+	b, err := c.blocker()
+	if err != nil {
+		req.free()
+		return nil, nil, err
+	}
+	defer func() {
+		c.blocking.idle <- b.pipeline // release
+	}()
+	return b.commandMap(req)
+}
+
+// Blocking extends the command timeout with the blocking duration of the
+// request. A zero timeout blocks indefinitely.
+func (r *request) blocking(timeout time.Duration) *request {
+	if timeout == 0 {
+		r.block = -1
+	} else {
+		r.block = timeout
+	}
+	return r
+}
+
+// AppendTimeout adds the seconds argument of blocking commands, including the
+// trailing CRLF.
+func appendTimeout(dst []byte, timeout time.Duration) []byte {
+	s := strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+	dst = append(dst, '$')
+	dst = strconv.AppendUint(dst, uint64(len(s)), 10)
+	dst = append(dst, '\r', '\n')
+	dst = append(dst, s...)
+	return append(dst, '\r', '\n')
+}
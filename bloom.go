@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"strconv"
+)
+
+// The BF and CF commands require the RedisBloom module, as included with Redis
+// Stack. Bloom filters may report false positives. Cuckoo filters may do so too,
+// yet they support deletion.
+
+// BFRESERVE executes <https://redis.io/commands/bf.reserve> with the desired
+// probability for false positives, e.g., 0.001 for one in a thousand. A zero
+// expansion applies the server default (of 2) for when capacity is reached,
+// and a negative expansion denies scaling with NONSCALING.
+func (c *Client[Key, Value]) BFRESERVE(k Key, errorRate float64, capacity, expansion int64) error {
+	rate := strconv.FormatFloat(errorRate, 'f', -1, 64)
+	size := strconv.FormatInt(capacity, 10)
+	var r *request
+	switch {
+	case expansion == 0:
+		r = requestWith3Strings("*4\r\n$10\r\nBF.RESERVE\r\n$", k, rate, size)
+	case expansion < 0:
+		r = requestWith3Strings("*5\r\n$10\r\nBF.RESERVE\r\n$", k, rate, size)
+		r.buf = append(r.buf, "$10\r\nNONSCALING\r\n"...)
+	default:
+		r = requestWith3Strings("*6\r\n$10\r\nBF.RESERVE\r\n$", k, rate, size)
+		r.buf = append(r.buf, "$9\r\nEXPANSION\r\n$"...)
+		r.addDecimalToDollar(expansion)
+	}
+	return c.commandOK(r)
+}
+
+// BFADD executes <https://redis.io/commands/bf.add>. The return is false if
+// the item may have been added before. The filter is created with defaults
+// when the Key does not exist.
+func (c *Client[Key, Value]) BFADD(k Key, item Value) (bool, error) {
+	n, err := c.commandInteger(requestWith2Strings("*3\r\n$6\r\nBF.ADD\r\n$", k, item))
+	return n != 0, err
+}
+
+// BFMADD executes <https://redis.io/commands/bf.madd>. The return has BFADD
+// results for each item, in order of appearance.
+func (c *Client[Key, Value]) BFMADD(k Key, items ...Value) ([]bool, error) {
+	return c.commandBools(requestWithStringAndList("\r\n$7\r\nBF.MADD\r\n$", k, items))
+}
+
+// BFEXISTS executes <https://redis.io/commands/bf.exists>. The return is false
+// if the item was not added.
+func (c *Client[Key, Value]) BFEXISTS(k Key, item Value) (bool, error) {
+	n, err := c.commandInteger(requestWith2Strings("*3\r\n$9\r\nBF.EXISTS\r\n$", k, item).idempotent())
+	return n != 0, err
+}
+
+// BFMEXISTS executes <https://redis.io/commands/bf.mexists>. The return has
+// BFEXISTS results for each item, in order of appearance.
+func (c *Client[Key, Value]) BFMEXISTS(k Key, items ...Value) ([]bool, error) {
+	return c.commandBools(requestWithStringAndList("\r\n$10\r\nBF.MEXISTS\r\n$", k, items).idempotent())
+}
+
+// CFRESERVE executes <https://redis.io/commands/cf.reserve>.
+func (c *Client[Key, Value]) CFRESERVE(k Key, capacity int64) error {
+	return c.commandOK(requestWithStringAndDecimal("*3\r\n$10\r\nCF.RESERVE\r\n$", k, capacity))
+}
+
+// CFADD executes <https://redis.io/commands/cf.add>. Items may be added more
+// than once. The filter is created with defaults when the Key does not exist.
+func (c *Client[Key, Value]) CFADD(k Key, item Value) error {
+	_, err := c.commandInteger(requestWith2Strings("*3\r\n$6\r\nCF.ADD\r\n$", k, item))
+	return err
+}
+
+// CFADDNX executes <https://redis.io/commands/cf.addnx>. The return is false
+// if the item may have been added before.
+func (c *Client[Key, Value]) CFADDNX(k Key, item Value) (bool, error) {
+	n, err := c.commandInteger(requestWith2Strings("*3\r\n$8\r\nCF.ADDNX\r\n$", k, item))
+	return n != 0, err
+}
+
+// CFEXISTS executes <https://redis.io/commands/cf.exists>. The return is false
+// if the item was not added.
+func (c *Client[Key, Value]) CFEXISTS(k Key, item Value) (bool, error) {
+	n, err := c.commandInteger(requestWith2Strings("*3\r\n$9\r\nCF.EXISTS\r\n$", k, item).idempotent())
+	return n != 0, err
+}
+
+// CFMEXISTS executes <https://redis.io/commands/cf.mexists>. The return has
+// CFEXISTS results for each item, in order of appearance.
+func (c *Client[Key, Value]) CFMEXISTS(k Key, items ...Value) ([]bool, error) {
+	return c.commandBools(requestWithStringAndList("\r\n$10\r\nCF.MEXISTS\r\n$", k, items).idempotent())
+}
+
+// CFDEL executes <https://redis.io/commands/cf.del>. The return is false if the
+// item was not found. Deletion of items which were not added may remove other
+// items.
+func (c *Client[Key, Value]) CFDEL(k Key, item Value) (bool, error) {
+	n, err := c.commandInteger(requestWith2Strings("*3\r\n$6\r\nCF.DEL\r\n$", k, item))
+	return n != 0, err
+}
+
+// CFCOUNT executes <https://redis.io/commands/cf.count>. The return is an
+// estimate of the number of times the item was added.
+func (c *Client[Key, Value]) CFCOUNT(k Key, item Value) (int64, error) {
+	return c.commandInteger(requestWith2Strings("*3\r\n$8\r\nCF.COUNT\r\n$", k, item).idempotent())
+}
+
+func (c *Client[Key, Value]) commandBools(req *request) ([]bool, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	bools, err := readBools(r)
+	c.passRead(err)
+	return bools, err
+}
+
+// ReadBools reads an array of integers, or RESP3 booleans. Error elements
+// return as such, after the array is consumed in full.
+func readBools(r *connReader) ([]bool, error) {
+	n, err := readArrayLen(r)
+	if n == 0 {
+		return nil, err
+	}
+	bools := make([]bool, n)
+	var firstErr error
+	for i := range bools {
+		v, err := readInteger(r)
+		if err != nil {
+			if _, ok := err.(ServerError); !ok {
+				return nil, err
+			}
+			// consume remaining elements
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		bools[i] = v != 0
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return bools, nil
+}
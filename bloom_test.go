@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"testing"
+)
+
+func TestReadBools(t *testing.T) {
+	r := testReader("*3\r\n:1\r\n#f\r\n:0\r\n*2\r\n-ERR full\r\n:1\r\n+OK\r\n")
+	got, err := readBools(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	if len(got) != 3 || !got[0] || got[1] || got[2] {
+		t.Errorf("got %t, want [true false false]", got)
+	}
+
+	if _, err := readBools(r); err == nil {
+		t.Error("error element got no error")
+	} else if _, ok := err.(ServerError); !ok {
+		t.Errorf("error element got %v, want a ServerError", err)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after error element got error:", err)
+	}
+}
+
+func TestBloom(t *testing.T) {
+	t.Parallel()
+	key := randomKey("bloom")
+
+	if err := testClient.BFRESERVE(key, 0.001, 100, 0); err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("BF.RESERVE error:", err)
+	}
+	if added, err := testClient.BFADD(key, "a"); err != nil {
+		t.Error("BF.ADD error:", err)
+	} else if !added {
+		t.Error("BF.ADD got false for a new item")
+	}
+	if added, err := testClient.BFMADD(key, "a", "b"); err != nil {
+		t.Error("BF.MADD error:", err)
+	} else if len(added) != 2 || added[0] || !added[1] {
+		t.Errorf("BF.MADD got %t, want [false true]", added)
+	}
+	if ok, err := testClient.BFMEXISTS(key, "a", "b"); err != nil {
+		t.Error("BF.MEXISTS error:", err)
+	} else if len(ok) != 2 || !ok[0] || !ok[1] {
+		t.Errorf("BF.MEXISTS got %t, want [true true]", ok)
+	}
+}
+
+func TestCuckoo(t *testing.T) {
+	t.Parallel()
+	key := randomKey("cuckoo")
+
+	if err := testClient.CFRESERVE(key, 100); err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("CF.RESERVE error:", err)
+	}
+	if err := testClient.CFADD(key, "a"); err != nil {
+		t.Error("CF.ADD error:", err)
+	}
+	if added, err := testClient.CFADDNX(key, "a"); err != nil {
+		t.Error("CF.ADDNX error:", err)
+	} else if added {
+		t.Error("CF.ADDNX got true for an existing item")
+	}
+	if n, err := testClient.CFCOUNT(key, "a"); err != nil {
+		t.Error("CF.COUNT error:", err)
+	} else if n != 1 {
+		t.Errorf("CF.COUNT got %d, want 1", n)
+	}
+	if deleted, err := testClient.CFDEL(key, "a"); err != nil {
+		t.Error("CF.DEL error:", err)
+	} else if !deleted {
+		t.Error("CF.DEL got false")
+	}
+	if ok, err := testClient.CFEXISTS(key, "a"); err != nil {
+		t.Error("CF.EXISTS error:", err)
+	} else if ok {
+		t.Error("CF.EXISTS got true after CF.DEL")
+	}
+}
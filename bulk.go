@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrBulkClosed rejects reads after Close on a BulkReader.
+var ErrBulkClosed = errors.New("redis: bulk reader closed")
+
+// BulkReader streams a bulk string from the connection. The pipeline of the
+// Client stalls until Close, which is mandatory. Reads are subject to the
+// command timeout, if any.
+type BulkReader struct {
+	r         *connReader
+	size      int64
+	remaining int64
+	err       error // sticky
+
+	// PassRead returns r to the read routine on Close.
+	passRead func(error)
+}
+
+// Size returns the total number of bytes in the bulk string.
+func (b *BulkReader) Size() int64 { return b.size }
+
+// Read implements the io.Reader interface.
+func (b *BulkReader) Read(p []byte) (n int, err error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.remaining == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err = b.r.Read(p)
+	b.remaining -= int64(n)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		b.err = err
+	}
+	return n, err
+}
+
+// Close discards any remainder, and it releases the connection for the next
+// command in line. Calling Close more than once has no effect.
+func (b *BulkReader) Close() error {
+	if b.err == ErrBulkClosed {
+		return nil
+	}
+	err := b.err
+	if err == nil {
+		// remainder plus CRLF
+		_, err = b.r.Discard(int(b.remaining) + 2)
+	}
+	b.passRead(err)
+	b.err = ErrBulkClosed
+	return err
+}
+
+// GETReader executes <https://redis.io/commands/get> with the value streamed
+// from the connection, without buffering the value as a whole. The return is
+// nil if the Key does not exist. Otherwise, the BulkReader must be closed.
+func (c *Client[Key, Value]) GETReader(k Key) (*BulkReader, error) {
+	req := requestWithString("*2\r\n$3\r\nGET\r\n$", k)
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	size, err := readBulkSize(r)
+	if err != nil {
+		c.passRead(err)
+		if err == errNull {
+			err = nil
+		}
+		return nil, err
+	}
+	return &BulkReader{r: r, size: size, remaining: size, passRead: c.passRead}, nil
+}
+
+// SETReader executes <https://redis.io/commands/set> with the value streamed
+// from r, without buffering the value as a whole. Exactly size bytes are read.
+// The pipeline stalls during the upload. Errors from r cause a reconnect, as
+// the request can't complete.
+func (c *Client[Key, Value]) SETReader(k Key, r io.Reader, size int64) error {
+	if size < 0 || size > c.SizeMax {
+		return fmt.Errorf("redis: SET value size %d out of range", size)
+	}
+	req := requestWithString("*3\r\n$3\r\nSET\r\n$", k)
+	req.buf = append(req.buf, '$')
+	req.buf = strconv.AppendInt(req.buf, size, 10)
+	req.buf = append(req.buf, '\r', '\n')
+	req.body = r
+	req.bodySize = size
+	return c.commandOK(req)
+}
+
+// ReadBulkSize reads the header of a bulk string, and it returns the number of
+// bytes which follow, excluding the CRLF.
+func readBulkSize(r *connReader) (int64, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case len(line) > 3 && line[0] == '$':
+		size := ParseInt(line[1 : len(line)-2])
+		if size < 0 || size > r.sizeMax {
+			if size == -1 {
+				// "null bulk string"
+				return 0, errNull
+			}
+			return 0, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+		}
+		return size, nil
+
+	case len(line) > 3 && line[0] == '=':
+		// RESP3 verbatim string has a 3-byte format prefix plus colon
+		size := ParseInt(line[1:len(line)-2]) - 4
+		if size < 0 || size > r.sizeMax {
+			return 0, fmt.Errorf("%w; received %.40q for verbatim string", errProtocol, line)
+		}
+		_, err := r.Discard(4)
+		return size, err
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return 0, errNull
+
+	case len(line) > 3 && line[0] == '-':
+		return 0, ServerError(line[1 : len(line)-2])
+
+	default:
+		return 0, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+	}
+}
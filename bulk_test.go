@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGETReader(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+	value := strings.Repeat("0123456789", 100e3)
+
+	if r, err := testClient.GETReader(key); err != nil {
+		t.Errorf("GET %q absent error: %s", key, err)
+	} else if r != nil {
+		t.Errorf("GET %q absent got a reader", key)
+		r.Close()
+	}
+
+	if err := testClient.SET(key, value); err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+
+	r, err := testClient.GETReader(key)
+	if err != nil {
+		t.Fatalf("GET %q error: %s", key, err)
+	}
+	if r.Size() != int64(len(value)) {
+		t.Errorf("got size %d, want %d", r.Size(), len(value))
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Error("read error:", err)
+	} else if string(got) != value {
+		t.Errorf("got %d bytes, want the value of %d bytes", len(got), len(value))
+	}
+	if err := r.Close(); err != nil {
+		t.Error("close error:", err)
+	}
+
+	// partial read must discard the remainder
+	r, err = testClient.GETReader(key)
+	if err != nil {
+		t.Fatalf("GET %q error: %s", key, err)
+	}
+	buf := make([]byte, 10)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Error("read error:", err)
+	} else if string(buf) != "0123456789" {
+		t.Errorf("got %q, want the first 10 bytes", buf)
+	}
+	if err := r.Close(); err != nil {
+		t.Error("close error:", err)
+	}
+	if _, err := r.Read(buf); err != ErrBulkClosed {
+		t.Errorf("read after close got error %v, want %v", err, ErrBulkClosed)
+	}
+
+	if n, err := testClient.STRLEN(key); err != nil {
+		t.Errorf("STRLEN %q after partial read error: %s", key, err)
+	} else if n != int64(len(value)) {
+		t.Errorf("STRLEN %q after partial read got %d, want %d", key, n, len(value))
+	}
+}
+
+func TestSETReader(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+	value := strings.Repeat("abcdefghij", 100e3)
+
+	err := testClient.SETReader(key, strings.NewReader(value), int64(len(value)))
+	if err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+	if got, err := testClient.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if got != value {
+		t.Errorf("GET %q got %d bytes, want the value of %d bytes", key, len(got), len(value))
+	}
+
+	// short body must not corrupt the pipeline
+	err = testClient.SETReader(key, strings.NewReader("abc"), 4)
+	if err == nil {
+		t.Error("SET with short body got no error")
+	}
+	for i := 0; i < 3; i++ {
+		var n int64
+		n, err = testClient.STRLEN(key)
+		if err == nil {
+			if n != int64(len(value)) {
+				t.Errorf("STRLEN %q after short body got %d, want %d", key, n, len(value))
+			}
+			break
+		}
+	}
+	if err != nil {
+		t.Errorf("STRLEN %q after short body error: %s", key, err)
+	}
+}
@@ -0,0 +1,790 @@
+package redis
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BytesClient is a Client with string Keys and []byte Values, without any type
+// parameters in its API, for interpreters, plugin systems and reflection-based
+// tools which lack support for generics. Each method calls the Client method of
+// the same name. Multiple goroutines may invoke methods on a BytesClient
+// simultaneously.
+type BytesClient struct {
+	c *Client[string, []byte]
+}
+
+// NewBytesClient is like NewClient.
+func NewBytesClient(config ClientConfig) *BytesClient {
+	return &BytesClient{NewClient[string, []byte](config)}
+}
+
+// DialBytesClient is like DialClient.
+func DialBytesClient(config ClientConfig) (*BytesClient, error) {
+	c, err := DialClient[string, []byte](config)
+	if err != nil {
+		return nil, err
+	}
+	return &BytesClient{c}, nil
+}
+
+// Config returns a copy of the ClientConfig in use.
+func (b *BytesClient) Config() ClientConfig {
+	return b.c.ClientConfig
+}
+
+// APPEND is like Client APPEND.
+func (b *BytesClient) APPEND(k string, v []byte) (newLen int64, err error) {
+	return b.c.APPEND(k, v)
+}
+
+// BFADD is like Client BFADD.
+func (b *BytesClient) BFADD(k string, item []byte) (bool, error) {
+	return b.c.BFADD(k, item)
+}
+
+// BFEXISTS is like Client BFEXISTS.
+func (b *BytesClient) BFEXISTS(k string, item []byte) (bool, error) {
+	return b.c.BFEXISTS(k, item)
+}
+
+// BFMADD is like Client BFMADD.
+func (b *BytesClient) BFMADD(k string, items ...[]byte) ([]bool, error) {
+	return b.c.BFMADD(k, items...)
+}
+
+// BFMEXISTS is like Client BFMEXISTS.
+func (b *BytesClient) BFMEXISTS(k string, items ...[]byte) ([]bool, error) {
+	return b.c.BFMEXISTS(k, items...)
+}
+
+// BFRESERVE is like Client BFRESERVE.
+func (b *BytesClient) BFRESERVE(k string, errorRate float64, capacity, expansion int64) error {
+	return b.c.BFRESERVE(k, errorRate, capacity, expansion)
+}
+
+// BLPOP is like Client BLPOP.
+func (b *BytesClient) BLPOP(timeout time.Duration, keys ...string) (string, []byte, error) {
+	return b.c.BLPOP(timeout, keys...)
+}
+
+// BRPOP is like Client BRPOP.
+func (b *BytesClient) BRPOP(timeout time.Duration, keys ...string) (string, []byte, error) {
+	return b.c.BRPOP(timeout, keys...)
+}
+
+// CFADD is like Client CFADD.
+func (b *BytesClient) CFADD(k string, item []byte) error {
+	return b.c.CFADD(k, item)
+}
+
+// CFADDNX is like Client CFADDNX.
+func (b *BytesClient) CFADDNX(k string, item []byte) (bool, error) {
+	return b.c.CFADDNX(k, item)
+}
+
+// CFCOUNT is like Client CFCOUNT.
+func (b *BytesClient) CFCOUNT(k string, item []byte) (int64, error) {
+	return b.c.CFCOUNT(k, item)
+}
+
+// CFDEL is like Client CFDEL.
+func (b *BytesClient) CFDEL(k string, item []byte) (bool, error) {
+	return b.c.CFDEL(k, item)
+}
+
+// CFEXISTS is like Client CFEXISTS.
+func (b *BytesClient) CFEXISTS(k string, item []byte) (bool, error) {
+	return b.c.CFEXISTS(k, item)
+}
+
+// CFMEXISTS is like Client CFMEXISTS.
+func (b *BytesClient) CFMEXISTS(k string, items ...[]byte) ([]bool, error) {
+	return b.c.CFMEXISTS(k, items...)
+}
+
+// CFRESERVE is like Client CFRESERVE.
+func (b *BytesClient) CFRESERVE(k string, capacity int64) error {
+	return b.c.CFRESERVE(k, capacity)
+}
+
+// CLIENTPAUSE is like Client CLIENTPAUSE.
+func (b *BytesClient) CLIENTPAUSE(timeout time.Duration, writeOnly bool) error {
+	return b.c.CLIENTPAUSE(timeout, writeOnly)
+}
+
+// CLIENTUNPAUSE is like Client CLIENTUNPAUSE.
+func (b *BytesClient) CLIENTUNPAUSE() error {
+	return b.c.CLIENTUNPAUSE()
+}
+
+// Close is like Client Close.
+func (b *BytesClient) Close() error {
+	return b.c.Close()
+}
+
+// CLUSTERCOUNTKEYSINSLOT is like Client CLUSTERCOUNTKEYSINSLOT.
+func (b *BytesClient) CLUSTERCOUNTKEYSINSLOT(slot int64) (int64, error) {
+	return b.c.CLUSTERCOUNTKEYSINSLOT(slot)
+}
+
+// CLUSTERINFO is like Client CLUSTERINFO.
+func (b *BytesClient) CLUSTERINFO() (*ClusterInfo, error) {
+	return b.c.CLUSTERINFO()
+}
+
+// CLUSTERMYID is like Client CLUSTERMYID.
+func (b *BytesClient) CLUSTERMYID() (string, error) {
+	return b.c.CLUSTERMYID()
+}
+
+// CLUSTERNODES is like Client CLUSTERNODES.
+func (b *BytesClient) CLUSTERNODES() ([]ClusterNode, error) {
+	return b.c.CLUSTERNODES()
+}
+
+// CLUSTERSHARDS is like Client CLUSTERSHARDS.
+func (b *BytesClient) CLUSTERSHARDS() ([]ClusterShard, error) {
+	return b.c.CLUSTERSHARDS()
+}
+
+// CLUSTERSLOTS is like Client CLUSTERSLOTS.
+func (b *BytesClient) CLUSTERSLOTS() ([]ClusterSlots, error) {
+	return b.c.CLUSTERSLOTS()
+}
+
+// COMMANDCOUNT is like Client COMMANDCOUNT.
+func (b *BytesClient) COMMANDCOUNT() (int64, error) {
+	return b.c.COMMANDCOUNT()
+}
+
+// COMMANDGETKEYS is like Client COMMANDGETKEYS.
+func (b *BytesClient) COMMANDGETKEYS(args ...string) ([]string, error) {
+	return b.c.COMMANDGETKEYS(args...)
+}
+
+// COMMANDINFO is like Client COMMANDINFO.
+func (b *BytesClient) COMMANDINFO(names ...string) ([]CommandInfo, error) {
+	return b.c.COMMANDINFO(names...)
+}
+
+// CONFIGGET is like Client CONFIGGET.
+func (b *BytesClient) CONFIGGET(pattern string) (map[string]string, error) {
+	return b.c.CONFIGGET(pattern)
+}
+
+// CONFIGRESETSTAT is like Client CONFIGRESETSTAT.
+func (b *BytesClient) CONFIGRESETSTAT() error {
+	return b.c.CONFIGRESETSTAT()
+}
+
+// CONFIGREWRITE is like Client CONFIGREWRITE.
+func (b *BytesClient) CONFIGREWRITE() error {
+	return b.c.CONFIGREWRITE()
+}
+
+// CONFIGSET is like Client CONFIGSET.
+func (b *BytesClient) CONFIGSET(names, values []string) error {
+	return b.c.CONFIGSET(names, values)
+}
+
+// Connect is like Client Connect.
+func (b *BytesClient) Connect() error {
+	return b.c.Connect()
+}
+
+// DELArgs is like Client DELArgs.
+func (b *BytesClient) DELArgs(m ...string) (int64, error) {
+	return b.c.DELArgs(m...)
+}
+
+// DEL is like Client DEL.
+func (b *BytesClient) DEL(k string) (bool, error) {
+	return b.c.DEL(k)
+}
+
+// DoArray is like Client DoArray.
+func (b *BytesClient) DoArray(r *Request) ([][]byte, error) {
+	return b.c.DoArray(r)
+}
+
+// DoArrayPooled is like Client DoArrayPooled.
+func (b *BytesClient) DoArrayPooled(r *Request) (*PooledArray, error) {
+	return b.c.DoArrayPooled(r)
+}
+
+// DoBulk is like Client DoBulk.
+func (b *BytesClient) DoBulk(r *Request) ([]byte, error) {
+	return b.c.DoBulk(r)
+}
+
+// DoBulkInto is like Client DoBulkInto.
+func (b *BytesClient) DoBulkInto(r *Request, buf []byte) (int, error) {
+	return b.c.DoBulkInto(r, buf)
+}
+
+// DoInteger is like Client DoInteger.
+func (b *BytesClient) DoInteger(r *Request) (int64, error) {
+	return b.c.DoInteger(r)
+}
+
+// DoMap is like Client DoMap.
+func (b *BytesClient) DoMap(r *Request) ([]string, [][]byte, error) {
+	return b.c.DoMap(r)
+}
+
+// DoOK is like Client DoOK.
+func (b *BytesClient) DoOK(r *Request) error {
+	return b.c.DoOK(r)
+}
+
+// DUMP is like Client DUMP.
+func (b *BytesClient) DUMP(k string) (serialized []byte, err error) {
+	return b.c.DUMP(k)
+}
+
+// ECHO is like Client ECHO.
+func (b *BytesClient) ECHO(message []byte) ([]byte, error) {
+	return b.c.ECHO(message)
+}
+
+// EXPIRE is like Client EXPIRE.
+func (b *BytesClient) EXPIRE(k string, seconds int64, flags uint) (bool, error) {
+	return b.c.EXPIRE(k, seconds, flags)
+}
+
+// EXPIREIf is like Client EXPIREIf.
+func (b *BytesClient) EXPIREIf(k string, d time.Duration, condition ExpireCondition) (bool, error) {
+	return b.c.EXPIREIf(k, d, condition)
+}
+
+// EXPIREWithDuration is like Client EXPIREWithDuration.
+func (b *BytesClient) EXPIREWithDuration(k string, d time.Duration, flags uint) (bool, error) {
+	return b.c.EXPIREWithDuration(k, d, flags)
+}
+
+// FLUSHALL is like Client FLUSHALL.
+func (b *BytesClient) FLUSHALL(async bool) error {
+	return b.c.FLUSHALL(async)
+}
+
+// FLUSHDB is like Client FLUSHDB.
+func (b *BytesClient) FLUSHDB(async bool) error {
+	return b.c.FLUSHDB(async)
+}
+
+// ForceReconnect is like Client ForceReconnect.
+func (b *BytesClient) ForceReconnect() error {
+	return b.c.ForceReconnect()
+}
+
+// FTAGGREGATE is like Client FTAGGREGATE.
+func (b *BytesClient) FTAGGREGATE(index, query string, o *AggregateOptions) (*AggregateResult, error) {
+	return b.c.FTAGGREGATE(index, query, o)
+}
+
+// FTCREATE is like Client FTCREATE.
+func (b *BytesClient) FTCREATE(index string, def SearchIndex) error {
+	return b.c.FTCREATE(index, def)
+}
+
+// FTDROPINDEX is like Client FTDROPINDEX.
+func (b *BytesClient) FTDROPINDEX(index string, deleteDocs bool) error {
+	return b.c.FTDROPINDEX(index, deleteDocs)
+}
+
+// FTSEARCH is like Client FTSEARCH.
+func (b *BytesClient) FTSEARCH(index, query string, o *SearchOptions) (*SearchResult, error) {
+	return b.c.FTSEARCH(index, query, o)
+}
+
+// GET is like Client GET.
+func (b *BytesClient) GET(k string) ([]byte, error) {
+	return b.c.GET(k)
+}
+
+// GETInto is like Client GETInto.
+func (b *BytesClient) GETInto(k string, buf []byte) (n int, err error) {
+	return b.c.GETInto(k, buf)
+}
+
+// GETOk is like Client GETOk.
+func (b *BytesClient) GETOk(k string) (v []byte, ok bool, err error) {
+	return b.c.GETOk(k)
+}
+
+// GETRANGE is like Client GETRANGE.
+func (b *BytesClient) GETRANGE(k string, start, end int64) ([]byte, error) {
+	return b.c.GETRANGE(k, start, end)
+}
+
+// GETReader is like Client GETReader.
+func (b *BytesClient) GETReader(k string) (*BulkReader, error) {
+	return b.c.GETReader(k)
+}
+
+// GETVisit is like Client GETVisit.
+func (b *BytesClient) GETVisit(k string, f VisitFunc) (ok bool, err error) {
+	return b.c.GETVisit(k, f)
+}
+
+// HDELArgs is like Client HDELArgs.
+func (b *BytesClient) HDELArgs(k string, mf ...string) (int64, error) {
+	return b.c.HDELArgs(k, mf...)
+}
+
+// HDEL is like Client HDEL.
+func (b *BytesClient) HDEL(k, f string) (bool, error) {
+	return b.c.HDEL(k, f)
+}
+
+// HGETALL is like Client HGETALL.
+func (b *BytesClient) HGETALL(k string) (fields []string, values [][]byte, err error) {
+	return b.c.HGETALL(k)
+}
+
+// HGETALLStruct is like Client HGETALLStruct.
+func (b *BytesClient) HGETALLStruct(k string, dst any) error {
+	return b.c.HGETALLStruct(k, dst)
+}
+
+// HGET is like Client HGET.
+func (b *BytesClient) HGET(k, f string) ([]byte, error) {
+	return b.c.HGET(k, f)
+}
+
+// HGETInto is like Client HGETInto.
+func (b *BytesClient) HGETInto(k, f string, buf []byte) (n int, err error) {
+	return b.c.HGETInto(k, f, buf)
+}
+
+// HGETOk is like Client HGETOk.
+func (b *BytesClient) HGETOk(k, f string) (v []byte, ok bool, err error) {
+	return b.c.HGETOk(k, f)
+}
+
+// HGETVisit is like Client HGETVisit.
+func (b *BytesClient) HGETVisit(k, field string, f VisitFunc) (ok bool, err error) {
+	return b.c.HGETVisit(k, field, f)
+}
+
+// HMGET is like Client HMGET.
+func (b *BytesClient) HMGET(k string, mf ...string) ([][]byte, error) {
+	return b.c.HMGET(k, mf...)
+}
+
+// HMGETOk is like Client HMGETOk.
+func (b *BytesClient) HMGETOk(k string, mf ...string) (values [][]byte, ok []bool, err error) {
+	return b.c.HMGETOk(k, mf...)
+}
+
+// HMGETPooled is like Client HMGETPooled.
+func (b *BytesClient) HMGETPooled(k string, mf ...string) (*PooledArray, error) {
+	return b.c.HMGETPooled(k, mf...)
+}
+
+// HMGETStruct is like Client HMGETStruct.
+func (b *BytesClient) HMGETStruct(k string, dst any) error {
+	return b.c.HMGETStruct(k, dst)
+}
+
+// HMSET is like Client HMSET.
+func (b *BytesClient) HMSET(k string, mf []string, mv [][]byte) error {
+	return b.c.HMSET(k, mf, mv)
+}
+
+// HSET is like Client HSET.
+func (b *BytesClient) HSET(k, f string, v []byte) (newField bool, err error) {
+	return b.c.HSET(k, f, v)
+}
+
+// HSETStruct is like Client HSETStruct.
+func (b *BytesClient) HSETStruct(k string, v any) (newFields int64, err error) {
+	return b.c.HSETStruct(k, v)
+}
+
+// INCRBY is like Client INCRBY.
+func (b *BytesClient) INCRBY(k string, increment int64) (newValue int64, err error) {
+	return b.c.INCRBY(k, increment)
+}
+
+// INCR is like Client INCR.
+func (b *BytesClient) INCR(k string) (newValue int64, err error) {
+	return b.c.INCR(k)
+}
+
+// INFO is like Client INFO.
+func (b *BytesClient) INFO(sections ...string) (*Info, error) {
+	return b.c.INFO(sections...)
+}
+
+// JSONDEL is like Client JSONDEL.
+func (b *BytesClient) JSONDEL(k string, path string) (int64, error) {
+	return b.c.JSONDEL(k, path)
+}
+
+// JSONGET is like Client JSONGET.
+func (b *BytesClient) JSONGET(k string, paths ...string) ([]byte, error) {
+	return b.c.JSONGET(k, paths...)
+}
+
+// JSONMGET is like Client JSONMGET.
+func (b *BytesClient) JSONMGET(path string, keys ...string) ([][]byte, error) {
+	return b.c.JSONMGET(path, keys...)
+}
+
+// JSONNUMINCRBY is like Client JSONNUMINCRBY.
+func (b *BytesClient) JSONNUMINCRBY(k string, path string, increment float64) ([]float64, error) {
+	return b.c.JSONNUMINCRBY(k, path, increment)
+}
+
+// JSONSETIf is like Client JSONSETIf.
+func (b *BytesClient) JSONSETIf(k string, path string, doc []byte, condition Exist) (bool, error) {
+	return b.c.JSONSETIf(k, path, doc, condition)
+}
+
+// JSONSET is like Client JSONSET.
+func (b *BytesClient) JSONSET(k string, path string, doc []byte, flags uint) (bool, error) {
+	return b.c.JSONSET(k, path, doc, flags)
+}
+
+// LINDEX is like Client LINDEX.
+func (b *BytesClient) LINDEX(k string, index int64) ([]byte, error) {
+	return b.c.LINDEX(k, index)
+}
+
+// LINDEXOk is like Client LINDEXOk.
+func (b *BytesClient) LINDEXOk(k string, index int64) (v []byte, ok bool, err error) {
+	return b.c.LINDEXOk(k, index)
+}
+
+// LLEN is like Client LLEN.
+func (b *BytesClient) LLEN(k string) (int64, error) {
+	return b.c.LLEN(k)
+}
+
+// LPOPInto is like Client LPOPInto.
+func (b *BytesClient) LPOPInto(k string, buf []byte) (n int, err error) {
+	return b.c.LPOPInto(k, buf)
+}
+
+// LPOP is like Client LPOP.
+func (b *BytesClient) LPOP(k string) ([]byte, error) {
+	return b.c.LPOP(k)
+}
+
+// LPOPOk is like Client LPOPOk.
+func (b *BytesClient) LPOPOk(k string) (v []byte, ok bool, err error) {
+	return b.c.LPOPOk(k)
+}
+
+// LPOPVisit is like Client LPOPVisit.
+func (b *BytesClient) LPOPVisit(k string, f VisitFunc) (ok bool, err error) {
+	return b.c.LPOPVisit(k, f)
+}
+
+// LPUSH is like Client LPUSH.
+func (b *BytesClient) LPUSH(k string, v []byte) (newLen int64, err error) {
+	return b.c.LPUSH(k, v)
+}
+
+// LRANGEEach is like Client LRANGEEach.
+func (b *BytesClient) LRANGEEach(k string, start, stop int64, f ElementFunc) error {
+	return b.c.LRANGEEach(k, start, stop, f)
+}
+
+// LRANGE is like Client LRANGE.
+func (b *BytesClient) LRANGE(k string, start, stop int64) ([][]byte, error) {
+	return b.c.LRANGE(k, start, stop)
+}
+
+// LRANGEPooled is like Client LRANGEPooled.
+func (b *BytesClient) LRANGEPooled(k string, start, stop int64) (*PooledArray, error) {
+	return b.c.LRANGEPooled(k, start, stop)
+}
+
+// LSET is like Client LSET.
+func (b *BytesClient) LSET(k string, index int64, value []byte) error {
+	return b.c.LSET(k, index, value)
+}
+
+// LTRIM is like Client LTRIM.
+func (b *BytesClient) LTRIM(k string, start, stop int64) error {
+	return b.c.LTRIM(k, start, stop)
+}
+
+// MEMORYDOCTOR is like Client MEMORYDOCTOR.
+func (b *BytesClient) MEMORYDOCTOR() (string, error) {
+	return b.c.MEMORYDOCTOR()
+}
+
+// MEMORYSTATS is like Client MEMORYSTATS.
+func (b *BytesClient) MEMORYSTATS() (*MemoryStats, error) {
+	return b.c.MEMORYSTATS()
+}
+
+// MEMORYUSAGE is like Client MEMORYUSAGE.
+func (b *BytesClient) MEMORYUSAGE(k string, samples int64) (int64, error) {
+	return b.c.MEMORYUSAGE(k, samples)
+}
+
+// MGETEach is like Client MGETEach.
+func (b *BytesClient) MGETEach(f ElementFunc, m ...string) error {
+	return b.c.MGETEach(f, m...)
+}
+
+// MGET is like Client MGET.
+func (b *BytesClient) MGET(m ...string) ([][]byte, error) {
+	return b.c.MGET(m...)
+}
+
+// MGETOk is like Client MGETOk.
+func (b *BytesClient) MGETOk(m ...string) (values [][]byte, ok []bool, err error) {
+	return b.c.MGETOk(m...)
+}
+
+// MGETPooled is like Client MGETPooled.
+func (b *BytesClient) MGETPooled(m ...string) (*PooledArray, error) {
+	return b.c.MGETPooled(m...)
+}
+
+// MOVE is like Client MOVE.
+func (b *BytesClient) MOVE(k string, db int64) (bool, error) {
+	return b.c.MOVE(k, db)
+}
+
+// MSET is like Client MSET.
+func (b *BytesClient) MSET(mk []string, mv [][]byte) error {
+	return b.c.MSET(mk, mv)
+}
+
+// NotifyKeyspaceEvents is like Client NotifyKeyspaceEvents.
+func (b *BytesClient) NotifyKeyspaceEvents(classes string) error {
+	return b.c.NotifyKeyspaceEvents(classes)
+}
+
+// PEXPIRE is like Client PEXPIRE.
+func (b *BytesClient) PEXPIRE(k string, milliseconds int64, flags uint) (bool, error) {
+	return b.c.PEXPIRE(k, milliseconds, flags)
+}
+
+// PING is like Client PING.
+func (b *BytesClient) PING() error {
+	return b.c.PING()
+}
+
+// PINGWithMessage is like Client PINGWithMessage.
+func (b *BytesClient) PINGWithMessage(message []byte) ([]byte, error) {
+	return b.c.PINGWithMessage(message)
+}
+
+// PTTL is like Client PTTL.
+func (b *BytesClient) PTTL(k string) (milliseconds int64, err error) {
+	return b.c.PTTL(k)
+}
+
+// PUBLISH is like Client PUBLISH.
+func (b *BytesClient) PUBLISH(channel string, message []byte) (clientCount int64, err error) {
+	return b.c.PUBLISH(channel, message)
+}
+
+// REPLICAOFNOONE is like Client REPLICAOFNOONE.
+func (b *BytesClient) REPLICAOFNOONE() error {
+	return b.c.REPLICAOFNOONE()
+}
+
+// REPLICAOF is like Client REPLICAOF.
+func (b *BytesClient) REPLICAOF(host string, port int64) error {
+	return b.c.REPLICAOF(host, port)
+}
+
+// RESET is like Client RESET.
+func (b *BytesClient) RESET() error {
+	return b.c.RESET()
+}
+
+// RESTORE is like Client RESTORE.
+func (b *BytesClient) RESTORE(k string, milliseconds int64, serialized []byte, replace bool) error {
+	return b.c.RESTORE(k, milliseconds, serialized, replace)
+}
+
+// RPOPOk is like Client RPOPOk.
+func (b *BytesClient) RPOPOk(k string) (v []byte, ok bool, err error) {
+	return b.c.RPOPOk(k)
+}
+
+// RPOP is like Client RPOP.
+func (b *BytesClient) RPOP(k string) ([]byte, error) {
+	return b.c.RPOP(k)
+}
+
+// RPUSH is like Client RPUSH.
+func (b *BytesClient) RPUSH(k string, v []byte) (newLen int64, err error) {
+	return b.c.RPUSH(k, v)
+}
+
+// SADDArgs is like Client SADDArgs.
+func (b *BytesClient) SADDArgs(k string, m ...string) (int64, error) {
+	return b.c.SADDArgs(k, m...)
+}
+
+// SADD is like Client SADD.
+func (b *BytesClient) SADD(k, m string) (bool, error) {
+	return b.c.SADD(k, m)
+}
+
+// SCAN is like Client SCAN.
+func (b *BytesClient) SCAN(cursor uint64, match string, count int64) (next uint64, keys []string, err error) {
+	return b.c.SCAN(cursor, match, count)
+}
+
+// SCARD is like Client SCARD.
+func (b *BytesClient) SCARD(k string) (int64, error) {
+	return b.c.SCARD(k)
+}
+
+// SELECT is like Client SELECT.
+func (b *BytesClient) SELECT(db int64) error {
+	return b.c.SELECT(db)
+}
+
+// SetAddr is like Client SetAddr.
+func (b *BytesClient) SetAddr(addr string) {
+	b.c.SetAddr(addr)
+}
+
+// SETGET is like Client SETGET.
+func (b *BytesClient) SETGET(k string, v []byte, o SETOptions) (previous []byte, ok bool, err error) {
+	return b.c.SETGET(k, v, o)
+}
+
+// SetPassword is like Client SetPassword.
+func (b *BytesClient) SetPassword(password []byte) {
+	b.c.SetPassword(password)
+}
+
+// SETReader is like Client SETReader.
+func (b *BytesClient) SETReader(k string, r io.Reader, size int64) error {
+	return b.c.SETReader(k, r, size)
+}
+
+// SET is like Client SET.
+func (b *BytesClient) SET(k string, v []byte) error {
+	return b.c.SET(k, v)
+}
+
+// SETWithOptions is like Client SETWithOptions.
+func (b *BytesClient) SETWithOptions(k string, v []byte, o SETOptions) (bool, error) {
+	return b.c.SETWithOptions(k, v, o)
+}
+
+// SINTER is like Client SINTER.
+func (b *BytesClient) SINTER(k ...string) ([][]byte, error) {
+	return b.c.SINTER(k...)
+}
+
+// SLOWLOGGET is like Client SLOWLOGGET.
+func (b *BytesClient) SLOWLOGGET(count int64) ([]SlowLogEntry, error) {
+	return b.c.SLOWLOGGET(count)
+}
+
+// SLOWLOGLEN is like Client SLOWLOGLEN.
+func (b *BytesClient) SLOWLOGLEN() (int64, error) {
+	return b.c.SLOWLOGLEN()
+}
+
+// SLOWLOGRESET is like Client SLOWLOGRESET.
+func (b *BytesClient) SLOWLOGRESET() error {
+	return b.c.SLOWLOGRESET()
+}
+
+// SMEMBERS is like Client SMEMBERS.
+func (b *BytesClient) SMEMBERS(k string) ([][]byte, error) {
+	return b.c.SMEMBERS(k)
+}
+
+// SREMArgs is like Client SREMArgs.
+func (b *BytesClient) SREMArgs(k string, m ...string) (int64, error) {
+	return b.c.SREMArgs(k, m...)
+}
+
+// SREM is like Client SREM.
+func (b *BytesClient) SREM(k, m string) (bool, error) {
+	return b.c.SREM(k, m)
+}
+
+// Stats is like Client Stats.
+func (b *BytesClient) Stats() Stats {
+	return b.c.Stats()
+}
+
+// STRLEN is like Client STRLEN.
+func (b *BytesClient) STRLEN(k string) (int64, error) {
+	return b.c.STRLEN(k)
+}
+
+// SUNION is like Client SUNION.
+func (b *BytesClient) SUNION(k ...string) ([][]byte, error) {
+	return b.c.SUNION(k...)
+}
+
+// SWAPDB is like Client SWAPDB.
+func (b *BytesClient) SWAPDB(db1, db2 int64) error {
+	return b.c.SWAPDB(db1, db2)
+}
+
+// TIME is like Client TIME.
+func (b *BytesClient) TIME() (time.Time, error) {
+	return b.c.TIME()
+}
+
+// TYPE is like Client TYPE.
+func (b *BytesClient) TYPE(k string) (string, error) {
+	return b.c.TYPE(k)
+}
+
+// UNLINKArgs is like Client UNLINKArgs.
+func (b *BytesClient) UNLINKArgs(m ...string) (int64, error) {
+	return b.c.UNLINKArgs(m...)
+}
+
+// UNLINKMatch is like Client UNLINKMatch.
+func (b *BytesClient) UNLINKMatch(pattern string, o UNLINKMatchOptions) (removed int64, err error) {
+	return b.c.UNLINKMatch(pattern, o)
+}
+
+// UNLINK is like Client UNLINK.
+func (b *BytesClient) UNLINK(k string) (bool, error) {
+	return b.c.UNLINK(k)
+}
+
+// WAIT is like Client WAIT.
+func (b *BytesClient) WAIT(numReplicas int64, timeout time.Duration) (int64, error) {
+	return b.c.WAIT(numReplicas, timeout)
+}
+
+// WithContext is like Client WithContext.
+func (b *BytesClient) WithContext(ctx context.Context) *BytesClient {
+	return &BytesClient{b.c.WithContext(ctx)}
+}
+
+// WithPrefix is like Client WithPrefix.
+func (b *BytesClient) WithPrefix(prefix string) *BytesClient {
+	return &BytesClient{b.c.WithPrefix(prefix)}
+}
+
+// ZADD is like Client ZADD.
+func (b *BytesClient) ZADD(k string, score float64, m []byte) (bool, error) {
+	return b.c.ZADD(k, score, m)
+}
+
+// ZRANGEWithScores is like Client ZRANGEWithScores.
+func (b *BytesClient) ZRANGEWithScores(k string, start, stop int64) (members [][]byte, scores []float64, err error) {
+	return b.c.ZRANGEWithScores(k, start, stop)
+}
+
+// ZRANGE is like Client ZRANGE.
+func (b *BytesClient) ZRANGE(k string, start, stop int64) ([][]byte, error) {
+	return b.c.ZRANGE(k, start, stop)
+}
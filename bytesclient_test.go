@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBytesClientComplete verifies that each Client method is available on
+// BytesClient, with the same signature.
+func TestBytesClientComplete(t *testing.T) {
+	client := reflect.TypeOf((*Client[string, []byte])(nil))
+	bytesClient := reflect.TypeOf((*BytesClient)(nil))
+	for i := 0; i < client.NumMethod(); i++ {
+		want := client.Method(i)
+		if want.Name == "Validate" {
+			continue // from ClientConfig
+		}
+		got, ok := bytesClient.MethodByName(want.Name)
+		if !ok {
+			t.Errorf("Client method %s missing in BytesClient", want.Name)
+			continue
+		}
+
+		wantType, gotType := want.Type, got.Type
+		if wantType.NumIn() != gotType.NumIn() || wantType.NumOut() != gotType.NumOut() || wantType.IsVariadic() != gotType.IsVariadic() {
+			t.Errorf("BytesClient method %s has signature %s, want %s", want.Name, gotType, wantType)
+			continue
+		}
+		for i := 1; i < wantType.NumIn(); i++ { // skip receiver
+			if wantType.In(i) != gotType.In(i) {
+				t.Errorf("BytesClient method %s argument %d is a %s, want %s", want.Name, i, gotType.In(i), wantType.In(i))
+			}
+		}
+		for i := 0; i < wantType.NumOut(); i++ {
+			w := wantType.Out(i)
+			if w == client {
+				w = bytesClient
+			}
+			if w != gotType.Out(i) {
+				t.Errorf("BytesClient method %s result %d is a %s, want %s", want.Name, i, gotType.Out(i), w)
+			}
+		}
+	}
+}
+
+func TestBytesClient(t *testing.T) {
+	t.Parallel()
+
+	c := NewBytesClient(testClient.ClientConfig)
+	defer c.Close()
+	if c.Config().Addr != testClient.Addr {
+		t.Errorf("got Addr %q, want %q", c.Config().Addr, testClient.Addr)
+	}
+
+	key := randomKey("bytes")
+	if err := c.SET(key, []byte{0, 1, 2}); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	got, err := c.WithPrefix("").GET(key)
+	if err != nil {
+		t.Fatal("GET error:", err)
+	}
+	if string(got) != "\x00\x01\x02" {
+		t.Errorf("GET got %q, want %q", got, "\x00\x01\x02")
+	}
+}
@@ -1,18 +1,74 @@
 package redis
 
 import (
-	"bufio"
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// DialDelayMax is the idle limit for automated reconnect attempts.
+// DialDelayMax is the default idle limit for automated reconnect attempts.
 // Sequential failure with connection establisment increases the retry
-// delay in steps from 0 to 500 ms.
+// delay in steps from 0 to 500 ms. See Backoff for custom schedules.
 const DialDelayMax = time.Second / 2
 
+// Backoff defines the delay schedule of automated reconnect attempts. The
+// delay starts at zero, and each sequential failure doubles the delay plus
+// Initial, up to Max. The zero value applies defaults to all fields.
+type Backoff struct {
+	// Initial is the increment on each delay. Zero defaults to 1 ms.
+	Initial time.Duration
+
+	// Max is the upper boundary for delays. Zero defaults to DialDelayMax.
+	Max time.Duration
+
+	// Jitter randomizes each delay with up to the fraction, e.g., 0.2 for
+	// any value from 80% to 120% of the delay. Zero disables jitter.
+	Jitter float64
+
+	// Attempts limits the number of sequential connect failures when
+	// nonzero. The connection establishment stops once exceeded, with the
+	// last connect error as a permanent state.
+	Attempts int
+}
+
+// Next returns the delay after delay.
+func (b *Backoff) next(delay time.Duration) time.Duration {
+	initial, max := b.Initial, b.Max
+	if initial == 0 {
+		initial = time.Millisecond
+	}
+	if max == 0 {
+		max = DialDelayMax
+	}
+	delay = 2*delay + initial
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// Randomize applies Jitter to delay.
+func (b *Backoff) randomize(delay time.Duration) time.Duration {
+	if b.Jitter == 0 || delay == 0 {
+		return delay
+	}
+	f := 1 + b.Jitter*(2*rand.Float64()-1)
+	return time.Duration(f * float64(delay))
+}
+
+// Exhausted returns whether the number of sequential failures reached Attempts.
+func (b *Backoff) exhausted(failures int) bool {
+	return b.Attempts != 0 && failures >= b.Attempts
+}
+
 // Fixed Settings
 const (
 	// Number of pending requests limit per network protocol.
@@ -23,18 +79,75 @@ const (
 // ErrConnLost signals connection loss on pending request.
 var errConnLost = errors.New("redis: connection lost while awaiting response")
 
+// ErrBreakerOpen rejects commands without any I/O, as the circuit breaker of
+// the Client went open. See ClientConfig BreakerThreshold for details.
+var ErrBreakerOpen = errors.New("redis: circuit breaker open")
+
+// ErrIdleTimeout is the cause of disconnects due ClientConfig IdleTimeout.
+var errIdleTimeout = errors.New("redis: connection idle timeout")
+
+// ErrReconnect is the cause of disconnects due ForceReconnect.
+var errReconnect = errors.New("redis: reconnect on request")
+
+// ErrLazyConnect marks the connection state before the first dial, with
+// ClientConfig LazyConnect.
+var errLazyConnect = errors.New("redis: connection not established yet")
+
 // ClientConfig defines a Client setup.
 type ClientConfig struct {
 	// The host defaults to localhost, and the port defaults to 6379.
 	// Thus, the empty string defaults to "localhost:6379". Use an
 	// absolute file path (e.g. "/var/run/redis.sock") for Unix
 	// domain sockets.
+	//
+	// Multiple addresses are separated by comma, as in "rds1.example.com,
+	// rds2.example.com:6380", for simple failover. Connection establishment
+	// tries each address in line, starting with the one last connected.
+	//
+	// Addresses in URL notation, as in "wss://gw.example.com/redis", pass
+	// to DialFunc as is, with the scheme as the network. Such transports,
+	// e.g., to a RESP-over-WebSocket gateway, enable js/wasm builds.
 	Addr string
 
+	// PoolSize is the number of connections when greater than one, each
+	// with a pipeline of its own. Commands are dispatched round-robin, such
+	// that slow commands stall only part of the load. SELECT and RESET
+	// apply to all connections. WAIT can not track the writes from other
+	// connections of the pool.
+	PoolSize int
+
+	// CoalesceWrites gathers small commands, which are submitted while
+	// other commands await their turn to write, into a single network
+	// write. The last command in line writes on behalf of those before it,
+	// such that no delay is added. High concurrency sees far fewer system
+	// calls as a result, with Stats Writes below CommandsSent.
+	CoalesceWrites bool
+
 	// Limit execution duration when nonzero. Expiry causes a reconnect
-	// to prevent stale connections and a net.Error with Timeout() true.
+	// to prevent stale connections and an ErrTimeout.
+	// Blocking commands get their timeout argument on top, and those that
+	// block indefinitely have no limit.
 	CommandTimeout time.Duration
 
+	// WriteTimeout limits the duration of request submission when nonzero,
+	// instead of CommandTimeout, such that stuck writes fail early, while
+	// slow commands keep their tolerance. Blocking commands are subject
+	// too. Expiry causes a reconnect and an ErrTimeout.
+	WriteTimeout time.Duration
+
+	// ReadTimeout limits the duration until reply reception when nonzero,
+	// instead of CommandTimeout. Blocking commands get their timeout
+	// argument on top, and those that block indefinitely have no limit.
+	// Expiry causes a reconnect and an ErrTimeout.
+	ReadTimeout time.Duration
+
+	// BlockingPoolSize is the number of dedicated connections for blocking
+	// commands, such as BLPOP, such that they never stall the pipeline.
+	// Connections launch on first use, and blocking commands await a free
+	// one when all are in use. Zero defaults to four. Negative values run
+	// blocking commands on the pipeline instead.
+	BlockingPoolSize int
+
 	// Limit the duration for network connection establishment. Expiry
 	// causes an abort plus retry. See net.Dialer Timeout for details.
 	// Zero defaults to one second.
@@ -44,11 +157,259 @@ type ClientConfig struct {
 	// connect attempt until the connection restores.
 	DialTimeout time.Duration
 
+	// LazyConnect defers connection establishment until the first command,
+	// or until Connect, for Clients which may never be used. The first
+	// command then blocks during the first dial attempt. DialClient ignores
+	// the setting.
+	LazyConnect bool
+
+	// KeepAlive is the interval for TCP keep-alive probes on the network
+	// connections. See net.Dialer KeepAlive for details. Zero applies the
+	// default of package net, and negative values disable keep-alives.
+	// DialFunc ignores the setting.
+	KeepAlive time.Duration
+
+	// Reconnect defines the delay schedule for connection establishment.
+	Reconnect Backoff
+
+	// Send a PING on idle connections every interval when nonzero. Broken
+	// connections are detected as such, and they are replaced before use.
+	// Each PING must complete within the interval. Health checks stop with
+	// Close.
+	PingInterval time.Duration
+
+	// IdleTimeout closes connections without any command for the duration
+	// when nonzero, such as for servers with a timeout setting, or for NAT
+	// gateways which drop idle flows silently. The next command reconnects,
+	// like it does with LazyConnect. A PingInterval below IdleTimeout keeps
+	// connections from expiring.
+	IdleTimeout time.Duration
+
+	// DialFunc establishes the network connections when not nil, e.g., for
+	// SSH tunnels, custom socket options, or network namespaces. Use the
+	// DialContext method for a custom net.Dialer. Network is "unix" for
+	// absolute file paths in Addr, the scheme for URLs, and "tcp" otherwise.
+	// The context expires with DialTimeout. Connection tuning is left to the
+	// function. Connections without deadline support, such as some
+	// WebSocket bridges, lose the timeouts.
+	DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// TLS when not nil. Client certificates, as required for mutual TLS,
+	// apply from the configuration, just like the RootCAs, and any custom
+	// verification with VerifyPeerCertificate or VerifyConnection, e.g.,
+	// for certificate pinning. ServerName, as used for both verification
+	// and SNI, defaults to the host from Addr.
+	TLS *tls.Config
+
 	// AUTH when not nil.
 	Password []byte
 
-	// SELECT when not zero.
+	// AUTH with an ACL user when not empty. Password must be set too.
+	// ACL requires Redis version 6 or later.
+	Username string
+
+	// CLIENT SETNAME when not empty. The name shows in CLIENT LIST and
+	// in the SLOWLOG, which helps to identify connections. Spaces are not
+	// permitted.
+	Name string
+
+	// SELECT when not zero. The Client SELECT method updates the value on
+	// success, such that reconnects continue with the same database.
 	DB int64
+
+	// HELLO 3 when set, which switches the connection to RESP3. RESP3
+	// requires Redis version 6 or later. The replies are decoded the same
+	// way as with RESP2. See <https://redis.io/docs/reference/protocol-spec>
+	// for the details.
+	RESP3 bool
+
+	// PushFunc receives the out-of-band messages from RESP3 connections,
+	// such as invalidation messages from client-side caching. Kind holds
+	// the type of push, e.g. "invalidate", with any nested aggregates
+	// of the message flattened into args. Push messages are consumed
+	// (and discarded when nil) in line with command replies, and as they
+	// arrive on idle connections. Slow or blocking receivers stall the
+	// pipeline.
+	PushFunc func(kind string, args [][]byte)
+
+	// OfflineWait holds commands for up to the duration when nonzero, in
+	// case of a connect failure, as they await the connection to restore.
+	// Commands are submitted as soon as the connection restores, and they
+	// fail with the connect error once the duration since submission, or
+	// their deadline, expires. The number of waiting commands is limited to
+	// the pipeline queue size, beyond which commands fail immediately.
+	// Zero fails commands with the connect error without waiting.
+	OfflineWait time.Duration
+
+	// RetryIdempotent submits read-only commands once more when the
+	// connection is lost before any of the reply is received. Commands
+	// with side effects are never retried, as they may have executed.
+	// The retry awaits the reconnect.
+	RetryIdempotent bool
+
+	// TransientRetryMax limits the duration of retries on transient server
+	// errors when nonzero. Commands which got LOADING, BUSY, TRYAGAIN,
+	// CLUSTERDOWN or MASTERDOWN are submitted again, with a delay which
+	// doubles from 1 ms up to DialDelayMax. The error applies once the limit
+	// or the command deadline would be exceeded. Requests with multiple
+	// commands, such as RESET with the connection settings, are not
+	// retried, and neither are streamed payloads, as with SETReader. See
+	// ServerError Transient.
+	TransientRetryMax time.Duration
+
+	// BreakerThreshold enables a circuit breaker when nonzero. After the
+	// number of consecutive command failures, server errors excluded,
+	// commands fail with ErrBreakerOpen for the BreakerCoolDown duration,
+	// without waiting on any connection. Commands pass again once the
+	// cool-down period expires, and the first failure after then opens the
+	// breaker again, until a command succeeds.
+	BreakerThreshold int
+
+	// BreakerCoolDown is the duration of an open circuit breaker. Zero
+	// defaults to one second.
+	BreakerCoolDown time.Duration
+
+	// CrossSlotCheck rejects commands with Keys from more than one hash
+	// slot with a *CrossSlotError, without sending any request. Commands
+	// with Key positions unknown to this package pass unchecked.
+	CrossSlotCheck bool
+
+	// SizeMax is the upper boundary for byte sizes, in both arguments and
+	// replies, for servers with a proto-max-bulk-len other than 512 MiB.
+	// Requests in excess fail without submission, and replies in excess
+	// fail the connection. Zero defaults to the package SizeMax.
+	SizeMax int64
+
+	// ElementMax is the upper boundary for element counts in replies.
+	// Replies in excess fail the connection. Zero defaults to the package
+	// ElementMax.
+	ElementMax int64
+
+	// BeforeCommandFunc is called before each command submission when not
+	// nil. Name has the first word of the command, e.g., "GET" or "CLIENT",
+	// and argCount the number of arguments that follow. Calls are made from
+	// the goroutine which invoked the command.
+	BeforeCommandFunc func(name string, argCount int)
+
+	// AfterCommandFunc is called on completion of each command when not nil,
+	// with the duration since submission and the error, if any. Null replies
+	// do not count as an error. Calls are made from the goroutine which
+	// invoked the command, before the command method returns. Commands which
+	// stopped waiting on a context are reported without the remainder.
+	AfterCommandFunc func(name string, argCount int, d time.Duration, err error)
+
+	// Trace receives the protocol exchange when not nil, for debugging
+	// purposes. Each line starts with the remote address, followed by ">"
+	// for request commands, or by "<" for reply headers. The arguments of
+	// AUTH are redacted. Bulk payloads in replies are omitted, and long
+	// arguments in requests are truncated. Tracing slows the client down.
+	Trace io.Writer
+
+	// ConnStateFunc receives each connection state change when not nil.
+	// Addr has the node address in use, or the empty string for the
+	// Reconnecting state. Err has the cause for both Disconnected and
+	// Reconnecting, and it is nil for Connected. Calls are sequential, and
+	// slow or blocking receivers delay connection establishment.
+	ConnStateFunc func(state ConnState, addr string, err error)
+
+	// OnConnect is called on each new connection when not nil, after the
+	// settings from ClientConfig are applied, and before the connection
+	// enters service, e.g., for CLIENT TRACKING or for assertions on the
+	// server configuration. An error rejects the connection, as a connect
+	// failure. The CommandTimeout, if any, applies to the setup as a whole.
+	// RESET does not repeat OnConnect.
+	OnConnect func(*ConnSetup) error
+}
+
+// Validate rejects configurations which can not work as intended, with an error
+// which names the offending field. DialClient returns such errors as is, and the
+// Clients from NewClient report them as a permanent connect failure. Validate
+// does not cover any server-side constraints, such as the number of databases.
+func (c *ClientConfig) Validate() error {
+	for _, d := range [...]struct {
+		name  string
+		value time.Duration
+	}{
+		{"CommandTimeout", c.CommandTimeout},
+		{"WriteTimeout", c.WriteTimeout},
+		{"ReadTimeout", c.ReadTimeout},
+		{"DialTimeout", c.DialTimeout},
+		{"IdleTimeout", c.IdleTimeout},
+		{"PingInterval", c.PingInterval},
+		{"OfflineWait", c.OfflineWait},
+		{"TransientRetryMax", c.TransientRetryMax},
+		{"BreakerCoolDown", c.BreakerCoolDown},
+		{"Reconnect Initial", c.Reconnect.Initial},
+		{"Reconnect Max", c.Reconnect.Max},
+	} {
+		if d.value < 0 {
+			return fmt.Errorf("redis: ClientConfig %s %s is negative", d.name, d.value)
+		}
+	}
+
+	for _, n := range [...]struct {
+		name  string
+		value int64
+	}{
+		{"PoolSize", int64(c.PoolSize)},
+		{"DB", atomic.LoadInt64(&c.DB)},
+		{"BreakerThreshold", int64(c.BreakerThreshold)},
+		{"SizeMax", c.SizeMax},
+		{"ElementMax", c.ElementMax},
+		{"Reconnect Attempts", int64(c.Reconnect.Attempts)},
+	} {
+		if n.value < 0 {
+			return fmt.Errorf("redis: ClientConfig %s %d is negative", n.name, n.value)
+		}
+	}
+
+	if c.Reconnect.Jitter < 0 || c.Reconnect.Jitter > 1 {
+		return fmt.Errorf("redis: ClientConfig Reconnect Jitter %g is not in range [0, 1]", c.Reconnect.Jitter)
+	}
+	if c.Username != "" && c.Password == nil {
+		return errors.New("redis: ClientConfig Username without Password")
+	}
+	if c.DialFunc == nil {
+		for _, addr := range strings.Split(c.Addr, ",") {
+			if addrScheme(addr) != "" {
+				return fmt.Errorf("redis: ClientConfig Addr %q requires a DialFunc", addr)
+			}
+		}
+	}
+	if c.PushFunc != nil && !c.RESP3 {
+		return errors.New("redis: ClientConfig PushFunc without RESP3, as push messages require RESP3")
+	}
+	return nil
+}
+
+// ConnState is a connection lifecycle event.
+type ConnState int
+
+// Connection States
+const (
+	// Connected is reported once a connection is ready for use, i.e.,
+	// after the connection settings from ClientConfig are applied.
+	Connected ConnState = iota + 1
+	// Disconnected is reported when a connection is lost, including
+	// on Close with ErrClosed as the cause.
+	Disconnected
+	// Reconnecting is reported when connection establishment failed,
+	// with another attempt pending.
+	Reconnecting
+)
+
+// String returns the constant name.
+func (state ConnState) String() string {
+	switch state {
+	case Connected:
+		return "Connected"
+	case Disconnected:
+		return "Disconnected"
+	case Reconnecting:
+		return "Reconnecting"
+	default:
+		return fmt.Sprintf("ConnState(%d)", int(state))
+	}
 }
 
 // Client manages a connection to a Redis node until Close. Broken connection
@@ -61,63 +422,448 @@ type Client[Key, Value String] struct {
 
 	noCopy noCopy
 
+	*pipeline // shared with views
+
+	// Context of the view, if any.
+	ctx context.Context
+
+	// Key namespace of the view, if any.
+	keyPrefix string
+
+	// Dedicated connections for blocking commands, if any, as shared
+	// with views.
+	blocking *blockingPool
+
+	// Pool of views with a pipeline each when PoolSize is greater than
+	// one, with the pipeline of the Client at index zero. Members have no
+	// members themselves.
+	members []*Client[Key, Value]
+
+	// Read distribution from NewReplicaClient, if any, as shared with
+	// views. Readers have a view on each replica, in order of the set.
+	replicas *replicaSet
+	readers  []*Client[Key, Value]
+}
+
+// Pipeline is the connection state of a Client, including its views.
+type pipeline struct {
+	// Statistics come first for 64-bit alignment of atomic access.
+	commandsSent  int64
+	connectCount  int64
+	latencySum    int64
+	latencyCounts [len(LatencyBuckets) + 1]int64
+	ioCounters
+
+	// Number of commands in awaitRestore.
+	offlineWaiting int64
+
+	// Number of network writes, and the number of commands awaiting the
+	// write lock when CoalesceWrites.
+	writeCount   int64
+	writeWaiting int64
+
+	// Circuit breaker state with the number of consecutive failures, and
+	// the expiry of an open breaker in Unix nanoseconds.
+	failureStreak int64
+	breakerUntil  int64
+
+	// Round-robin position for pool dispatch, after the 64-bit fields.
+	poolNext uint32
+
+	// Last connect failure, if any, as a *connectFailure.
+	lastConnectFailure atomic.Value
+
+	// The configuration of the Client that created the pipeline.
+	config *ClientConfig
+	// Addr and Password in use, as shared with the other pipelines of the
+	// Client.
+	target *dialTarget
+
 	// The connection semaphore is used as a write lock.
 	connSem chan *redisConn
 
-	// Requests are send with a redisConn. The buffering reader attached to
-	// a redisConn is used to read the response. Requests enqueue a callback
-	// channel to parse the response in pipeline order. A nil Reader receive
-	// implies connection loss.
+	// Requests are send with a redisConn. The read routine of a redisConn
+	// hands its buffering reader to each request in line, to parse the
+	// response in pipeline order. Requests enqueue a callback channel for
+	// the purpose. A nil Reader receive implies connection loss.
 	// Insertion must hold the write lock (connSem).
-	readQueue chan chan<- *bufio.Reader
+	readQueue chan chan<- *connReader
+
+	// Requests hand the buffering reader back to the read routine. An error
+	// halts the read routine, as the request drops the connection instead.
+	readReturn chan error
+
+	// A send/receive skips the retry delay of connectOrClosed.
+	redial chan struct{}
 
 	// A send/receive halts the read routine. The bufio.Reader is discarded.
-	// No more consumption on ReadQueue.
+	// No more consumption on ReadQueue. The connection must be closed first
+	// when readIdle, as the read routine blocks on a read then.
 	// Insertion must hold the write lock (connSem).
 	readTerm chan struct{}
+
+	// Position in ClientConfig Addr, owned by connectOrClosed.
+	addrIndex int
+
+	// Submission time of the command reading, owned by the reader holder.
+	// The zero value omits latency observation.
+	readSince time.Time
+	// Command of the read routine for AfterCommandFunc, if any.
+	readCommand  string
+	readArgCount int
 }
 
 // NewDefaultClient launches a managed connection to a node (address).
-// Both CommandTimeout and DialTimeout are set to one second.
-func NewDefaultClient[Key, Value String](addr string) *Client[Key, Value] {
-	return NewClient[Key, Value](ClientConfig{
+// Both CommandTimeout and DialTimeout are set to one second. Options apply
+// in order of appearance, on top of the defaults.
+func NewDefaultClient[Key, Value String](addr string, opts ...Option) *Client[Key, Value] {
+	config := ClientConfig{
 		Addr:           addr,
 		CommandTimeout: time.Second,
 		DialTimeout:    time.Second,
-	})
+	}
+	for _, o := range opts {
+		o(&config)
+	}
+	return NewClient[Key, Value](config)
 }
 
-// NewClient launches a managed connection to a node (address).
+// NewClient launches a managed connection to a node (address). LazyConnect
+// defers the launch until first use.
 func NewClient[Key, Value String](config ClientConfig) *Client[Key, Value] {
+	c := newClient[Key, Value](config)
+	for _, m := range c.pool() {
+		if c.LazyConnect {
+			m.connSem <- &redisConn{offline: errLazyConnect}
+		} else {
+			go m.connectOrClosed()
+		}
+	}
+	c.launchPingLoops()
+	return c
+}
+
+// Connect launches the connection establishment in case of LazyConnect, and
+// it awaits the first connect attempt. The return is the connect failure, if
+// any, while reconnects continue in the background, as with NewClient. Clients
+// without LazyConnect just await the first connect attempt, if pending.
+func (c *Client[Key, Value]) Connect() error {
+	var err error
+	for _, m := range c.pool() {
+		if connectErr := m.awaitConnect(); connectErr != nil && err == nil {
+			err = connectErr
+		}
+	}
+	for _, r := range c.readers {
+		r.Connect() // falls back to primary
+	}
+	return err
+}
+
+// SetAddr replaces the address(es) from ClientConfig Addr for any connection
+// establishment from then on, e.g., for DNS cutovers. The ClientConfig itself
+// remains as is. Connections in use remain until they are lost, or until
+// ForceReconnect. The update applies to views and pools as a whole. Replicas
+// of NewReplicaClient keep their address.
+func (c *Client[Key, Value]) SetAddr(addr string) {
+	addr = normalizeAddr(addr)
+	c.target.mutex.Lock()
+	c.target.addr = addr
+	c.target.mutex.Unlock()
+}
+
+// SetPassword replaces the password from ClientConfig Password for any
+// connection establishment from then on, e.g., for credential rotation. The
+// ClientConfig itself remains as is, and so does the Username. Connections in
+// use remain until they are lost, or until ForceReconnect. The update applies
+// to views and pools as a whole.
+func (c *Client[Key, Value]) SetPassword(password []byte) {
+	c.target.mutex.Lock()
+	c.target.password = password
+	c.target.mutex.Unlock()
+	for _, r := range c.readers {
+		r.SetPassword(password)
+	}
+}
+
+// ForceReconnect replaces each connection with a new one, such that updates
+// from SetAddr and SetPassword apply immediately. Pending commands complete on
+// the old connection first, while new commands await the new connection. The
+// return is the connect failure, if any, while reconnects continue in the
+// background. Dedicated connections for blocking commands are not replaced.
+func (c *Client[Key, Value]) ForceReconnect() error {
+	var err error
+	for _, m := range c.pool() {
+		if connectErr := m.reconnectPipeline(); connectErr != nil && err == nil {
+			err = connectErr
+		}
+	}
+	for _, r := range c.readers {
+		r.ForceReconnect() // falls back to primary
+	}
+	return err
+}
+
+func (c *Client[Key, Value]) reconnectPipeline() error {
+	conn := <-c.connSem // lock write
+	switch {
+	case conn.offline == errLazyConnect || conn.offline == ErrClosed:
+		c.connSem <- conn // unlock write
+		return c.awaitConnect()
+
+	case conn.offline != nil && conn.retried != nil:
+		// await next attempt, without delay
+		retried := conn.retried
+		c.connSem <- conn // unlock write
+		select {
+		case c.redial <- struct{}{}:
+			break
+		default:
+			break // pending already
+		}
+		<-retried
+		return c.awaitConnect()
+
+	case conn.offline != nil:
+		// permanent offline; launch again
+		if conn.restored != nil {
+			close(conn.restored) // release waiting commands
+		}
+		// write remains locked (until connectOrClosed)
+		go c.connectOrClosed()
+		return c.awaitConnect()
+	}
+
+	if conn.readIdle {
+		conn.Close() // unblocks read routine
+	}
+	// must hold write lock for insertion:
+	c.readTerm <- struct{}{}
+	// race unlikely yet possible
+	c.cancelQueue()
+	conn.Close()
+	c.connState(Disconnected, c.addrIndex, errReconnect)
+
+	// write remains locked (until connectOrClosed)
+	go c.connectOrClosed()
+	return c.awaitConnect()
+}
+
+func (c *Client[Key, Value]) awaitConnect() error {
+	conn := <-c.connSem // lock write
+	if conn.offline == errLazyConnect {
+		go c.connectOrClosed()
+		conn = <-c.connSem // first attempt done
+	}
+	c.connSem <- conn // unlock write
+	return conn.offline
+}
+
+// DialClient establishes a connection to a node (address) before it returns.
+// Any failure on the initial connect, including authentication, is returned
+// as is, i.e., without launch. From then on, the Client behaves the same as
+// one from NewClient, with automated reconnects.
+func DialClient[Key, Value String](config ClientConfig) (*Client[Key, Value], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	c := newClient[Key, Value](config)
+	pool := c.pool()
+	for i, m := range pool {
+		conn, reader, err := c.config.connect(c.target, conservativeMSS, &m.addrIndex, &m.ioCounters)
+		if err != nil {
+			for _, connected := range pool[:i] {
+				connected.Close()
+			}
+			return nil, err
+		}
+		m.connectCount = 1
+		c.connState(Connected, m.addrIndex, nil)
+		m.launch(conn, reader)
+	}
+	c.launchPingLoops()
+	return c, nil
+}
+
+func newClient[Key, Value String](config ClientConfig) *Client[Key, Value] {
 	config.Addr = normalizeAddr(config.Addr)
 	if config.DialTimeout == 0 {
 		config.DialTimeout = time.Second
 	}
+	if config.BreakerCoolDown == 0 {
+		config.BreakerCoolDown = time.Second
+	}
+	if config.SizeMax == 0 {
+		config.SizeMax = SizeMax
+	}
+	if config.ElementMax == 0 {
+		config.ElementMax = ElementMax
+	}
 
 	queueSize := queueSizeTCP
-	if isUnixAddr(config.Addr) {
+	// queue remains on failover to other addresses
+	if isUnixAddr(config.Addr) && !strings.Contains(config.Addr, ",") {
 		queueSize = queueSizeUnix
 	}
 
-	c := &Client[Key, Value]{
-		ClientConfig: config,
+	c := &Client[Key, Value]{ClientConfig: config}
+	target := &dialTarget{addr: config.Addr, password: config.Password}
+	c.pipeline = newPipeline(&c.ClientConfig, target, queueSize)
+	if config.BlockingPoolSize == 0 {
+		c.BlockingPoolSize = 4
+	}
+	if c.BlockingPoolSize > 0 {
+		c.blocking = newBlockingPool(c.BlockingPoolSize)
+	}
+	if config.PoolSize > 1 {
+		c.members = make([]*Client[Key, Value], config.PoolSize)
+		c.members[0] = &Client[Key, Value]{
+			ClientConfig: config,
+			pipeline:     c.pipeline,
+		}
+		for i := 1; i < len(c.members); i++ {
+			c.members[i] = &Client[Key, Value]{
+				ClientConfig: config,
+				pipeline:     newPipeline(&c.ClientConfig, target, queueSize),
+			}
+		}
+	}
+	return c
+}
+
+// LaunchPingLoops starts a pingLoop per pipeline when PingInterval. The
+// Client must be in service, such that Close halts each loop.
+func (c *Client[Key, Value]) launchPingLoops() {
+	if c.PingInterval <= 0 {
+		return
+	}
+	for _, m := range c.pool() {
+		go (&Client[Key, Value]{
+			ClientConfig: c.ClientConfig,
+			pipeline:     m.pipeline,
+			ctx:          context.Background(),
+		}).pingLoop()
+	}
+}
+
+// DialTarget has the Addr and the Password from the configuration, as replaced
+// by SetAddr and SetPassword, without any change to the configuration.
+type dialTarget struct {
+	mutex    sync.Mutex
+	addr     string
+	password []byte
+}
+
+func (t *dialTarget) load() (addr string, password []byte) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.addr, t.password
+}
 
-		connSem:   make(chan *redisConn, 1),
-		readQueue: make(chan chan<- *bufio.Reader, queueSize),
-		readTerm:  make(chan struct{}),
+func newPipeline(config *ClientConfig, target *dialTarget, queueSize int) *pipeline {
+	return &pipeline{
+		config:     config,
+		target:     target,
+		connSem:    make(chan *redisConn, 1),
+		readQueue:  make(chan chan<- *connReader, queueSize),
+		readReturn: make(chan error, 1),
+		readTerm:   make(chan struct{}),
+		redial:     make(chan struct{}, 1),
 	}
+}
 
-	go c.connectOrClosed()
+// Pool returns each Client with a pipeline, which is just c without PoolSize.
+func (c *Client[Key, Value]) pool() []*Client[Key, Value] {
+	if c.members == nil {
+		return []*Client[Key, Value]{c}
+	}
+	return c.members
+}
 
-	return c
+// Member returns the Client for the next command, which is just c without
+// PoolSize.
+func (c *Client[Key, Value]) member() *Client[Key, Value] {
+	if c.members == nil {
+		return c
+	}
+	i := atomic.AddUint32(&c.poolNext, 1)
+	return c.members[i%uint32(len(c.members))]
+}
+
+// WithContext returns a view of c which applies ctx to each command. Command
+// submission fails with the context error once ctx is done. Commands which
+// await their turn in the pipeline return early with the context error, while
+// their reply is discarded in the background. The deadline of ctx, if any,
+// limits the execution duration the same way CommandTimeout does, whichever
+// expires first. Views share the connection with c, including Close.
+// The ClientConfig of a view is a copy. WithContext panics on a nil context.
+func (c *Client[Key, Value]) WithContext(ctx context.Context) *Client[Key, Value] {
+	if ctx == nil {
+		panic("redis: nil context")
+	}
+	return c.view(ctx, c.keyPrefix)
 }
 
 type redisConn struct {
 	net.Conn       // nil when offline
 	offline  error // reason for connection absence
 
-	// The token is nil when a read routine is using it.
-	idle *bufio.Reader
+	// The read routine awaits data without any command in line when set.
+	// The flag is owned by the write lock.
+	readIdle bool
+
+	// Closed once the connection restores, or on Close, when offline due
+	// a connect failure.
+	restored chan struct{}
+
+	// Closed once the next connect attempt completes, or on Close, when
+	// offline due a connect failure. Nil when no more attempts follow.
+	retried chan struct{}
+
+	// Deadlines are cleared when a command has none. The write
+	// flag is owned by the write lock, and the read flag by the
+	// holder of the buffering reader, which is the write lock
+	// when readIdle.
+	writeDeadline, readDeadline bool
+
+	// Commands not written yet, with CoalesceWrites only. The buffer
+	// is owned by the write lock.
+	pending []byte
+}
+
+// CoalesceMax is the request size limit for ClientConfig CoalesceWrites, and
+// the number of pending bytes which causes a write regardless.
+const coalesceMax = 16 * conservativeMSS
+
+// Send writes req on conn, either directly or pending, with the write lock.
+func (c *Client[Key, Value]) send(conn *redisConn, req *request) error {
+	if c.CoalesceWrites && !conn.readIdle && req.body == nil && len(req.buf) <= coalesceMax {
+		conn.pending = append(conn.pending, req.buf...)
+		if len(conn.pending) < coalesceMax && atomic.LoadInt64(&c.writeWaiting) != 0 {
+			return nil // next in line writes
+		}
+		return c.flush(conn)
+	}
+
+	if len(conn.pending) != 0 {
+		if err := c.flush(conn); err != nil {
+			return err
+		}
+	}
+	atomic.AddInt64(&c.writeCount, 1)
+	_, err := conn.Write(req.buf)
+	if err == nil && req.body != nil {
+		err = writeBody(conn, req)
+	}
+	return err
+}
+
+// Flush writes any pending commands, with the write lock.
+func (c *Client[Key, Value]) flush(conn *redisConn) error {
+	atomic.AddInt64(&c.writeCount, 1)
+	_, err := conn.Write(conn.pending)
+	conn.pending = conn.pending[:0]
+	return err
 }
 
 // Close terminates the connection establishment.
@@ -125,6 +871,25 @@ type redisConn struct {
 // All pending commands are dealt with on return.
 // Calling Close more than once has no effect.
 func (c *Client[Key, Value]) Close() error {
+	if c.blocking != nil {
+		c.blocking.close()
+	}
+
+	var err error
+	for _, m := range c.pool() {
+		if closeErr := m.closePipeline(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	for _, r := range c.readers {
+		if closeErr := r.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (c *pipeline) closePipeline() error {
 	conn := <-c.connSem // lock write
 	if conn.offline == ErrClosed {
 		// redundant invocation
@@ -132,30 +897,54 @@ func (c *Client[Key, Value]) Close() error {
 		return nil
 	}
 
-	if conn.offline == nil && conn.idle == nil {
+	var err error
+	if conn.offline == nil {
+		if conn.readIdle {
+			err = conn.Close() // unblocks read routine
+		}
 		// must hold write lock for insertion:
 		c.readTerm <- struct{}{}
 		// race unlikely yet possible
 		c.cancelQueue()
+		if !conn.readIdle {
+			err = conn.Close()
+		}
+	}
+
+	if conn.restored != nil {
+		close(conn.restored) // release waiting commands
+	}
+	if conn.retried != nil {
+		close(conn.retried) // release ForceReconnect
 	}
 
 	// stop command submission (unlocks write)
 	c.connSem <- &redisConn{offline: ErrClosed}
 
 	if conn.Conn != nil {
-		return conn.Close()
+		c.connState(Disconnected, c.addrIndex, ErrClosed)
 	}
-	return nil
+	return err
 }
 
 // connectOrClosed populates the connection semaphore.
 func (c *Client[Key, Value]) connectOrClosed() {
+	if err := c.config.Validate(); err != nil {
+		c.lastConnectFailure.Store(&connectFailure{err, time.Now()})
+		// permanent offline
+		c.connSem <- &redisConn{offline: err}
+		c.connState(Disconnected, -1, err)
+		return
+	}
+
 	var retryDelay time.Duration
-	for {
-		conn, reader, err := c.connect(conservativeMSS)
+	for failures := 1; ; failures++ {
+		conn, reader, err := c.config.connect(c.target, conservativeMSS, &c.addrIndex, &c.ioCounters)
 		if err != nil {
-			retry := time.NewTimer(retryDelay)
+			retry := time.NewTimer(c.Reconnect.randomize(retryDelay))
 
+			restored := make(chan struct{})
+			var retried chan struct{}
 			// remove previous connect error unless closed
 			if retryDelay != 0 {
 				current := <-c.connSem
@@ -164,15 +953,37 @@ func (c *Client[Key, Value]) connectOrClosed() {
 					retry.Stop()         // cleanup
 					return               // abandon
 				}
+				restored = current.restored
+				retried = current.retried
 			}
+			c.lastConnectFailure.Store(&connectFailure{err, time.Now()})
+			exhausted := c.Reconnect.exhausted(failures)
 			// propagate current connect error
-			c.connSem <- &redisConn{offline: fmt.Errorf("redis: offline due %w", err)}
-
-			retryDelay = 2*retryDelay + time.Millisecond
-			if retryDelay > DialDelayMax {
-				retryDelay = DialDelayMax
+			offline := &redisConn{
+				offline:  fmt.Errorf("redis: offline due %w", err),
+				restored: restored,
+			}
+			if !exhausted {
+				offline.retried = make(chan struct{})
+			}
+			c.connSem <- offline
+			if retried != nil {
+				close(retried)
+			}
+			if exhausted {
+				retry.Stop()
+				c.connState(Disconnected, -1, err)
+				return // permanent offline
+			}
+			c.connState(Reconnecting, -1, err)
+
+			retryDelay = c.Reconnect.next(retryDelay)
+			select {
+			case <-retry.C:
+				break
+			case <-c.redial:
+				retry.Stop()
 			}
-			<-retry.C
 			continue
 		}
 
@@ -184,20 +995,60 @@ func (c *Client[Key, Value]) connectOrClosed() {
 				conn.Close()         // discard
 				return               // abandon
 			}
+			defer close(current.restored)
+			defer close(current.retried)
 		}
 
-		// release
-		c.connSem <- &redisConn{Conn: conn, idle: reader}
+		atomic.AddInt64(&c.connectCount, 1)
+		c.connState(Connected, c.addrIndex, nil)
+		c.launch(conn, reader)
 		return
 	}
 }
 
-func (c *Client[Key, Value]) cancelQueue() {
+// Launch starts a read routine on conn, and it releases the write lock with
+// conn.
+func (c *Client[Key, Value]) launch(conn net.Conn, reader *connReader) {
+	rc := &redisConn{Conn: conn}
+	go c.readRoutine(rc, reader)
+	c.connSem <- rc
+}
+
+// PingLoop checks the connection every PingInterval until Close. The receiver
+// must be a view, as the context is replaced on each PING.
+func (c *Client[Key, Value]) pingLoop() {
+	ticker := time.NewTicker(c.PingInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		select {
+		case conn := <-c.connSem:
+			// write locked
+			closed := conn.offline == ErrClosed
+			idle := conn.offline == nil && conn.readIdle
+			c.connSem <- conn // unlock write
+			if closed {
+				return
+			}
+			if !idle {
+				continue
+			}
+		default:
+			continue // busy
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.PingInterval)
+		c.ctx = ctx
+		c.PING() // any I/O error causes a reconnect
+		cancel()
+	}
+}
+
+func (c *pipeline) cancelQueue() {
 	for {
 		select {
 		case ch := <-c.readQueue:
 			// signal connection loss
-			ch <- (*bufio.Reader)(nil)
+			ch <- (*connReader)(nil)
 		default:
 			return
 		}
@@ -206,133 +1057,565 @@ func (c *Client[Key, Value]) cancelQueue() {
 
 // Exchange sends a request, and then it awaits its turn (in the pipeline) for
 // response receiption.
-func (c *Client[Key, Value]) exchange(req *request) (*bufio.Reader, error) {
-	conn := <-c.connSem // lock write
+func (c *Client[Key, Value]) exchange(req *request) (_ *connReader, err error) {
+	start := time.Now()
 
-	// validate connection state
-	if err := conn.offline; err != nil {
-		c.connSem <- conn // unlock write
-		return nil, err
+	if c.keyPrefix != "" && !req.prefixed {
+		buf, err := appendPrefixKeys(make([]byte, 0, len(req.buf)+64), req.buf, c.keyPrefix)
+		if err != nil {
+			return nil, err
+		}
+		req.buf = buf
+	}
+	if c.CrossSlotCheck {
+		if err := checkSlots(req.buf); err != nil {
+			return nil, err
+		}
+	}
+	if int64(len(req.buf)) > c.SizeMax {
+		if err := checkArgSizes(req.buf, c.SizeMax); err != nil {
+			return nil, err
+		}
+	}
+	if req.body != nil && req.bodySize > c.SizeMax {
+		return nil, fmt.Errorf("redis: %d-byte argument exceeds SizeMax", req.bodySize)
 	}
 
-	// apply time-out if set
-	var deadline time.Time
-	if c.CommandTimeout != 0 {
-		deadline = time.Now().Add(c.CommandTimeout)
-		conn.SetWriteDeadline(deadline)
+	var command string
+	var argCount int
+	if c.BeforeCommandFunc != nil || c.AfterCommandFunc != nil {
+		command, argCount = commandName(req.buf)
+		if c.BeforeCommandFunc != nil {
+			c.BeforeCommandFunc(command, argCount)
+		}
+		if c.AfterCommandFunc != nil {
+			defer func() {
+				if err != nil {
+					c.AfterCommandFunc(command, argCount, time.Since(start), err)
+				}
+			}()
+		}
 	}
 
-	// send command
-	if _, err := conn.Write(req.buf); err != nil {
-		// write remains locked (until connectOrClosed)
-		go func() {
-			if conn.idle == nil {
-				// read routine running
-				// must hold write lock for insertion:
-				c.readTerm <- struct{}{}
-				c.cancelQueue()
+	if c.BreakerThreshold != 0 {
+		if atomic.LoadInt64(&c.breakerUntil) > start.UnixNano() {
+			return nil, ErrBreakerOpen
+		}
+		defer func() {
+			if err != nil && (c.ctx == nil || c.ctx.Err() == nil) {
+				c.breakerResult(err)
 			}
-			conn.Close()
-			c.connectOrClosed()
 		}()
-		return nil, err
 	}
 
-	reader := conn.idle
-	if reader != nil {
-		// clear idle state; we're the read routine now
-		conn.idle = nil
-		// receive channel not used as first in line
-		req.free()
-	} else {
-		// read routine is running; wait in line
-		// must hold write lock for insertion:
-		c.readQueue <- req.receive
+	// apply time-out if set
+	var timeout, deadline, writeDeadline time.Time
+	readTimeout := c.CommandTimeout
+	if c.ReadTimeout != 0 {
+		readTimeout = c.ReadTimeout
 	}
-
-	c.connSem <- conn // unlock write
-
-	if reader == nil {
-		// await response turn in pipeline
-		reader = <-req.receive
-		req.free()
-		if reader == nil {
-			// queue abandonment
-			return nil, errConnLost
-		}
+	if readTimeout != 0 && req.block >= 0 {
+		timeout = start.Add(readTimeout + req.block)
 	}
-
-	if !deadline.IsZero() {
-		conn.SetReadDeadline(deadline)
+	if c.WriteTimeout != 0 {
+		writeDeadline = start.Add(c.WriteTimeout)
+	} else if c.CommandTimeout != 0 && req.block >= 0 {
+		writeDeadline = start.Add(c.CommandTimeout + req.block)
+	}
+	deadline = timeout
+	if c.ctx != nil {
+		if err := c.ctx.Err(); err != nil {
+			return nil, err
+		}
+		d, ok := c.ctx.Deadline()
+		if ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+		if ok && (writeDeadline.IsZero() || d.Before(writeDeadline)) {
+			writeDeadline = d
+		}
 	}
 
-	return reader, nil
-}
+	var reader *connReader
+	var transientDelay time.Duration
+	for {
+		if c.CoalesceWrites {
+			atomic.AddInt64(&c.writeWaiting, 1)
+		}
+		conn := <-c.connSem // lock write
+		if c.CoalesceWrites {
+			atomic.AddInt64(&c.writeWaiting, -1)
+		}
 
-func (c *Client[Key, Value]) commandOK(req *request) error {
-	r, err := c.exchange(req)
-	if err != nil {
+		// validate connection state
+		if err := conn.offline; err != nil {
+			if err == errLazyConnect {
+				// write remains locked (until connectOrClosed)
+				go c.connectOrClosed()
+				continue
+			}
+			c.connSem <- conn // unlock write
+			if conn.restored == nil || c.OfflineWait == 0 {
+				return nil, err
+			}
+			if err := c.awaitRestore(conn.restored, err, start, deadline); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !writeDeadline.IsZero() || conn.writeDeadline {
+			conn.SetWriteDeadline(writeDeadline)
+			conn.writeDeadline = !writeDeadline.IsZero()
+		}
+
+		// send command
+		if err := c.send(conn, req); err != nil {
+			// write remains locked (until connectOrClosed)
+			go func() {
+				if conn.readIdle || c.CoalesceWrites {
+					// The read routine blocks on a read,
+					// possibly for lost pending commands.
+					conn.Close()
+				}
+				// must hold write lock for insertion:
+				c.readTerm <- struct{}{}
+				c.cancelQueue()
+				conn.Close()
+				c.connState(Disconnected, c.addrIndex, err)
+				c.connectOrClosed()
+			}()
+			if c.retryOnce(req) {
+				continue
+			}
+			return nil, err
+		}
+
+		atomic.AddInt64(&c.commandsSent, 1)
+
+		// wait in line
+		// must hold write lock for insertion:
+		c.readQueue <- req.receive
+		// The read routine of an idle connection reads
+		// the response, so it gets the deadline instead.
+		first := conn.readIdle
+		if first {
+			conn.readIdle = false
+			if !deadline.IsZero() || conn.readDeadline {
+				conn.SetReadDeadline(deadline)
+				conn.readDeadline = !deadline.IsZero()
+			}
+		}
+
+		c.connSem <- conn // unlock write
+
+		// await response turn in pipeline
+		if c.ctx == nil || first {
+			reader = <-req.receive
+		} else {
+			select {
+			case reader = <-req.receive:
+				break
+			case <-c.ctx.Done():
+				go c.abandonReplies(req, conn, timeout)
+				return nil, c.ctx.Err()
+			}
+		}
+		if reader == nil {
+			// queue abandonment
+			if c.retryOnce(req) {
+				continue
+			}
+			req.free()
+			return nil, errConnLost
+		}
+
+		if !deadline.IsZero() || conn.readDeadline {
+			conn.SetReadDeadline(deadline)
+			conn.readDeadline = !deadline.IsZero()
+		}
+
+		if c.RESP3 {
+			if err := c.routePushes(reader); err != nil {
+				c.passRead(err)
+				if c.retryOnce(req) {
+					continue
+				}
+				req.free()
+				return nil, err
+			}
+		}
+
+		if req.retry && c.RetryIdempotent {
+			// Connection loss shows on the first read typically.
+			// Nothing of the reply is consumed on error.
+			if _, err := reader.Peek(1); err != nil {
+				c.passRead(err)
+				if c.retryOnce(req) {
+					continue
+				}
+				req.free()
+				return nil, err
+			}
+		}
+
+		// retry would desync on the replies of any other commands, and
+		// streamed payloads can not be sent again
+		if c.TransientRetryMax != 0 && req.body == nil && countCommands(req.buf) == 1 {
+			if e, ok := readTransient(reader); ok {
+				c.passRead(e)
+
+				transientDelay = 2*transientDelay + time.Millisecond
+				if transientDelay > DialDelayMax {
+					transientDelay = DialDelayMax
+				}
+				if !c.awaitTransientRetry(start, deadline, transientDelay) {
+					req.free()
+					return nil, e
+				}
+				continue
+			}
+		}
+
+		break
+	}
+	req.free()
+
+	c.readSince = start
+	c.readCommand = command
+	c.readArgCount = argCount
+	return reader, nil
+}
+
+// WriteBody sends the payload of a streaming request. Errors from the body leave
+// the connection in an undefined state, just like write errors do.
+func writeBody(w io.Writer, req *request) error {
+	n, err := io.CopyN(w, req.body, req.bodySize)
+	if err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("redis: body ended after %d out of %d bytes", n, req.bodySize)
+		}
+		return err
+	}
+	_, err = w.Write([]byte{'\r', '\n'})
+	return err
+}
+
+// RetryOnce returns whether req should be submitted again, which is true at
+// most once, for requests marked as retry only, and only with RetryIdempotent.
+func (c *Client[Key, Value]) retryOnce(req *request) bool {
+	if !req.retry || !c.RetryIdempotent {
+		return false
+	}
+	req.retry = false
+	return true
+}
+
+// BreakerResult updates the circuit breaker with the outcome of a command.
+func (c *Client[Key, Value]) breakerResult(err error) {
+	if _, ok := err.(ServerError); err == nil || err == errNull || ok {
+		atomic.StoreInt64(&c.failureStreak, 0)
+		return
+	}
+	if atomic.AddInt64(&c.failureStreak, 1) >= int64(c.BreakerThreshold) {
+		until := time.Now().Add(c.BreakerCoolDown).UnixNano()
+		atomic.StoreInt64(&c.breakerUntil, until)
+	}
+}
+
+// AwaitRestore blocks until the connection restores, or until OfflineWait since
+// start expires, whichever comes first. The deadline applies when set. The
+// offline error is returned on expiry.
+func (c *Client[Key, Value]) awaitRestore(restored <-chan struct{}, offline error, start, deadline time.Time) error {
+	if atomic.AddInt64(&c.offlineWaiting, 1) > int64(cap(c.readQueue)) {
+		atomic.AddInt64(&c.offlineWaiting, -1)
+		return offline
+	}
+	defer atomic.AddInt64(&c.offlineWaiting, -1)
+
+	expire := start.Add(c.OfflineWait)
+	if !deadline.IsZero() && deadline.Before(expire) {
+		expire = deadline
+	}
+	timer := time.NewTimer(time.Until(expire))
+	defer timer.Stop()
+
+	var done <-chan struct{}
+	if c.ctx != nil {
+		done = c.ctx.Done()
+	}
+	select {
+	case <-restored:
+		return nil
+	case <-timer.C:
+		return offline
+	case <-done:
+		return c.ctx.Err()
+	}
+}
+
+// AwaitTransientRetry sleeps for delay, and it returns whether a retry is due.
+// The retry must not exceed TransientRetryMax since start, nor the deadline
+// when set.
+func (c *Client[Key, Value]) awaitTransientRetry(start, deadline time.Time, delay time.Duration) bool {
+	next := time.Now().Add(delay)
+	if next.After(start.Add(c.TransientRetryMax)) {
+		return false
+	}
+	if !deadline.IsZero() && next.After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	if c.ctx == nil {
+		<-timer.C
+		return true
+	}
+	select {
+	case <-timer.C:
+		return true
+	case <-c.ctx.Done():
+		timer.Stop()
+		return false
+	}
+}
+
+// AbandonReplies consumes the replies of req once it is their turn in the
+// pipeline, on behalf of a command which stopped waiting. The deadline is
+// without any context.
+func (c *Client[Key, Value]) abandonReplies(req *request, conn *redisConn, deadline time.Time) {
+	reader := <-req.receive
+	n := countCommands(req.buf)
+	req.free()
+	if reader == nil {
+		return // queue abandonment
+	}
+
+	if !deadline.IsZero() || conn.readDeadline {
+		conn.SetReadDeadline(deadline)
+		conn.readDeadline = !deadline.IsZero()
+	}
+
+	for ; n > 0; n-- {
+		if c.RESP3 {
+			if err := c.routePushes(reader); err != nil {
+				c.passRead(err)
+				return
+			}
+		}
+		err := discardReply(reader)
+		if _, ok := err.(ServerError); err != nil && !ok {
+			c.passRead(err)
+			return
+		}
+	}
+	c.passRead(nil)
+}
+
+// RoutePushes consumes any RESP3 push messages in line, which may precede the
+// reply.
+func (c *Client[Key, Value]) routePushes(r *connReader) error {
+	for {
+		head, err := r.Peek(1)
+		if err != nil {
+			return err
+		}
+		if head[0] != '>' {
+			return nil
+		}
+
+		kind, args, err := readPush(r)
+		if err != nil {
+			return fmt.Errorf("redis: push message: %w", err)
+		}
+		if c.PushFunc != nil {
+			c.PushFunc(kind, args)
+		}
+	}
+}
+
+func (c *Client[Key, Value]) commandOK(req *request) error {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
 		return err
 	}
 	err = readOK(r)
-	c.passRead(r, err)
+	c.passRead(err)
 	return err
 }
 
 func (c *Client[Key, Value]) commandOKOrReconnect(req *request) error {
+	c = c.route(req)
 	r, err := c.exchange(req)
 	if err != nil {
 		return err
 	}
 	err = readOK(r)
 	if err != nil {
-		c.dropConnFromRead()
+		c.readDone(err)
+		c.readReturn <- err // halt read routine
+		c.dropConnFromRead(err)
 	} else {
-		c.passRead(r, nil)
+		c.passRead(nil)
 	}
 	return err
 }
 
 func (c *Client[Key, Value]) commandInteger(req *request) (int64, error) {
+	c = c.route(req)
 	r, err := c.exchange(req)
 	if err != nil {
 		return 0, err
 	}
 	integer, err := readInteger(r)
-	c.passRead(r, err)
+	c.passRead(err)
 	return integer, err
 }
 
 func (c *Client[Key, Value]) commandBulk(req *request) (bulk Value, _ error) {
+	c = c.route(req)
 	r, err := c.exchange(req)
 	if err != nil {
 		return bulk, err
 	}
 	bulk, err = readBulk[Value](r)
-	c.passRead(r, err)
+	c.passRead(err)
 	if err == errNull {
 		err = nil
 	}
 	return bulk, err
 }
 
+func (c *Client[Key, Value]) commandBulkOk(req *request) (bulk Value, ok bool, _ error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return bulk, false, err
+	}
+	bulk, err = readBulk[Value](r)
+	c.passRead(err)
+	switch err {
+	case nil:
+		return bulk, true, nil
+	case errNull:
+		return bulk, false, nil
+	}
+	return bulk, false, err
+}
+
+func (c *Client[Key, Value]) commandBulkInto(req *request, buf []byte) (int, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return 0, err
+	}
+	n, err := readBulkInto(r, buf)
+	if err == io.ErrShortBuffer {
+		c.passRead(nil) // reply consumed
+	} else {
+		c.passRead(err)
+	}
+	if err == errNull {
+		err = nil
+	}
+	return n, err
+}
+
+func (c *Client[Key, Value]) commandString(req *request) (string, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return "", err
+	}
+	s, err := readBulk[string](r)
+	c.passRead(err)
+	if err == errNull {
+		err = nil
+	}
+	return s, err
+}
+
 func (c *Client[Key, Value]) commandArray(req *request) ([]Value, error) {
+	c = c.route(req)
 	r, err := c.exchange(req)
 	if err != nil {
 		return nil, err
 	}
 	array, err := readArray[Value](r)
-	c.passRead(r, err)
+	c.passRead(err)
 	if err == errNull {
 		err = nil
 	}
 	return array, err
 }
 
-// PassRead hands over the buffered reader to the following command in line. It
-// goes in idle mode (on the redisConn from connSem) when all requests are done
-// for.
-func (c *Client[Key, Value]) passRead(r *bufio.Reader, err error) {
+func (c *Client[Key, Value]) commandArrayOk(req *request) ([]Value, []bool, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	array, present, err := readArrayOk[Value](r)
+	c.passRead(err)
+	if err == errNull {
+		err = nil
+	}
+	return array, present, err
+}
+
+func (c *Client[Key, Value]) commandScored(req *request) ([]Value, []float64, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, scores, err := readScored[Value](r)
+	c.passRead(err)
+	if err == errNull {
+		err = nil
+	}
+	return members, scores, err
+}
+
+func (c *Client[Key, Value]) commandScan(req *request) (uint64, []Key, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	cursor, keys, err := readScan[Key](r)
+	c.passRead(err)
+	if c.keyPrefix != "" {
+		for i := range keys {
+			keys[i] = c.trimKeyPrefix(keys[i])
+		}
+	}
+	if err == errNull {
+		err = nil
+	}
+	return cursor, keys, err
+}
+
+func (c *Client[Key, Value]) commandMap(req *request) ([]Key, []Value, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, values, err := readMap[Key, Value](r)
+	c.passRead(err)
+	if err == errNull {
+		err = nil
+	}
+	return keys, values, err
+}
+
+// PassRead hands the buffered reader back to the read routine. I/O errors halt
+// the read routine, and they cause a reconnect before return.
+func (c *Client[Key, Value]) passRead(err error) {
+	c.readDone(err)
+
 	switch err {
 	case nil, errNull:
 		break
@@ -340,46 +1623,138 @@ func (c *Client[Key, Value]) passRead(r *bufio.Reader, err error) {
 		_, ok := err.(ServerError)
 		if !ok {
 			// got an I/O error on response
-			c.dropConnFromRead()
+			c.readReturn <- err // halt read routine
+			c.dropConnFromRead(err)
 			return
 		}
 	}
+	c.readReturn <- nil
+}
 
-	// pass r to enqueued
-	select {
-	case next := <-c.readQueue:
-		next <- r // direct pass
-		return
-	default:
-		break
-	}
-
-	// go idle
-	select {
-	case next := <-c.readQueue:
-		// request enqueued while awaiting lock
-		next <- r // pass after all
-
-	// Acquire write lock to make the idle decision atomic, as
-	// readQueue insertion (in exchange) operates within the lock.
-	case conn := <-c.connSem:
-		// write locked
+// ReadRoutine owns the buffered reader of conn. It hands r over to each command
+// in line, and it awaits the return in between. Idle connections are read too,
+// such that any RESP3 push messages get delivered as they arrive, and such that
+// connection loss is detected without the need for any command.
+func (c *Client[Key, Value]) readRoutine(conn *redisConn, r *connReader) {
+	for {
+		var next chan<- *connReader
 		select {
-		case next := <-c.readQueue:
-			// lost race while awaiting lock
-			next <- r // pass after all
+		case next = <-c.readQueue:
+			break // pass r to enqueued
 		default:
-			conn.idle = r // go idle mode
+			// go idle
+			select {
+			case next = <-c.readQueue:
+				break // request enqueued while awaiting lock
+
+			// Acquire write lock to make the idle decision atomic, as
+			// readQueue insertion (in exchange) operates within the lock.
+			case <-c.connSem:
+				// write locked
+				select {
+				case next = <-c.readQueue:
+					break // lost race while awaiting lock
+				default:
+					if c.IdleTimeout != 0 {
+						conn.SetReadDeadline(time.Now().Add(c.IdleTimeout))
+						conn.readDeadline = true
+					} else if conn.readDeadline {
+						conn.SetReadDeadline(time.Time{})
+						conn.readDeadline = false
+					}
+					conn.readIdle = true // go idle mode
+				}
+				c.connSem <- conn // unlock write
+
+			case <-c.readTerm:
+				return // accept halt; discard r
+			}
 		}
-		c.connSem <- conn // unlock write
 
-	case <-c.readTerm:
-		break // accept halt; discard r
+		if next == nil {
+			// await response or push message
+			var err error
+			if c.RESP3 {
+				err = c.routePushes(r)
+			} else {
+				_, err = r.Peek(1)
+			}
+			if err != nil {
+				// The command in line, if any, reads the error
+				// once more, as its deadline applies to the read.
+				select {
+				case next = <-c.readQueue:
+					break
+				case <-c.connSem:
+					// write locked
+					select {
+					case next = <-c.readQueue:
+						break
+					default:
+						// no commands
+						if c.IdleTimeout != 0 && isTimeout(err) {
+							c.idleTeardown(conn)
+							return
+						}
+					}
+					c.connSem <- conn // unlock write
+				case <-c.readTerm:
+					return // accept halt; discard r
+				}
+				if next == nil {
+					c.dropConnFromRead(err)
+					return
+				}
+			} else {
+				select {
+				case next = <-c.readQueue:
+					break
+				case <-c.readTerm:
+					return // accept halt; discard r
+				}
+			}
+		}
+
+		next <- r
+		if err := <-c.readReturn; err != nil {
+			return // command drops conn
+		}
+	}
+}
+
+// IdleTeardown closes conn with the write lock, and it releases the write lock
+// in the LazyConnect state, such that the next command reconnects.
+func (c *Client[Key, Value]) idleTeardown(conn *redisConn) {
+	conn.Close()
+	c.connState(Disconnected, c.addrIndex, errIdleTimeout)
+	c.connSem <- &redisConn{offline: errLazyConnect} // unlock write
+}
+
+// ReadDone concludes the command of the read routine with its result.
+func (c *Client[Key, Value]) readDone(err error) {
+	if c.readSince.IsZero() {
+		return // not applicable
+	}
+	d := time.Since(c.readSince)
+	c.readSince = time.Time{}
+
+	if err == errNull {
+		err = nil
+	}
+	if _, ok := err.(ServerError); err == nil || ok {
+		c.observeLatency(d)
+	}
+	if c.BreakerThreshold != 0 {
+		c.breakerResult(err)
+	}
+	if c.AfterCommandFunc != nil {
+		c.AfterCommandFunc(c.readCommand, c.readArgCount, d, err)
 	}
 }
 
-// DropConnFromRead disconnects with Redis.
-func (c *Client[Key, Value]) dropConnFromRead() {
+// DropConnFromRead disconnects with Redis, from the read routine, or from the
+// command which halted the read routine.
+func (c *Client[Key, Value]) dropConnFromRead(cause error) {
 	for {
 		select {
 		case <-c.readTerm:
@@ -390,7 +1765,7 @@ func (c *Client[Key, Value]) dropConnFromRead() {
 		// so include discard here to prevent deadlock.
 		case next := <-c.readQueue:
 			// signal connection loss
-			next <- (*bufio.Reader)(nil)
+			next <- (*connReader)(nil)
 
 		case conn := <-c.connSem:
 			// write locked
@@ -401,6 +1776,7 @@ func (c *Client[Key, Value]) dropConnFromRead() {
 				go func() {
 					conn.Close()
 					c.cancelQueue()
+					c.connState(Disconnected, c.addrIndex, cause)
 					c.connectOrClosed()
 				}()
 			}
@@ -410,63 +1786,231 @@ func (c *Client[Key, Value]) dropConnFromRead() {
 	}
 }
 
-func (c *ClientConfig) connect(readBufferSize int) (net.Conn, *bufio.Reader, error) {
+// ConnState reports to ConnStateFunc, if any. A negative addrIndex reports an
+// empty address.
+func (p *pipeline) connState(state ConnState, addrIndex int, err error) {
+	if p.config.ConnStateFunc == nil {
+		return
+	}
+	var addr string
+	if addrIndex >= 0 {
+		addrs, _ := p.target.load()
+		all := strings.Split(addrs, ",")
+		addr = all[addrIndex%len(all)]
+	}
+	p.config.ConnStateFunc(state, addr, err)
+}
+
+// Connect tries each address from target in line, starting with addrIndex. The
+// index is updated to the address in use on success. Network traffic goes into
+// counters when not nil.
+func (c *ClientConfig) connect(target *dialTarget, readBufferSize int, addrIndex *int, counters *ioCounters) (conn net.Conn, reader *connReader, err error) {
+	addr, _ := target.load()
+	addrs := strings.Split(addr, ",")
+	for i := range addrs {
+		index := (*addrIndex + i) % len(addrs)
+		conn, reader, err = c.connectAddr(addrs[index], target, readBufferSize, counters)
+		if err == nil {
+			*addrIndex = index
+			break
+		}
+	}
+	return
+}
+
+func (c *ClientConfig) connectAddr(addr string, target *dialTarget, readBufferSize int, counters *ioCounters) (net.Conn, *connReader, error) {
 	network := "tcp"
-	if isUnixAddr(c.Addr) {
+	if isUnixAddr(addr) {
 		network = "unix"
+	} else if scheme := addrScheme(addr); scheme != "" {
+		network = scheme
+		if c.DialFunc == nil {
+			return nil, nil, fmt.Errorf("redis: address %q requires a DialFunc", addr)
+		}
 	}
-	conn, err := net.DialTimeout(network, c.Addr, c.DialTimeout)
-	if err != nil {
-		return nil, nil, err
+	var conn net.Conn
+	var err error
+	if c.DialFunc != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.DialTimeout)
+		conn, err = c.DialFunc(ctx, network, addr)
+		cancel()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		dialer := net.Dialer{Timeout: c.DialTimeout, KeepAlive: c.KeepAlive}
+		conn, err = dialer.Dial(network, addr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// connection tuning
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.SetNoDelay(false)
+			tcp.SetLinger(0)
+		}
 	}
 
-	// connection tuning
-	if tcp, ok := conn.(*net.TCPConn); ok {
-		tcp.SetNoDelay(false)
-		tcp.SetLinger(0)
+	if c.TLS != nil {
+		conn, err = c.handshakeTLS(conn, addr)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	conn = deadlineConn{conn}
+	if c.Trace != nil {
+		conn = newTraceConn(conn, c.Trace)
+	}
+	if counters != nil {
+		conn = countingConn{conn, counters}
 	}
-	reader := bufio.NewReaderSize(conn, readBufferSize)
+	reader := newConnReader(conn, readBufferSize, c.SizeMax, c.ElementMax)
 
 	// apply sticky settings
-	if c.Password != nil {
-		req := requestWithString("*2\r\n$4\r\nAUTH\r\n$", c.Password)
-		defer req.free()
+	req := requestFix("")
+	_, password := target.load()
+	auth, selectDB := c.addSticky(req, password)
+	if len(req.buf) == 0 && c.OnConnect == nil {
+		req.free()
+		return conn, reader, nil
+	}
+	defer req.free()
 
-		if c.CommandTimeout != 0 {
-			conn.SetDeadline(time.Now().Add(c.CommandTimeout))
-			defer conn.SetDeadline(time.Time{})
-		}
-		_, err := conn.Write(req.buf)
+	if c.CommandTimeout != 0 {
+		conn.SetDeadline(time.Now().Add(c.CommandTimeout))
+		defer conn.SetDeadline(time.Time{})
+	}
+	if len(req.buf) != 0 {
+		_, err = conn.Write(req.buf)
 		// ⚠️ reverse/delayed error check
 		if err == nil {
-			err = readOK(reader)
+			err = c.readSticky(reader, auth, selectDB, "on new connection")
 		}
+	}
+	if err == nil && c.OnConnect != nil {
+		err = c.OnConnect(&ConnSetup{conn: conn, reader: reader})
 		if err != nil {
-			conn.Close()
-			return nil, nil, fmt.Errorf("redis: AUTH on new connection: %w", err)
+			err = fmt.Errorf("redis: OnConnect: %w", err)
 		}
 	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, reader, nil
+}
 
-	if c.DB != 0 {
-		req := requestWithDecimal("*2\r\n$6\r\nSELECT\r\n$", c.DB)
-		defer req.free()
+// DeadlineConn wraps any timeout error in ErrTimeout.
+type deadlineConn struct {
+	net.Conn
+}
 
-		if c.CommandTimeout != 0 {
-			conn.SetDeadline(time.Now().Add(c.CommandTimeout))
-			defer conn.SetDeadline(time.Time{})
-		}
-		_, err := conn.Write(req.buf)
-		// ⚠️ reverse/delayed error check
+// Read implements io.Reader.
+func (c deadlineConn) Read(p []byte) (n int, err error) {
+	n, err = c.Conn.Read(p)
+	if err != nil && isTimeout(err) {
+		err = &timeoutError{err}
+	}
+	return
+}
+
+// Write implements io.Writer.
+func (c deadlineConn) Write(p []byte) (n int, err error) {
+	n, err = c.Conn.Write(p)
+	if err != nil && isTimeout(err) {
+		err = &timeoutError{err}
+	}
+	return
+}
+
+func isTimeout(err error) bool {
+	var e net.Error
+	return errors.As(err, &e) && e.Timeout()
+}
+
+// HandshakeTLS secures conn, or it closes conn on error.
+func (c *ClientConfig) handshakeTLS(conn net.Conn, addr string) (net.Conn, error) {
+	config := c.TLS
+	if config.ServerName == "" && !isUnixAddr(addr) {
+		host, _, err := net.SplitHostPort(addr)
 		if err == nil {
-			err = readOK(reader)
+			config = config.Clone()
+			config.ServerName = host
 		}
-		if err != nil {
-			conn.Close()
-			return nil, nil, fmt.Errorf("redis: SELECT on new connection: %w", err)
+	}
+
+	tlsConn := tls.Client(conn, config)
+	conn.SetDeadline(time.Now().Add(c.DialTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("redis: TLS handshake: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// AddSticky follows r up with the commands for each connection setting, if
+// any, with password instead of the one from ClientConfig. The replies are
+// consumed with readSticky, with auth set when AUTH was included, and with
+// selectDB set when SELECT was included.
+func (c *ClientConfig) addSticky(r *request, password []byte) (auth, selectDB bool) {
+	if password != nil {
+		auth = true
+		if c.Username != "" {
+			r.buf = append(r.buf, "*3\r\n$4\r\nAUTH\r\n$"...)
+			r.buf = appendStringAndDollarToDollar(r.buf, c.Username)
+		} else {
+			r.buf = append(r.buf, "*2\r\n$4\r\nAUTH\r\n$"...)
 		}
+		r.buf = appendStringToDollar(r.buf, password)
 	}
 
-	return conn, reader, nil
+	if c.RESP3 {
+		r.buf = append(r.buf, "*2\r\n$5\r\nHELLO\r\n$1\r\n3\r\n"...)
+	}
+
+	if c.Name != "" {
+		r.buf = append(r.buf, "*3\r\n$6\r\nCLIENT\r\n$7\r\nSETNAME\r\n$"...)
+		r.buf = appendStringToDollar(r.buf, c.Name)
+	}
+
+	if db := atomic.LoadInt64(&c.DB); db != 0 {
+		selectDB = true
+		r.buf = append(r.buf, "*2\r\n$6\r\nSELECT\r\n$"...)
+		r.addDecimalToDollar(db)
+	}
+	return auth, selectDB
+}
+
+// ReadSticky consumes the replies of the addSticky commands. The errors
+// mention the command name followed by when.
+func (c *ClientConfig) readSticky(r *connReader, auth, selectDB bool, when string) error {
+	if auth {
+		if err := readOK(r); err != nil {
+			return fmt.Errorf("redis: AUTH %s: %w", when, err)
+		}
+	}
+
+	if c.RESP3 {
+		// reply is a map with server properties
+		if err := discardReply(r); err != nil {
+			return fmt.Errorf("redis: HELLO %s: %w", when, err)
+		}
+	}
+
+	if c.Name != "" {
+		if err := readOK(r); err != nil {
+			return fmt.Errorf("redis: CLIENT SETNAME %s: %w", when, err)
+		}
+	}
+
+	if selectDB {
+		if err := readOK(r); err != nil {
+			return fmt.Errorf("redis: SELECT %s: %w", when, err)
+		}
+	}
+
+	return nil
 }
 
 // noCopy may be embedded into structs which must not be copied
@@ -1,15 +1,26 @@
 package redis
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"math/rand"
 	"net"
 	"os"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -25,120 +36,1423 @@ func init() {
 	if s, ok := os.LookupEnv("TEST_REDIS_PASSWORD"); ok {
 		config.Password = []byte(s)
 	}
+	if s, ok := os.LookupEnv("TEST_REDIS_USERNAME"); ok {
+		config.Username = s
+	}
+
+	benchClient = NewClient[string, string](config)
+
+	config.CommandTimeout = time.Second
+	testClient = NewClient[string, string](config)
+
+	// make random keys vary
+	rand.Seed(time.Now().UnixNano())
+}
+
+func byteValueClient(t testing.TB) *Client[string, []byte] {
+	return ViewAs[string, []byte](testClient)
+}
+
+func randomKey(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, rand.Uint64())
+}
+
+func TestViewAs(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient[string, string](testClient.ClientConfig)
+	defer c.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	view := ViewAs[string, []byte](c.WithPrefix("view:").WithContext(ctx))
+
+	key := randomKey("test")
+	if err := view.SET(key, []byte("v")); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if got, err := c.GET("view:" + key); err != nil {
+		t.Error("GET error:", err)
+	} else if got != "v" {
+		t.Errorf("GET got %q, want %q", got, "v")
+	}
+	// blocking pool shared
+	list := randomKey("list")
+	if _, err := view.RPUSH(list, []byte("element")); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if k, v, err := view.BLPOP(time.Second, list); err != nil {
+		t.Error("BLPOP error:", err)
+	} else if k != list || string(v) != "element" {
+		t.Errorf("BLPOP got %q and %q, want %q and %q", k, v, list, "element")
+	}
+	if n := c.Stats().CommandsSent; n != 3 {
+		t.Errorf("got %d commands sent on pipeline, want 3", n)
+	}
+
+	cancel()
+	if _, err := view.GET(key); err != context.Canceled {
+		t.Errorf("GET after cancel got error %v, want context.Canceled", err)
+	}
+
+	c.Close()
+	if _, err := ViewAs[string, []byte](c).GET(key); err != ErrClosed {
+		t.Errorf("GET after Close got error %v, want ErrClosed", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+	c := NewDefaultClient[string, string](testClient.Addr)
+	if err := c.Close(); err != nil {
+		t.Fatal("close got error:", err)
+	}
+
+	if _, err := c.GET("arbitrary"); err != ErrClosed {
+		t.Errorf("command got error %q, want %q", err, ErrClosed)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal("second close got error:", err)
+	}
+}
+
+func TestCloseBussy(t *testing.T) {
+	t.Parallel()
+	c := NewClient[string, string](testClient.ClientConfig)
+	key := randomKey("counter")
+
+	timeout := time.NewTimer(time.Second)
+
+	// launch command loops
+	exit := make(chan error, runtime.GOMAXPROCS(0))
+	for routines := cap(exit); routines > 0; routines-- {
+		go func() {
+			for {
+				_, err := c.INCR(key)
+				if err != nil {
+					exit <- err
+					return
+				}
+			}
+		}()
+	}
+
+	// await full I/O activity
+	time.Sleep(2 * time.Millisecond)
+	t.Log(len(c.readQueue), "pending commands")
+
+	if err := c.Close(); err != nil {
+		t.Fatal("close got error:", err)
+	}
+	for i := 0; i < cap(exit); i++ {
+		select {
+		case <-timeout.C:
+			t.Fatalf("%d out of %d command routines stopped before timeout", i, cap(exit))
+		case err := <-exit:
+			if err != ErrClosed {
+				t.Errorf("got exit error %q, want %q", err, ErrClosed)
+			}
+		}
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.Addr = "doesnotexist.example.com:70"
+	config.DialTimeout = 100 * time.Millisecond
+	c := NewClient[string, string](config)
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error("close got error:", err)
+		}
+
+		if _, err := c.GET("arbitrary"); err != ErrClosed {
+			t.Errorf("command after close got error %q, want %q", err, ErrClosed)
+		}
+	}()
+
+	_, err := c.GET("arbitrary")
+	if e := new(net.OpError); !errors.As(err, &e) {
+		t.Errorf("got error %v, want a net.OpError", err)
+	} else if e.Op != "dial" {
+		t.Errorf(`got error for opperation %q, want "dial"`, e.Op)
+	}
+
+	// let the Client retry…
+	time.Sleep(2 * config.DialTimeout)
+
+	_, err = c.GET("arbitrary")
+	if e := new(net.OpError); !errors.As(err, &e) {
+		t.Errorf("after connect retry, got error %v, want a net.OpError", err)
+	} else if e.Op != "dial" {
+		t.Errorf(`after connect retry, got error for opperation %q, want "dial"`, e.Op)
+	}
+}
+
+func TestDialClient(t *testing.T) {
+	t.Parallel()
+
+	c, err := DialClient[string, string](testClient.ClientConfig)
+	if err != nil {
+		t.Fatal("dial error:", err)
+	}
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Error("GET error:", err)
+	}
+
+	_, err = DialClient[string, string](ClientConfig{Addr: "doesnotexist.example.com"})
+	if e := new(net.OpError); !errors.As(err, &e) {
+		t.Errorf("dial to unknown host got error %v, want a net.OpError", err)
+	}
+
+	config := testClient.ClientConfig
+	config.Name = "with space"
+	_, err = DialClient[string, string](config)
+	if e := ServerError(""); !errors.As(err, &e) {
+		t.Errorf("dial with name %q got error %v, want a ServerError", config.Name, err)
+	}
+}
+
+func TestLazyConnect(t *testing.T) {
+	t.Parallel()
+
+	connects := make(chan ConnState, 4)
+	config := testClient.ClientConfig
+	config.LazyConnect = true
+	config.ConnStateFunc = func(state ConnState, addr string, err error) {
+		connects <- state
+	}
+
+	c := NewClient[string, string](config)
+	defer c.Close()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case state := <-connects:
+		t.Fatalf("got state %s before first use", state)
+	default:
+		break
+	}
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Error("GET error:", err)
+	}
+	if state := <-connects; state != Connected {
+		t.Errorf("got state %s after GET, want Connected", state)
+	}
+
+	c = NewClient[string, string](config)
+	if err := c.Connect(); err != nil {
+		t.Error("Connect error:", err)
+	}
+	if state := <-connects; state != Connected {
+		t.Errorf("got state %s after Connect, want Connected", state)
+	}
+	if err := c.Connect(); err != nil {
+		t.Error("redundant Connect error:", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Error("close error:", err)
+	}
+	if err := c.Connect(); err != ErrClosed {
+		t.Errorf("Connect after Close got error %v, want ErrClosed", err)
+	}
+
+	// never connected
+	c = NewClient[string, string](config)
+	if err := c.Close(); err != nil {
+		t.Error("close error:", err)
+	}
+	if _, err := c.GET("arbitrary"); err != ErrClosed {
+		t.Errorf("GET after Close got error %v, want ErrClosed", err)
+	}
+
+	config.Addr = "doesnotexist.example.com"
+	config.ConnStateFunc = nil
+	c = NewClient[string, string](config)
+	defer c.Close()
+	err := c.Connect()
+	if e := new(net.OpError); !errors.As(err, &e) {
+		t.Errorf("Connect to unknown host got error %v, want a net.OpError", err)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		state ConnState
+		err   error
+	}
+	events := make(chan event, 9)
+	config := testClient.ClientConfig
+	config.IdleTimeout = 20 * time.Millisecond
+	config.ConnStateFunc = func(state ConnState, addr string, err error) {
+		events <- event{state, err}
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GET("arbitrary"); err != nil {
+			t.Fatal("GET error:", err)
+		}
+		if e := <-events; e.state != Connected {
+			t.Errorf("got event %+v, want Connected", e)
+		}
+		if e := <-events; e.state != Disconnected || e.err != errIdleTimeout {
+			t.Errorf("got event %+v, want Disconnected with errIdleTimeout", e)
+		}
+	}
+	if n := c.Stats().Reconnects; n != 1 {
+		t.Errorf("got %d reconnects, want 1", n)
+	}
+}
+
+func TestForceReconnect(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient[string, string](testClient.ClientConfig)
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET error:", err)
+	}
+
+	c.SetAddr("doesnotexist.example.com")
+	if c.Addr != testClient.Addr {
+		t.Errorf("SetAddr changed ClientConfig Addr to %q", c.Addr)
+	}
+	err := c.ForceReconnect()
+	if e := new(net.OpError); !errors.As(err, &e) || e.Op != "dial" {
+		t.Errorf("reconnect to unknown host got error %v, want a net.OpError for dial", err)
+	}
+	if _, err := c.GET("arbitrary"); err == nil {
+		t.Error("GET got no error after reconnect to unknown host")
+	}
+
+	c.SetAddr(testClient.Addr)
+	c.SetPassword([]byte("wrong"))
+	if err := c.ForceReconnect(); err == nil {
+		t.Error("reconnect with wrong password got no error")
+	}
+
+	c.SetPassword(testClient.Password)
+	if err := c.ForceReconnect(); err != nil {
+		t.Error("reconnect error:", err)
+	}
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Error("GET error:", err)
+	}
+	if err := c.ForceReconnect(); err != nil {
+		t.Error("reconnect on idle connection error:", err)
+	}
+	if n := c.Stats().Reconnects; n != 2 {
+		t.Errorf("got %d reconnects, want 2", n)
+	}
+}
+
+func TestConnState(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		state ConnState
+		addr  string
+		err   error
+	}
+	events := make(chan event, 9)
+	config := testClient.ClientConfig
+	config.ConnStateFunc = func(state ConnState, addr string, err error) {
+		events <- event{state, addr, err}
+	}
+	c := NewClient[string, string](config)
+
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET error:", err)
+	}
+	if e := <-events; e.state != Connected || e.addr != c.Addr || e.err != nil {
+		t.Errorf("got event %+v, want Connected to %q", e, c.Addr)
+	}
+
+	// break connection
+	conn := <-c.connSem
+	conn.Close()
+	c.connSem <- conn
+	if _, err := c.GET("arbitrary"); err == nil {
+		t.Error("GET on broken connection got no error")
+	}
+	if e := <-events; e.state != Disconnected || e.err == nil {
+		t.Errorf("got event %+v, want Disconnected with cause", e)
+	}
+	if e := <-events; e.state != Connected {
+		t.Errorf("got event %+v, want Connected after Disconnected", e)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Error("close error:", err)
+	}
+	if e := <-events; e.state != Disconnected || e.err != ErrClosed {
+		t.Errorf("got event %+v, want Disconnected with ErrClosed", e)
+	}
+
+	config.Addr = "doesnotexist.example.com"
+	c = NewClient[string, string](config)
+	defer c.Close()
+	if e := <-events; e.state != Reconnecting || e.addr != "" || e.err == nil {
+		t.Errorf("got event %+v, want Reconnecting with error", e)
+	}
+}
+
+func TestPingInterval(t *testing.T) {
+	t.Parallel()
+
+	states := make(chan ConnState, 9)
+	config := testClient.ClientConfig
+	config.PingInterval = 10 * time.Millisecond
+	config.ConnStateFunc = func(state ConnState, addr string, err error) {
+		states <- state
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if state := <-states; state != Connected {
+		t.Fatalf("got state %s, want Connected", state)
+	}
+
+	// break connection
+	conn := <-c.connSem
+	conn.Close()
+	c.connSem <- conn
+
+	// health check must detect without any commands
+	if state := <-states; state != Disconnected {
+		t.Errorf("got state %s, want Disconnected", state)
+	}
+	if state := <-states; state != Connected {
+		t.Errorf("got state %s, want Connected", state)
+	}
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Error("GET after health check error:", err)
+	}
+}
+
+func TestCommandHooks(t *testing.T) {
+	t.Parallel()
+
+	var before, after []string
+	config := testClient.ClientConfig
+	config.BeforeCommandFunc = func(name string, argCount int) {
+		before = append(before, fmt.Sprintf("%s/%d", name, argCount))
+	}
+	config.AfterCommandFunc = func(name string, argCount int, d time.Duration, err error) {
+		if d <= 0 {
+			t.Errorf("%s got duration %s", name, d)
+		}
+		var e ServerError
+		after = append(after, fmt.Sprintf("%s/%d %t", name, argCount, errors.As(err, &e)))
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	key := randomKey("test")
+	c.SET(key, "v")
+	c.GET(randomKey("absent"))
+	c.INCR(key)
+
+	wantBefore := []string{"SET/2", "GET/1", "INCR/1"}
+	if fmt.Sprint(before) != fmt.Sprint(wantBefore) {
+		t.Errorf("got before hooks %q, want %q", before, wantBefore)
+	}
+	// INCR on a non-integer value gets a ServerError
+	wantAfter := []string{"SET/2 false", "GET/1 false", "INCR/1 true"}
+	if fmt.Sprint(after) != fmt.Sprint(wantAfter) {
+		t.Errorf("got after hooks %q, want %q", after, wantAfter)
+	}
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	t.Parallel()
+
+	for _, retry := range []bool{false, true} {
+		config := testClient.ClientConfig
+		config.RetryIdempotent = retry
+		c := NewClient[string, string](config)
+		defer c.Close()
+		if _, err := c.GET("arbitrary"); err != nil {
+			t.Fatal("GET error:", err)
+		}
+
+		// break connection
+		conn := <-c.connSem
+		conn.Close()
+		c.connSem <- conn
+
+		_, err := c.GET("arbitrary")
+		if retry && err != nil {
+			t.Error("GET after connection loss with retry got error:", err)
+		}
+		if !retry && err == nil {
+			t.Error("GET after connection loss without retry got no error")
+		}
+	}
+}
+
+func TestTransientRetry(t *testing.T) {
+	t.Parallel()
+
+	// server is loading for the first two commands
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for seqNo := 1; ; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if line[0] != '*' {
+				continue
+			}
+			switch seqNo {
+			case 1, 2:
+				conn.Write([]byte("-LOADING Redis is loading the dataset in memory\r\n"))
+			case 3:
+				conn.Write([]byte("$2\r\nok\r\n"))
+			default:
+				conn.Write([]byte("-ERR no such thing\r\n"))
+			}
+			seqNo++
+		}
+	}()
+
+	c := NewClient[string, string](ClientConfig{
+		Addr:              l.Addr().String(),
+		TransientRetryMax: time.Second,
+	})
+	defer c.Close()
+
+	v, err := c.GET("k")
+	if err != nil {
+		t.Fatal("GET error:", err)
+	}
+	if v != "ok" {
+		t.Errorf(`GET got %q, want "ok"`, v)
+	}
+	if n := c.Stats().CommandsSent; n != 3 {
+		t.Errorf("got %d commands sent, want 3", n)
+	}
+
+	_, err = c.GET("k")
+	var e ServerError
+	if !errors.As(err, &e) || e.Transient() {
+		t.Errorf("GET got error %v, want a permanent ServerError", err)
+	}
+}
+
+func TestTransientRetryMultiple(t *testing.T) {
+	t.Parallel()
+
+	// server is loading on RESET only
+	addr := fakeReplica(t, func(args []string) string {
+		switch args[0] {
+		case "RESET":
+			return "-LOADING Redis is loading the dataset in memory\r\n"
+		case "PING":
+			return "+PONG\r\n"
+		default:
+			return "+OK\r\n"
+		}
+	})
+	c := NewClient[string, string](ClientConfig{
+		Addr:              addr,
+		Name:              "test",
+		TransientRetryMax: time.Second,
+	})
+	defer c.Close()
+
+	// RESET comes with CLIENT SETNAME in one request
+	var e ServerError
+	if err := c.RESET(); !errors.As(err, &e) || e.Prefix() != "LOADING" {
+		t.Errorf("RESET got error %v, want LOADING without retry", err)
+	}
+	if err := c.PING(); err != nil {
+		t.Error("PING after RESET error:", err)
+	}
+}
+
+func TestTransientRetryReader(t *testing.T) {
+	t.Parallel()
+
+	// server is loading on SET only
+	addr := fakeReplica(t, func(args []string) string {
+		switch args[0] {
+		case "SET":
+			return "-LOADING Redis is loading the dataset in memory\r\n"
+		case "PING":
+			return "+PONG\r\n"
+		default:
+			return "+OK\r\n"
+		}
+	})
+	c := NewClient[string, string](ClientConfig{
+		Addr:              addr,
+		TransientRetryMax: time.Second,
+	})
+	defer c.Close()
+
+	// payload can not be read twice
+	var e ServerError
+	if err := c.SETReader("k", strings.NewReader("hello"), 5); !errors.As(err, &e) || e.Prefix() != "LOADING" {
+		t.Errorf("SETReader got error %v, want LOADING without retry", err)
+	}
+	if err := c.PING(); err != nil {
+		t.Error("PING after SETReader error:", err)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+
+	// address without listener
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	c := NewClient[string, string](ClientConfig{
+		Addr:             l.Addr().String(),
+		BreakerThreshold: 2,
+		BreakerCoolDown:  100 * time.Millisecond,
+	})
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GET("k")
+		if err == nil || errors.Is(err, ErrBreakerOpen) {
+			t.Fatalf("GET %d got error %v, want connect failure", i+1, err)
+		}
+	}
+	if _, err := c.GET("k"); err != ErrBreakerOpen {
+		t.Errorf("GET after threshold got error %v, want %v", err, ErrBreakerOpen)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := c.GET("k"); err == nil || err == ErrBreakerOpen {
+		t.Errorf("GET after cool-down got error %v, want connect failure", err)
+	}
+	if _, err := c.GET("k"); err != ErrBreakerOpen {
+		t.Errorf("GET after failed probe got error %v, want %v", err, ErrBreakerOpen)
+	}
+}
+
+func TestOfflineWait(t *testing.T) {
+	t.Parallel()
+
+	// address without listener (yet)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c := NewClient[string, string](ClientConfig{
+		Addr:        addr,
+		OfflineWait: 50 * time.Millisecond,
+	})
+	start := time.Now()
+	if _, err := c.GET("k"); err == nil {
+		t.Fatal("GET without server got no error")
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("GET without server failed after %s, want OfflineWait", d)
+	}
+	c.Close()
+
+	c = NewClient[string, string](ClientConfig{
+		Addr:        addr,
+		OfflineWait: time.Second,
+	})
+	defer c.Close()
+	done := make(chan error)
+	go func() {
+		_, err := c.GET("k")
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	l, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip("address not available anymore:", err)
+	}
+	defer l.Close()
+	go func() {
+		// serve any connection, as other dialers may reach the address too
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line[0] == '*' {
+						conn.Write([]byte("$2\r\nok\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+
+	if err := <-done; err != nil {
+		t.Error("GET during offline got error:", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var b Backoff
+	var delays []time.Duration
+	for d := time.Duration(0); d < DialDelayMax; d = b.next(d) {
+		delays = append(delays, d)
+	}
+	if len(delays) != 9 || delays[1] != time.Millisecond || delays[2] != 3*time.Millisecond {
+		t.Errorf("default delays %v", delays)
+	}
+
+	b = Backoff{Initial: 10 * time.Millisecond, Max: 25 * time.Millisecond, Jitter: 0.5}
+	if got := b.next(b.next(b.next(0))); got != 25*time.Millisecond {
+		t.Errorf("got third delay %s, want Max 25ms", got)
+	}
+	for i := 0; i < 100; i++ {
+		d := b.randomize(100 * time.Millisecond)
+		if d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("100ms with jitter 0.5 got %s", d)
+		}
+	}
+}
+
+func TestReconnectAttempts(t *testing.T) {
+	t.Parallel()
+
+	// address without listener
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	states := make(chan ConnState, 10)
+	c := NewClient[string, string](ClientConfig{
+		Addr:      l.Addr().String(),
+		Reconnect: Backoff{Attempts: 3},
+		ConnStateFunc: func(state ConnState, addr string, err error) {
+			states <- state
+		},
+	})
+	defer c.Close()
+
+	for _, want := range []ConnState{Reconnecting, Reconnecting, Disconnected} {
+		select {
+		case got := <-states:
+			if got != want {
+				t.Fatalf("got state %s, want %s", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("state %s timeout", want)
+		}
+	}
+	if _, err := c.GET("k"); err == nil {
+		t.Error("GET after reconnect attempts got no error")
+	}
+	if n := c.Stats().Reconnects; n != 0 {
+		t.Errorf("got %d reconnects", n)
+	}
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.PoolSize = 3
+	c, err := DialClient[string, string](config)
+	if err != nil {
+		t.Fatal("dial error:", err)
+	}
+	defer c.Close()
+
+	if err := c.SELECT(4); err != nil {
+		t.Fatal("SELECT 4 error:", err)
+	}
+	key := randomKey("test")
+	if err := c.SET(key, "v"); err != nil {
+		t.Fatalf(`SET %q "v" error: %s`, key, err)
+	}
+	// each connection must be on database 4
+	view := c.WithContext(context.Background())
+	for i := 0; i < 2*config.PoolSize; i++ {
+		if v, err := view.GET(key); err != nil {
+			t.Errorf("GET %q error: %s", key, err)
+		} else if v != "v" {
+			t.Errorf(`GET %q got %q, want "v"`, key, v)
+		}
+	}
+
+	stats := c.Stats()
+	// SELECT on each connection, one SET, and the GETs
+	if want := int64(3*config.PoolSize + 1); stats.CommandsSent != want {
+		t.Errorf("got %d commands sent, want %d", stats.CommandsSent, want)
+	}
+	if stats.Reconnects != 0 {
+		t.Errorf("got %d reconnects", stats.Reconnects)
+	}
+}
+
+func TestCoalesceWrites(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.CoalesceWrites = true
+	c, err := DialClient[string, string](config)
+	if err != nil {
+		t.Fatal("dial error:", err)
+	}
+	defer c.Close()
+	key := randomKey("test-counter")
+
+	// queue commands on the write lock
+	const n = 100
+	conn := <-c.connSem
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := c.INCR(key)
+			errs <- err
+		}()
+	}
+	for atomic.LoadInt64(&c.writeWaiting) != n {
+		time.Sleep(time.Millisecond)
+	}
+	c.connSem <- conn
+
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Error("INCR error:", err)
+		}
+	}
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if v != "100" {
+		t.Errorf("GET %q got %q, want 100", key, v)
+	}
+
+	stats := c.Stats()
+	if stats.CommandsSent != n+1 {
+		t.Errorf("got %d commands sent, want %d", stats.CommandsSent, n+1)
+	}
+	// idle connection writes the first one directly
+	if stats.Writes > 3 {
+		t.Errorf("got %d writes for %d commands, want 3 at most", stats.Writes, stats.CommandsSent)
+	}
+
+	// break the connection with commands queued
+	conn = <-c.connSem
+	conn.Close()
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := c.INCR(key)
+			errs <- err
+		}()
+	}
+	for atomic.LoadInt64(&c.writeWaiting) != n {
+		time.Sleep(time.Millisecond)
+	}
+	c.connSem <- conn
+
+	timeout := time.After(2 * time.Second)
+	for i := 0; i < n; i++ {
+		select {
+		case <-errs:
+			break // either outcome
+		case <-timeout:
+			t.Fatalf("%d out of %d commands stuck after connection loss", n-i, n)
+		}
+	}
+	if _, err := c.GET(key); err != nil {
+		t.Error("GET after reconnect error:", err)
+	}
+}
+
+func TestSizeMax(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.SizeMax = 32
+	c := NewClient[string, string](config)
+	defer c.Close()
+	key := randomKey("test")
+
+	if err := c.SET(key, strings.Repeat("x", 33)); err == nil {
+		t.Error("SET with 33-byte value got no error")
+	}
+	if n := c.Stats().CommandsSent; n != 0 {
+		t.Errorf("got %d commands sent, want none", n)
+	}
+
+	if err := testClient.SET(key, strings.Repeat("x", 33)); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if _, err := c.GET(key); !errors.Is(err, errProtocol) {
+		t.Errorf("GET with 33-byte value got error %v, want %v", err, errProtocol)
+	}
+	if err := c.SET(key, strings.Repeat("x", 32)); err != nil {
+		t.Error("SET with 32-byte value error:", err)
+	}
+}
+
+func TestAuthUnknownUser(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.Username = "doesnotexist"
+	config.Password = []byte("arbitrary")
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	_, err := c.GET("arbitrary")
+	if e := ServerError(""); !errors.As(err, &e) {
+		t.Errorf("got error %v, want a ServerError", err)
+	} else if !strings.Contains(err.Error(), "AUTH") {
+		t.Errorf("got error %q, want AUTH mentioned", err)
+	}
+}
+
+func TestConnName(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.Name = "redis-test"
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Errorf("GET with name %q got error: %s", config.Name, err)
+	}
+	verifyName := func() {
+		t.Helper()
+		r := NewRequest("CLIENT")
+		r.AddString("GETNAME")
+		if got, err := c.DoBulk(r); err != nil {
+			t.Error("CLIENT GETNAME error:", err)
+		} else if got != config.Name {
+			t.Errorf("CLIENT GETNAME got %q, want %q", got, config.Name)
+		}
+	}
+	verifyName()
+	// name applies to each connection
+	if err := c.ForceReconnect(); err != nil {
+		t.Fatal("ForceReconnect error:", err)
+	}
+	verifyName()
+
+	config.Name = "with space"
+	c = NewClient[string, string](config)
+	defer c.Close()
+	_, err := c.GET("arbitrary")
+	if e := ServerError(""); !errors.As(err, &e) {
+		t.Errorf("GET with name %q got error %v, want a ServerError", config.Name, err)
+	} else if !strings.Contains(err.Error(), "SETNAME") {
+		t.Errorf("GET with name %q got error %q, want SETNAME mentioned", config.Name, err)
+	}
+}
+
+func TestDialFunc(t *testing.T) {
+	t.Parallel()
+
+	dialCount := make(chan string, 9)
+	dialer := &net.Dialer{KeepAlive: -1}
+	config := testClient.ClientConfig
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("dial context without deadline")
+		}
+		dialCount <- addr
+		return dialer.DialContext(ctx, network, addr)
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET error:", err)
+	}
+	if len(dialCount) != 1 {
+		t.Errorf("got %d dials, want 1", len(dialCount))
+	} else if addr := <-dialCount; addr != config.Addr {
+		t.Errorf("dialed %q, want %q", addr, config.Addr)
+	}
+
+	dialErr := errors.New("dial denied")
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, dialErr
+	}
+	c = NewClient[string, string](config)
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); !errors.Is(err, dialErr) {
+		t.Errorf("GET got error %v, want %v", err, dialErr)
+	}
+}
+
+func TestDialBridge(t *testing.T) {
+	t.Parallel()
+
+	const gateway = "wss://gw.example.com/redis"
+	config := testClient.ClientConfig
+	config.Addr = gateway
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network != "wss" || addr != gateway {
+			t.Errorf("dial got network %q and address %q, want wss and %q", network, addr, gateway)
+		}
+		// emulate bridge with a plain connection
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", testClient.Addr)
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if c.Addr != gateway {
+		t.Errorf("got Addr %q, want %q as is", c.Addr, gateway)
+	}
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Error("GET error:", err)
+	}
+
+	config.DialFunc = nil
+	c = NewClient[string, string](config)
+	defer c.Close()
+	want := `redis: ClientConfig Addr "wss://gw.example.com/redis" requires a DialFunc`
+	if _, err := c.GET("arbitrary"); err == nil || err.Error() != want {
+		t.Errorf("GET without DialFunc got error %v, want %q", err, want)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	t.Parallel()
+
+	// address of a closed listener refuses connections
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.Close()
+	deadAddr := ln.Addr().String()
+
+	dials := make(chan string, 9)
+	config := testClient.ClientConfig
+	config.Addr = deadAddr + "," + testClient.Addr
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials <- addr
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET with failover error:", err)
+	}
+	if n := len(dials); n != 2 {
+		t.Fatalf("got %d dials, want 2", n)
+	}
+	first, second := <-dials, <-dials
+	if first != deadAddr || second != testClient.Addr {
+		t.Errorf("got dial addresses %s and %s, want %s and %s", first, second, deadAddr, testClient.Addr)
+	}
+
+	// break connection
+	conn := <-c.connSem
+	conn.Close()
+	c.connSem <- conn
+	c.GET("arbitrary") // write error
+
+	// reconnect starts with the address last connected
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET after reconnect error:", err)
+	}
+	if len(dials) != 1 {
+		t.Errorf("got %d dials on reconnect, want 1", len(dials))
+	} else if addr := <-dials; addr != testClient.Addr {
+		t.Errorf("reconnect dialed %q, want %q", addr, testClient.Addr)
+	}
+}
+
+// NewTLSProxy serves the test server with mutual TLS. The certificate is valid
+// for the "redis.test" domain, for both server and client authentication.
+func newTLSProxy(t *testing.T) (addr string, roots *x509.CertPool, cert tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis.test"},
+		DNSNames:              []string{"redis.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x509Cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots = x509.NewCertPool()
+	roots.AddCert(x509Cert)
+	cert = tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: x509Cert}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return // closed
+			}
+			network := "tcp"
+			if isUnixAddr(testClient.Addr) {
+				network = "unix"
+			}
+			backend, err := net.Dial(network, testClient.Addr)
+			if err != nil {
+				t.Error("proxy dial error:", err)
+				conn.Close()
+				continue
+			}
+			go func() {
+				io.Copy(backend, conn)
+				backend.Close()
+			}()
+			go func() {
+				io.Copy(conn, backend)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), roots, cert
+}
 
-	benchClient = NewClient[string, string](config)
+func TestMutualTLS(t *testing.T) {
+	t.Parallel()
+	addr, roots, cert := newTLSProxy(t)
 
-	config.CommandTimeout = time.Second
-	testClient = NewClient[string, string](config)
+	var verifyCount int
+	config := testClient.ClientConfig
+	config.Addr = addr
+	config.TLS = &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{cert},
+		ServerName:   "redis.test",
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			verifyCount++
+			if len(rawCerts) != 1 || !bytes.Equal(rawCerts[0], cert.Certificate[0]) {
+				return errors.New("certificate pin mismatch")
+			}
+			return nil
+		},
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
 
-	// make random keys vary
-	rand.Seed(time.Now().UnixNano())
-}
+	key := randomKey("test")
+	if err := c.SET(key, "secure"); err != nil {
+		t.Fatalf(`SET %q "secure" over TLS error: %s`, key, err)
+	}
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q over TLS error: %s", key, err)
+	} else if v != "secure" {
+		t.Errorf(`GET %q over TLS got %q, want "secure"`, key, v)
+	}
+	if verifyCount != 1 {
+		t.Errorf("VerifyPeerCertificate got %d invocations, want 1", verifyCount)
+	}
 
-func byteValueClient(t testing.TB) *Client[string, []byte] {
-	c := NewClient[string, []byte](testClient.ClientConfig)
-	t.Cleanup(func() {
-		err := c.Close()
-		if err != nil {
-			t.Error("close error:", err)
-		}
-	})
-	return c
+	// without client certificate
+	config.TLS = &tls.Config{RootCAs: roots, ServerName: "redis.test"}
+	c = NewClient[string, string](config)
+	defer c.Close()
+	if _, err := c.GET(key); err == nil {
+		t.Error("GET without client certificate got no error")
+	}
 }
 
-func randomKey(prefix string) string {
-	return fmt.Sprintf("%s-%d", prefix, rand.Uint64())
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := testClient.WithContext(ctx).GET("arbitrary"); err != context.Canceled {
+		t.Errorf("GET with cancelled context got error %v, want %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	key := randomKey("test")
+	if err := testClient.WithContext(ctx).SET(key, "ctx"); err != nil {
+		t.Errorf("SET %q with context error: %s", key, err)
+	}
+	if v, err := testClient.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if v != "ctx" {
+		t.Errorf("GET %q got %q, want \"ctx\"", key, v)
+	}
 }
 
-func TestClose(t *testing.T) {
+func TestContextDeadline(t *testing.T) {
 	t.Parallel()
-	c := NewDefaultClient[string, string](testClient.Addr)
-	if err := c.Close(); err != nil {
-		t.Fatal("close got error:", err)
-	}
 
-	if _, err := c.GET("arbitrary"); err != ErrClosed {
-		t.Errorf("command got error %q, want %q", err, ErrClosed)
+	// server never responds
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
 
-	if err := c.Close(); err != nil {
-		t.Fatal("second close got error:", err)
+	c := NewClient[string, string](ClientConfig{Addr: l.Addr().String()})
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.WithContext(ctx).GET("arbitrary")
+	var e net.Error
+	if !errors.As(err, &e) || !e.Timeout() {
+		t.Errorf("GET got error %v, want a timeout", err)
+	}
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GET got error %v, want ErrTimeout and context.DeadlineExceeded", err)
 	}
 }
 
-func TestCloseBussy(t *testing.T) {
+func TestCommandTimeout(t *testing.T) {
 	t.Parallel()
-	c := NewClient[string, string](testClient.ClientConfig)
-	key := randomKey("counter")
 
-	timeout := time.NewTimer(time.Second)
+	// server never responds
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
 
-	// launch command loops
-	exit := make(chan error, runtime.GOMAXPROCS(0))
-	for routines := cap(exit); routines > 0; routines-- {
-		go func() {
-			for {
-				_, err := c.INCR(key)
-				if err != nil {
-					exit <- err
-					return
-				}
+	c := NewClient[string, string](ClientConfig{
+		Addr:           l.Addr().String(),
+		CommandTimeout: 50 * time.Millisecond,
+	})
+	defer c.Close()
+
+	_, err = c.GET("arbitrary")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("GET got error %v, want ErrTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GET got error %v, want a context.DeadlineExceeded match", err)
+	}
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("GET got error %v, want the os.ErrDeadlineExceeded cause", err)
+	}
+	var e net.Error
+	if !errors.As(err, &e) || !e.Timeout() {
+		t.Errorf("GET got error %v, want a net.Error with Timeout", err)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	t.Parallel()
+
+	// server never responds
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
 			}
-		}()
+			defer conn.Close()
+		}
+	}()
+
+	c := NewClient[string, string](ClientConfig{
+		Addr:           l.Addr().String(),
+		CommandTimeout: time.Minute,
+		ReadTimeout:    50 * time.Millisecond,
+	})
+	defer c.Close()
+
+	start := time.Now()
+	_, err = c.GET("arbitrary")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("GET got error %v, want ErrTimeout", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("GET returned after %s", d)
 	}
+}
 
-	// await full I/O activity
-	time.Sleep(2 * time.Millisecond)
-	t.Log(len(c.readQueue), "pending commands")
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
 
-	if err := c.Close(); err != nil {
-		t.Fatal("close got error:", err)
+	// server never reads
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
 	}
-	for i := 0; i < cap(exit); i++ {
-		select {
-		case <-timeout.C:
-			t.Fatalf("%d out of %d command routines stopped before timeout", i, cap(exit))
-		case err := <-exit:
-			if err != ErrClosed {
-				t.Errorf("got exit error %q, want %q", err, ErrClosed)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
 			}
+			defer conn.Close()
 		}
+	}()
+
+	c := NewClient[string, string](ClientConfig{
+		Addr:         l.Addr().String(),
+		WriteTimeout: 50 * time.Millisecond,
+		KeepAlive:    -1,
+	})
+	defer c.Close()
+
+	// exceeds the socket buffers
+	value := strings.Repeat("v", 64<<20)
+	start := time.Now()
+	err = c.SET("k", value)
+	var e *net.OpError
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &e) || e.Op != "write" {
+		t.Errorf("SET got error %v, want ErrTimeout on write", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("SET returned after %s", d)
 	}
 }
 
-func TestUnavailable(t *testing.T) {
+func TestContextQueued(t *testing.T) {
 	t.Parallel()
 
-	config := testClient.ClientConfig
-	config.Addr = "doesnotexist.example.com:70"
-	config.DialTimeout = 100 * time.Millisecond
-	c := NewClient[string, string](config)
-	defer func() {
-		if err := c.Close(); err != nil {
-			t.Error("close got error:", err)
+	// server responds slowly with a sequence number on each command
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for seqNo := 1; ; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if line[0] != '*' {
+				continue
+			}
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(conn, "$1\r\n%d\r\n", seqNo)
+			seqNo++
 		}
+	}()
 
-		if _, err := c.GET("arbitrary"); err != ErrClosed {
-			t.Errorf("command after close got error %q, want %q", err, ErrClosed)
+	c := NewClient[string, string](ClientConfig{Addr: l.Addr().String()})
+	defer c.Close()
+
+	first := make(chan string)
+	go func() {
+		v, err := c.GET("k")
+		if err != nil {
+			t.Error("first GET error:", err)
 		}
+		first <- v
 	}()
+	time.Sleep(10 * time.Millisecond)
 
-	_, err := c.GET("arbitrary")
-	if e := new(net.OpError); !errors.As(err, &e) {
-		t.Errorf("got error %v, want a net.OpError", err)
-	} else if e.Op != "dial" {
-		t.Errorf(`got error for opperation %q, want "dial"`, e.Op)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := c.WithContext(ctx).GET("k"); err != context.Canceled {
+		t.Errorf("queued GET got error %v, want %v", err, context.Canceled)
+	}
+	if d := time.Since(start); d > 40*time.Millisecond {
+		t.Errorf("queued GET returned after %s", d)
 	}
 
-	// let the Client retry…
-	time.Sleep(2 * config.DialTimeout)
-
-	_, err = c.GET("arbitrary")
-	if e := new(net.OpError); !errors.As(err, &e) {
-		t.Errorf("after connect retry, got error %v, want a net.OpError", err)
-	} else if e.Op != "dial" {
-		t.Errorf(`after connect retry, got error for opperation %q, want "dial"`, e.Op)
+	v, err := c.GET("k")
+	if err != nil {
+		t.Fatal("third GET error:", err)
+	}
+	if v != "3" {
+		t.Errorf("third GET got %q, want \"3\"", v)
 	}
+	if v := <-first; v != "1" {
+		t.Errorf("first GET got %q, want \"1\"", v)
+	}
+}
+
+// WriteFailConn fails on each write.
+type writeFailConn struct {
+	net.Conn
+}
+
+// Write implements io.Writer.
+func (writeFailConn) Write(p []byte) (n int, err error) {
+	return 0, &net.OpError{Op: "write", Net: "tcp", Err: errors.New("test failure")}
 }
 
 // Note that testClient must recover for the next test to pass.
@@ -146,9 +1460,11 @@ func TestWriteError(t *testing.T) {
 	timeout := time.After(time.Second)
 	select {
 	case conn := <-testClient.connSem:
-		if conn.Conn != nil {
-			conn.Close()
+		if conn.Conn == nil {
+			testClient.connSem <- conn
+			t.Fatal("no connection")
 		}
+		conn.Conn = writeFailConn{conn.Conn}
 
 		select {
 		case testClient.connSem <- conn:
@@ -156,10 +1472,6 @@ func TestWriteError(t *testing.T) {
 		case <-timeout:
 			t.Fatal("connection sempahore release timeout")
 		}
-
-		if conn.Conn == nil {
-			t.Fatal("no connection")
-		}
 	case <-timeout:
 		t.Fatal("connection sempahore acquire timeout")
 	}
@@ -174,34 +1486,154 @@ func TestWriteError(t *testing.T) {
 	}
 }
 
-// Note that testClient must recover for the next test to pass.
 func TestReadError(t *testing.T) {
-	timeout := time.After(time.Second)
+	t.Parallel()
 
-	// break connection
-	select {
-	case conn := <-testClient.connSem:
-		if conn.Conn != nil {
-			conn.Conn.Close()
+	// server disconnects on the first command
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadString('\n')
+			conn.Close()
 		}
+	}()
+
+	c := NewClient[string, string](ClientConfig{Addr: l.Addr().String()})
+	defer c.Close()
+
+	_, err = c.DEL("key")
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("DEL got error %v, want %v", err, io.EOF)
+	}
+}
+
+// Connection loss must show without any command pending.
+func TestIdleDisconnect(t *testing.T) {
+	t.Parallel()
+
+	states := make(chan ConnState, 9)
+	config := testClient.ClientConfig
+	config.ConnStateFunc = func(state ConnState, addr string, err error) {
+		states <- state
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if state := <-states; state != Connected {
+		t.Fatalf("got state %s, want Connected", state)
+	}
 
-		// replace with closed pipe
-		c, _ := net.Pipe()
-		c.Close()
-		conn.Conn = c
+	conn := <-c.connSem
+	conn.Close()
+	c.connSem <- conn
+
+	timeout := time.After(time.Second)
+	for _, want := range []ConnState{Disconnected, Connected} {
 		select {
-		case testClient.connSem <- conn:
-			break // write unlocked
+		case state := <-states:
+			if state != want {
+				t.Errorf("got state %s, want %s", state, want)
+			}
 		case <-timeout:
-			t.Fatal("connection sempahore release timeout")
+			t.Fatalf("no %s state before timeout", want)
 		}
-	case <-timeout:
-		t.Fatal("connection sempahore acquire timeout")
 	}
+}
 
-	_, err := testClient.DEL("key")
-	if !errors.Is(err, io.ErrClosedPipe) {
-		t.Errorf("DEL got error %q, want %q", err, io.ErrClosedPipe)
+func TestRESP3(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.RESP3 = true
+	c := NewClient[string, []byte](config)
+	defer c.Close()
+
+	key := randomKey("test")
+	if err := c.SET(key, []byte("v")); err != nil {
+		var e ServerError
+		if errors.As(err, &e) && e.Prefix() == "ERR" {
+			t.Skip("RESP3 not supported by server:", err)
+		}
+		t.Fatalf(`SET %q "v" error: %s`, key, err)
+	}
+
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if string(v) != "v" {
+		t.Errorf(`GET %q got %q, want "v"`, key, v)
+	}
+	const absentKey = "doesn't exist"
+	if v, err := c.GET(absentKey); err != nil {
+		t.Errorf("GET %q error: %s", absentKey, err)
+	} else if v != nil {
+		t.Errorf("GET %q got %q, want nil", absentKey, v)
+	}
+
+	// RESP3 maps decode as RESP2 arrays
+	if _, err := c.HSET(key+"-hash", "f", []byte("v")); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if fields, values, err := c.HGETALL(key + "-hash"); err != nil {
+		t.Errorf("HGETALL %q error: %s", key+"-hash", err)
+	} else if len(fields) != 1 || fields[0] != "f" || len(values) != 1 || string(values[0]) != "v" {
+		t.Errorf(`HGETALL %q got fields %q and values %q, want ["f"] and ["v"]`, key+"-hash", fields, values)
+	}
+}
+
+func TestIdlePush(t *testing.T) {
+	t.Parallel()
+
+	// server sends a push message after the handshake
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.EqualFold(line, "HELLO\r\n") {
+				r.ReadString('\n') // protocol length
+				r.ReadString('\n') // protocol version
+				conn.Write([]byte("%0\r\n>2\r\n$10\r\ninvalidate\r\n*1\r\n$1\r\nk\r\n"))
+			}
+		}
+	}()
+
+	pushes := make(chan string, 1)
+	c := NewClient[string, string](ClientConfig{
+		Addr:  l.Addr().String(),
+		RESP3: true,
+		PushFunc: func(kind string, args [][]byte) {
+			pushes <- fmt.Sprintf("%s %q", kind, args)
+		},
+	})
+	defer c.Close()
+
+	select {
+	case got := <-pushes:
+		const want = `invalidate ["k"]`
+		if got != want {
+			t.Errorf("got push %s, want %s", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Error("no push message without commands")
 	}
 }
 
@@ -401,10 +1833,14 @@ func BenchmarkArray(b *testing.B) {
 	}
 }
 
+func visitNoop([]byte) error { return nil }
+
 func TestNoAllocation(t *testing.T) {
 	// both too large for stack:
 	key := randomKey(strings.Repeat("k", 10e6))
 	value := strings.Repeat("v", 10e6)
+	buf := make([]byte, len(value))
+	var custom Request
 
 	f := func() {
 		if _, err := testClient.INCR(key); err != nil {
@@ -413,6 +1849,9 @@ func TestNoAllocation(t *testing.T) {
 		if err := testClient.SET(key, value); err != nil {
 			t.Fatal(err)
 		}
+		if _, err := testClient.GETInto(key, buf); err != nil {
+			t.Fatal(err)
+		}
 		if _, err := testClient.APPEND(key, value); err != nil {
 			t.Fatal(err)
 		}
@@ -426,6 +1865,31 @@ func TestNoAllocation(t *testing.T) {
 		if _, err := testClient.DELArgs(key); err != nil {
 			t.Fatal(err)
 		}
+
+		custom.Reset()
+		custom.AddString("INCRBY")
+		custom.AddString(key)
+		custom.AddInt(99)
+		if _, err := testClient.DoInteger(&custom); err != nil {
+			t.Fatal(err)
+		}
+		custom.Reset()
+		custom.AddString("GET")
+		custom.AddString(key)
+		if _, err := testClient.DoBulkInto(&custom, buf); err != nil {
+			t.Fatal(err)
+		}
+		pooled, err := testClient.MGETPooled(key, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pooled.Release()
+		if _, err := testClient.GETVisit(key, visitNoop); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testClient.DEL(key); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	perRun := testing.AllocsPerRun(1, f)
@@ -433,3 +1897,88 @@ func TestNoAllocation(t *testing.T) {
 		t.Errorf("did %f memory allocations, want 0", perRun)
 	}
 }
+
+func TestOnConnect(t *testing.T) {
+	t.Parallel()
+
+	key := randomKey("test-key")
+	c, err := DialClient[string, string](ClientConfig{
+		Addr:     testClient.Addr,
+		PoolSize: 2,
+		OnConnect: func(s *ConnSetup) error {
+			if err := s.OK("CLIENT", "SETNAME", "setup"); err != nil {
+				return err
+			}
+			if _, err := s.Integer("INCR", key); err != nil {
+				return err
+			}
+			v, err := s.String("ECHO", "hello")
+			if err != nil {
+				return err
+			}
+			if v != "hello" {
+				return fmt.Errorf("ECHO got %q", v)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("dial error:", err)
+	}
+	defer c.Close()
+	if n, err := c.GET(key); err != nil {
+		t.Error("GET error:", err)
+	} else if n != "2" {
+		t.Errorf("got %q connection setups, want 2 for the pool", n)
+	}
+
+	reject := errors.New("rejected")
+	_, err = DialClient[string, string](ClientConfig{
+		Addr:      testClient.Addr,
+		OnConnect: func(s *ConnSetup) error { return reject },
+	})
+	if !errors.Is(err, reject) {
+		t.Errorf("dial with rejection got error %v, want %v", err, reject)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := testClient.ClientConfig.Validate(); err != nil {
+		t.Error("test configuration got error:", err)
+	}
+
+	golden := []struct {
+		config ClientConfig
+		want   string
+	}{
+		{ClientConfig{CommandTimeout: -time.Second}, "redis: ClientConfig CommandTimeout -1s is negative"},
+		{ClientConfig{Reconnect: Backoff{Max: -1}}, "redis: ClientConfig Reconnect Max -1ns is negative"},
+		{ClientConfig{DB: -1}, "redis: ClientConfig DB -1 is negative"},
+		{ClientConfig{PoolSize: -2}, "redis: ClientConfig PoolSize -2 is negative"},
+		{ClientConfig{Reconnect: Backoff{Jitter: 1.5}}, "redis: ClientConfig Reconnect Jitter 1.5 is not in range [0, 1]"},
+		{ClientConfig{Username: "u"}, "redis: ClientConfig Username without Password"},
+		{ClientConfig{PushFunc: func(string, [][]byte) {}}, "redis: ClientConfig PushFunc without RESP3, as push messages require RESP3"},
+	}
+	for _, gold := range golden {
+		err := gold.config.Validate()
+		if err == nil || err.Error() != gold.want {
+			t.Errorf("got error %v, want %q", err, gold.want)
+		}
+	}
+
+	config := testClient.ClientConfig
+	config.DB = -1
+	if _, err := DialClient[string, string](config); err == nil || err.Error() != "redis: ClientConfig DB -1 is negative" {
+		t.Errorf("DialClient got error %v, want validation error", err)
+	}
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); err == nil || err.Error() != "redis: ClientConfig DB -1 is negative" {
+		t.Errorf("GET got error %v, want validation error", err)
+	}
+	if err := c.Connect(); err == nil || err.Error() != "redis: ClientConfig DB -1 is negative" {
+		t.Errorf("Connect got error %v, want validation error", err)
+	}
+}
@@ -0,0 +1,394 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SlotRange is an inclusive interval of hash slots.
+type SlotRange struct {
+	First, Last int64
+}
+
+// ClusterInfo is the state of a cluster node, conform CLUSTER INFO.
+type ClusterInfo struct {
+	State         string // either "ok" or "fail"
+	SlotsAssigned int64
+	SlotsOK       int64
+	SlotsPFail    int64
+	SlotsFail     int64
+	KnownNodes    int64
+	Size          int64 // number of primaries serving slots
+	CurrentEpoch  int64
+	MyEpoch       int64
+
+	// Fields has all properties as reported by the node.
+	Fields map[string]string
+}
+
+// ClusterNode is an entry from CLUSTER NODES.
+type ClusterNode struct {
+	ID       string
+	Addr     string // host and port for clients
+	BusPort  int64  // cluster bus port
+	Hostname string // optional
+
+	// Flags include "myself", "master", "slave", "fail?", "fail",
+	// "handshake", "noaddr", "nofailover" and "noflags".
+	Flags []string
+
+	PrimaryID   string // empty for primaries
+	PingSent    int64  // Unix time in milliseconds
+	PongRecv    int64  // Unix time in milliseconds
+	ConfigEpoch int64
+	Connected   bool // link state
+
+	// Slots served, excluding any migration in progress.
+	Slots []SlotRange
+}
+
+// ClusterSlots is an entry from CLUSTER SLOTS.
+type ClusterSlots struct {
+	SlotRange
+
+	// The primary comes first, followed by any replicas.
+	Nodes []ClusterSlotsNode
+}
+
+// ClusterSlotsNode is a node entry from CLUSTER SLOTS.
+type ClusterSlotsNode struct {
+	Host string // may be empty or "?" when unknown
+	Port int64
+	ID   string
+}
+
+// ClusterShard is an entry from CLUSTER SHARDS.
+type ClusterShard struct {
+	Slots []SlotRange
+	Nodes []ClusterShardNode
+}
+
+// ClusterShardNode is a node entry from CLUSTER SHARDS.
+type ClusterShardNode struct {
+	ID                string
+	Endpoint          string
+	IP                string
+	Hostname          string
+	Port              int64
+	TLSPort           int64
+	Role              string // either "master" or "replica"
+	ReplicationOffset int64
+	Health            string // either "online", "failed" or "loading"
+}
+
+// CLUSTERINFO executes <https://redis.io/commands/cluster-info>.
+func (c *Client[Key, Value]) CLUSTERINFO() (*ClusterInfo, error) {
+	text, err := c.commandString(requestFix("*2\r\n$7\r\nCLUSTER\r\n$4\r\nINFO\r\n").idempotent())
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterInfo(text), nil
+}
+
+// CLUSTERNODES executes <https://redis.io/commands/cluster-nodes>.
+func (c *Client[Key, Value]) CLUSTERNODES() ([]ClusterNode, error) {
+	text, err := c.commandString(requestFix("*2\r\n$7\r\nCLUSTER\r\n$5\r\nNODES\r\n").idempotent())
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterNodes(text)
+}
+
+// CLUSTERSLOTS executes <https://redis.io/commands/cluster-slots>.
+func (c *Client[Key, Value]) CLUSTERSLOTS() ([]ClusterSlots, error) {
+	req := requestFix("*2\r\n$7\r\nCLUSTER\r\n$5\r\nSLOTS\r\n").idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := readClusterSlots(r)
+	c.passRead(err)
+	return slots, err
+}
+
+// CLUSTERSHARDS executes <https://redis.io/commands/cluster-shards>.
+// Redis version 7 or later is required.
+func (c *Client[Key, Value]) CLUSTERSHARDS() ([]ClusterShard, error) {
+	req := requestFix("*2\r\n$7\r\nCLUSTER\r\n$6\r\nSHARDS\r\n").idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := readClusterShards(r)
+	c.passRead(err)
+	return shards, err
+}
+
+// CLUSTERMYID executes <https://redis.io/commands/cluster-myid>.
+func (c *Client[Key, Value]) CLUSTERMYID() (string, error) {
+	return c.commandString(requestFix("*2\r\n$7\r\nCLUSTER\r\n$4\r\nMYID\r\n").idempotent())
+}
+
+// CLUSTERCOUNTKEYSINSLOT executes <https://redis.io/commands/cluster-countkeysinslot>.
+func (c *Client[Key, Value]) CLUSTERCOUNTKEYSINSLOT(slot int64) (int64, error) {
+	return c.commandInteger(requestWithDecimal("*3\r\n$7\r\nCLUSTER\r\n$15\r\nCOUNTKEYSINSLOT\r\n$", slot).idempotent())
+}
+
+func parseClusterInfo(text string) *ClusterInfo {
+	info := ClusterInfo{Fields: make(map[string]string)}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+		name, value := line[:i], line[i+1:]
+		info.Fields[name] = value
+
+		n, _ := strconv.ParseInt(value, 10, 64)
+		switch name {
+		case "cluster_state":
+			info.State = value
+		case "cluster_slots_assigned":
+			info.SlotsAssigned = n
+		case "cluster_slots_ok":
+			info.SlotsOK = n
+		case "cluster_slots_pfail":
+			info.SlotsPFail = n
+		case "cluster_slots_fail":
+			info.SlotsFail = n
+		case "cluster_known_nodes":
+			info.KnownNodes = n
+		case "cluster_size":
+			info.Size = n
+		case "cluster_current_epoch":
+			info.CurrentEpoch = n
+		case "cluster_my_epoch":
+			info.MyEpoch = n
+		}
+	}
+	return &info
+}
+
+func parseClusterNodes(text string) ([]ClusterNode, error) {
+	var nodes []ClusterNode
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("%w; CLUSTER NODES line %.40q", errProtocol, line)
+		}
+
+		node := ClusterNode{
+			ID:        fields[0],
+			Flags:     strings.Split(fields[2], ","),
+			Connected: fields[7] == "connected",
+		}
+		// format: ip:port@cport[,hostname]
+		addr := fields[1]
+		if i := strings.IndexByte(addr, ','); i >= 0 {
+			node.Hostname = addr[i+1:]
+			addr = addr[:i]
+		}
+		if i := strings.IndexByte(addr, '@'); i >= 0 {
+			node.BusPort, _ = strconv.ParseInt(addr[i+1:], 10, 64)
+			addr = addr[:i]
+		}
+		node.Addr = addr
+		if fields[3] != "-" {
+			node.PrimaryID = fields[3]
+		}
+		node.PingSent, _ = strconv.ParseInt(fields[4], 10, 64)
+		node.PongRecv, _ = strconv.ParseInt(fields[5], 10, 64)
+		node.ConfigEpoch, _ = strconv.ParseInt(fields[6], 10, 64)
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				continue // migration in progress
+			}
+			var r SlotRange
+			first, last, isRange := strings.Cut(slot, "-")
+			r.First, _ = strconv.ParseInt(first, 10, 64)
+			r.Last = r.First
+			if isRange {
+				r.Last, _ = strconv.ParseInt(last, 10, 64)
+			}
+			node.Slots = append(node.Slots, r)
+		}
+
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func readClusterSlots(r *connReader) ([]ClusterSlots, error) {
+	n, err := readArrayLen(r)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]ClusterSlots, n)
+	for i := range slots {
+		elementN, err := readArrayLen(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: CLUSTER SLOTS entry: %w", err)
+		}
+		if elementN < 2 {
+			return nil, fmt.Errorf("%w; CLUSTER SLOTS entry with %d elements", errProtocol, elementN)
+		}
+		slots[i].First, err = readInteger(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: CLUSTER SLOTS start: %w", err)
+		}
+		slots[i].Last, err = readInteger(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: CLUSTER SLOTS end: %w", err)
+		}
+
+		slots[i].Nodes = make([]ClusterSlotsNode, elementN-2)
+		for j := range slots[i].Nodes {
+			node := &slots[i].Nodes[j]
+			nodeN, err := readArrayLen(r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: CLUSTER SLOTS node: %w", err)
+			}
+			if nodeN < 2 {
+				return nil, fmt.Errorf("%w; CLUSTER SLOTS node with %d elements", errProtocol, nodeN)
+			}
+			node.Host, err = readBulk[string](r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: CLUSTER SLOTS node host: %w", err)
+			}
+			node.Port, err = readInteger(r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: CLUSTER SLOTS node port: %w", err)
+			}
+			if nodeN > 2 {
+				node.ID, err = readBulk[string](r)
+				if err != nil {
+					return nil, fmt.Errorf("redis: CLUSTER SLOTS node ID: %w", err)
+				}
+			}
+			// skip networking metadata (since Redis 7)
+			for ; nodeN > 3; nodeN-- {
+				if err := discardReply(r); err != nil {
+					return nil, fmt.Errorf("redis: CLUSTER SLOTS node metadata: %w", err)
+				}
+			}
+		}
+	}
+	return slots, nil
+}
+
+func readClusterShards(r *connReader) ([]ClusterShard, error) {
+	n, err := readArrayLen(r)
+	if err != nil {
+		return nil, err
+	}
+	shards := make([]ClusterShard, n)
+	for i := range shards {
+		err := readMapFunc(r, func(name string) error {
+			switch name {
+			case "slots":
+				bounds, err := readArray[[]byte](r)
+				if err != nil {
+					return err
+				}
+				for j := 0; j+1 < len(bounds); j += 2 {
+					shards[i].Slots = append(shards[i].Slots, SlotRange{
+						First: ParseInt(bounds[j]),
+						Last:  ParseInt(bounds[j+1]),
+					})
+				}
+				return nil
+
+			case "nodes":
+				nodeN, err := readArrayLen(r)
+				if err != nil {
+					return err
+				}
+				shards[i].Nodes = make([]ClusterShardNode, nodeN)
+				for j := range shards[i].Nodes {
+					err := readClusterShardNode(r, &shards[i].Nodes[j])
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+
+			default:
+				return discardReply(r)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("redis: CLUSTER SHARDS entry: %w", err)
+		}
+	}
+	return shards, nil
+}
+
+func readClusterShardNode(r *connReader, node *ClusterShardNode) error {
+	return readMapFunc(r, func(name string) error {
+		switch name {
+		case "id", "endpoint", "ip", "hostname", "role", "health":
+			s, err := readBulk[string](r)
+			switch name {
+			case "id":
+				node.ID = s
+			case "endpoint":
+				node.Endpoint = s
+			case "ip":
+				node.IP = s
+			case "hostname":
+				node.Hostname = s
+			case "role":
+				node.Role = s
+			case "health":
+				node.Health = s
+			}
+			return err
+
+		case "port", "tls-port", "replication-offset":
+			n, err := readInteger(r)
+			switch name {
+			case "port":
+				node.Port = n
+			case "tls-port":
+				node.TLSPort = n
+			case "replication-offset":
+				node.ReplicationOffset = n
+			}
+			return err
+
+		default:
+			return discardReply(r)
+		}
+	})
+}
+
+// ReadMapFunc reads a map (or a RESP2 array with key–value pairs) with string
+// keys. The value of each entry must be consumed by f.
+func readMapFunc(r *connReader, f func(name string) error) error {
+	n, err := readArrayLen(r)
+	if err != nil {
+		return err
+	}
+	if n&1 != 0 {
+		return fmt.Errorf("%w; odd number of elements (%d) for map", errProtocol, n)
+	}
+	for ; n > 0; n -= 2 {
+		name, err := readBulk[string](r)
+		if err != nil {
+			return err
+		}
+		if err := f(name); err != nil {
+			return fmt.Errorf("%q: %w", name, err)
+		}
+	}
+	return nil
+}
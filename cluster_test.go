@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseClusterInfo(t *testing.T) {
+	info := parseClusterInfo("cluster_state:ok\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\ncluster_slots_pfail:0\r\ncluster_slots_fail:0\r\ncluster_known_nodes:6\r\ncluster_size:3\r\ncluster_current_epoch:6\r\ncluster_my_epoch:2\r\ncluster_stats_messages_sent:1483972\r\n")
+	want := ClusterInfo{
+		State:         "ok",
+		SlotsAssigned: 16384,
+		SlotsOK:       16384,
+		KnownNodes:    6,
+		Size:          3,
+		CurrentEpoch:  6,
+		MyEpoch:       2,
+	}
+	want.Fields = info.Fields
+	if !reflect.DeepEqual(*info, want) {
+		t.Errorf("got %+v, want %+v", *info, want)
+	}
+	if got := info.Fields["cluster_stats_messages_sent"]; got != "1483972" {
+		t.Errorf("got cluster_stats_messages_sent %q, want 1483972", got)
+	}
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	const text = "07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004,host4.example.com slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected\n" +
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 5461 [5462->-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]\n"
+
+	nodes, err := parseClusterNodes(text)
+	if err != nil {
+		t.Fatal("parse error:", err)
+	}
+	want := []ClusterNode{{
+		ID:          "07c37dfeb235213a872192d90877d0cd55635b91",
+		Addr:        "127.0.0.1:30004",
+		BusPort:     31004,
+		Hostname:    "host4.example.com",
+		Flags:       []string{"slave"},
+		PrimaryID:   "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+		PongRecv:    1426238317239,
+		ConfigEpoch: 4,
+		Connected:   true,
+	}, {
+		ID:          "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+		Addr:        "127.0.0.1:30001",
+		BusPort:     31001,
+		Flags:       []string{"myself", "master"},
+		ConfigEpoch: 1,
+		Connected:   true,
+		Slots:       []SlotRange{{0, 5460}, {5461, 5461}},
+	}}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("got %+v\nwant %+v", nodes, want)
+	}
+}
+
+func TestReadClusterSlots(t *testing.T) {
+	const reply = "*1\r\n*4\r\n:0\r\n:5460\r\n" +
+		"*4\r\n$9\r\n127.0.0.1\r\n:30001\r\n$4\r\nabcd\r\n*0\r\n" +
+		"*3\r\n$9\r\n127.0.0.1\r\n:30004\r\n$4\r\nefgh\r\n" +
+		"+OK\r\n"
+	r := testReader(reply)
+	slots, err := readClusterSlots(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	want := []ClusterSlots{{
+		SlotRange: SlotRange{0, 5460},
+		Nodes: []ClusterSlotsNode{
+			{Host: "127.0.0.1", Port: 30001, ID: "abcd"},
+			{Host: "127.0.0.1", Port: 30004, ID: "efgh"},
+		},
+	}}
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("got %+v\nwant %+v", slots, want)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after CLUSTER SLOTS reply got error:", err)
+	}
+}
+
+func TestReadClusterShards(t *testing.T) {
+	const reply = "*1\r\n%2\r\n" +
+		"$5\r\nslots\r\n*2\r\n:0\r\n:5460\r\n" +
+		"$5\r\nnodes\r\n*1\r\n%5\r\n" +
+		"$2\r\nid\r\n$4\r\nabcd\r\n" +
+		"$4\r\nport\r\n:30001\r\n" +
+		"$4\r\nrole\r\n$6\r\nmaster\r\n" +
+		"$18\r\nreplication-offset\r\n:72156\r\n" +
+		"$6\r\nfuture\r\n*1\r\n$3\r\nfoo\r\n" +
+		"+OK\r\n"
+	r := testReader(reply)
+	shards, err := readClusterShards(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	want := []ClusterShard{{
+		Slots: []SlotRange{{0, 5460}},
+		Nodes: []ClusterShardNode{{
+			ID:                "abcd",
+			Port:              30001,
+			Role:              "master",
+			ReplicationOffset: 72156,
+		}},
+	}}
+	if !reflect.DeepEqual(shards, want) {
+		t.Errorf("got %+v\nwant %+v", shards, want)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after CLUSTER SHARDS reply got error:", err)
+	}
+}
+
+func TestClusterInfo(t *testing.T) {
+	info, err := testClient.CLUSTERINFO()
+	var e ServerError
+	if errors.As(err, &e) {
+		t.Skip("no cluster:", err)
+	}
+	if err != nil {
+		t.Fatal("CLUSTER INFO error:", err)
+	}
+	if info.State != "ok" && info.State != "fail" {
+		t.Errorf("got cluster state %q", info.State)
+	}
+
+	id, err := testClient.CLUSTERMYID()
+	if err != nil {
+		t.Fatal("CLUSTER MYID error:", err)
+	}
+	nodes, err := testClient.CLUSTERNODES()
+	if err != nil {
+		t.Fatal("CLUSTER NODES error:", err)
+	}
+	for _, node := range nodes {
+		if node.ID == id {
+			return
+		}
+	}
+	t.Errorf("CLUSTER MYID %q not in CLUSTER NODES %+v", id, nodes)
+}
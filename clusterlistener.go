@@ -0,0 +1,45 @@
+package redis
+
+import "strings"
+
+// ClusterListener subscribes on one node of a Redis Cluster at a time, with
+// failover to the other nodes. Redis Cluster propagates PUBLISH to all of its
+// nodes, such that any node delivers each message. Subscription on more than
+// one node would just multiply the traffic.
+//
+// Sharded pub/sub, i.e., SSUBSCRIBE with SPUBLISH, is not supported.
+//
+// The embedded Listener operates as documented, with the node addresses as a
+// failover sequence. Multiple goroutines may invoke methods on a
+// ClusterListener simultaneously.
+type ClusterListener struct {
+	*Listener
+}
+
+var _ Subscriber = (*ClusterListener)(nil)
+
+// NewClusterListener launches a managed connection to one of the nodes. Addr in
+// config has the node addresses separated by comma, as in "rds1.example.com,
+// rds2.example.com:6380". See SetAddr for topology changes.
+func NewClusterListener(config ListenerConfig) *ClusterListener {
+	return &ClusterListener{NewListener(config)}
+}
+
+// SetAddr replaces the node addresses, separated by comma, e.g., from the
+// CLUSTERNODES of a Client. The connection in use remains, unless its node is
+// absent in addr, in which case the subscriptions continue on another node.
+// Any messages published in between are lost.
+func (c *ClusterListener) SetAddr(addr string) {
+	addr = normalizeAddr(addr)
+	c.Listener.SetAddr(addr)
+
+	c.mutex.Lock()
+	current := c.connAddr
+	c.mutex.Unlock()
+	for _, a := range strings.Split(addr, ",") {
+		if a == current {
+			return // node remains
+		}
+	}
+	c.ForceReconnect()
+}
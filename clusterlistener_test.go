@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// TCPProxy forwards each connection to addr until the test ends. The return
+// is another network address for the same node.
+func tcpProxy(t *testing.T, addr string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", addr)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			t.Cleanup(func() { conn.Close(); upstream.Close() })
+			go io.Copy(upstream, conn)
+			go io.Copy(conn, upstream)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestClusterListener(t *testing.T) {
+	t.Parallel()
+
+	calls := make(chan *listenerCall, 99)
+	connects := make(chan struct{}, 9)
+	otherAddr := tcpProxy(t, testClient.Addr)
+	l := NewClusterListener(ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			calls <- &listenerCall{channel, string(message), err}
+		},
+		OnConnect: func(channels, patterns []string) {
+			connects <- struct{}{}
+		},
+
+		Addr:     testClient.Addr + "," + otherAddr,
+		Password: testClient.Password,
+		Username: testClient.Username,
+	})
+	defer l.Close()
+
+	channel := randomKey("channel")
+	if err := <-l.SUBSCRIBEAck(channel); err != nil {
+		t.Fatal("SUBSCRIBEAck error:", err)
+	}
+	<-connects
+	publishAndVerify := func() {
+		t.Helper()
+		for _, message := range []string{"one", "two", "one"} {
+			if n, err := testClient.PUBLISH(channel, message); err != nil {
+				t.Fatal("PUBLISH error:", err)
+			} else if n != 1 {
+				t.Errorf("PUBLISH got %d clients, want 1", n)
+			}
+		}
+
+		timeout := time.After(200 * time.Millisecond)
+		var got []string
+		for done := false; !done; {
+			select {
+			case call := <-calls:
+				if call.err != nil {
+					t.Error("Listener called with error:", call.err)
+				} else if call.channel != channel {
+					t.Errorf("Listener called with channel %q, want %q", call.channel, channel)
+				}
+				got = append(got, call.message)
+			case <-timeout:
+				done = true
+			}
+		}
+		if len(got) != 3 || got[0] != "one" || got[1] != "two" || got[2] != "one" {
+			t.Errorf("got messages %q, want [\"one\" \"two\" \"one\"]", got)
+		}
+	}
+	publishAndVerify()
+
+	// node in use remains
+	l.SetAddr(otherAddr + "," + testClient.Addr)
+	select {
+	case <-connects:
+		t.Error("reconnect while node remains")
+	case <-time.After(100 * time.Millisecond):
+		break
+	}
+
+	// node in use gone
+	l.SetAddr(otherAddr)
+	select {
+	case <-connects:
+		break
+	case <-time.After(time.Second):
+		t.Fatal("no reconnect on removal of node in use")
+	}
+	if err := <-l.SUBSCRIBEAck(channel); err != nil {
+		t.Fatal("SUBSCRIBEAck error after reconnect:", err)
+	}
+	publishAndVerify()
+}
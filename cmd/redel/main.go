@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to SELECT.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	countFlag    = flag.Int64("count", 0, "Hint the `number` of keys per SCAN when positive.")
+	batchFlag    = flag.Int("batch", 100, "Limit the `number` of keys per UNLINK.")
+	pipelineFlag = flag.Int("pipeline", 4, "Limit the `number` of UNLINK commands in progress.")
+	rateFlag     = flag.Int("rate", 0, "Limit the `number` of keys per second when positive.")
+	quietFlag    = flag.Bool("q", false, "Omit progress reports on the standard error.")
+)
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 1 || args[0] == "" {
+		os.Stderr.WriteString(`NAME
+	redel — remove Redis keys by pattern
+
+SYNOPSIS
+	redel [ options ] pattern
+
+DESCRIPTION
+	Redel removes each key on the node which matches the glob-style
+	pattern, with SCAN iteration, and with UNLINK in batches. Keys
+	created during the run may remain. Progress is reported on the
+	standard error, with the number of keys matched and removed.
+
+	An interrupt stops the run once the pending commands are done,
+	with an exit status of 1.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		DB:             *dbFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		config.Password, _ = ioutil.ReadAll(os.Stdin)
+	}
+	client := redis.NewClient[string, []byte](config)
+	defer client.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	options := redis.UNLINKMatchOptions{
+		Count:     *countFlag,
+		BatchSize: *batchFlag,
+		Pipeline:  *pipelineFlag,
+		RateLimit: *rateFlag,
+	}
+	if !*quietFlag {
+		options.ProgressFunc = func(p redis.UNLINKMatchProgress) {
+			fmt.Fprintf(os.Stderr, "redel: %d matched, %d removed\n", p.Matched, p.Removed)
+		}
+	}
+
+	removed, err := client.WithContext(ctx).UNLINKMatch(args[0], options)
+	switch {
+	case err == nil:
+		fmt.Fprintf(os.Stderr, "redel: %d keys removed\n", removed)
+	case ctx.Err() != nil:
+		fmt.Fprintf(os.Stderr, "redel: interrupted with %d keys removed\n", removed)
+		os.Exit(1)
+	default:
+		fmt.Fprintf(os.Stderr, "redel: %s, with %d keys removed\n", err, removed)
+		os.Exit(255)
+	}
+}
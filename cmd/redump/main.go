@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input. The same\npassword applies to the node of -to, if any.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to SELECT.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	countFlag = flag.Int64("count", 0, "Hint the `number` of keys per SCAN when positive.")
+
+	restoreFlag = flag.Bool("restore", false, "Read a dump from the standard input, and RESTORE its keys\non the node, instead of writing a dump.")
+	toFlag      = flag.String("to", "", "Copy keys to the node at `address`, instead of writing a\ndump.")
+	toDBFlag    = flag.Int64("todb", 0, "Logical database `number` for -to.")
+	replaceFlag = flag.Bool("replace", false, "Overwrite existing keys on RESTORE, instead of skipping\nthem.")
+)
+
+// Magic is the header of the dump format.
+const magic = "REDUMP1\n"
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	if len(args) > 1 || *restoreFlag && (len(args) != 0 || *toFlag != "") {
+		os.Stderr.WriteString(`NAME
+	redump — copy Redis keys
+
+SYNOPSIS
+	redump [ options ] [ pattern ] > file
+	redump [ options ] -restore < file
+	redump [ options ] -to address [ pattern ]
+
+DESCRIPTION
+	Redump walks each key on the node which matches the glob-style
+	pattern, or all keys in the absence of a pattern, with SCAN. Keys
+	are serialized with DUMP, together with their time to live, for
+	a RESTORE later on, or on another node with -to. The format of
+	DUMP depends on the Redis version. Expiry applies relative to the
+	moment of RESTORE. Keys which are modified during the walk may or
+	may not be included.
+
+	Existing keys are skipped on RESTORE, unless -replace is set. The
+	exit status is 1 when one or more keys were skipped.
+
+	The dump starts with the line "REDUMP1", followed by a record per
+	key. Records consist of the key, the time to live in milliseconds
+	(or zero for no expiry), and the serialization. Each of them is
+	prefixed with its length (or value) as an unsigned varint.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	var pattern string
+	if len(args) != 0 {
+		pattern = args[0]
+	}
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		DB:             *dbFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		var err error
+		config.Password, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "redump: standard input with", err)
+			os.Exit(255)
+		}
+		if *restoreFlag {
+			fmt.Fprintln(os.Stderr, "redump: auth conflicts with restore from standard input")
+			os.Exit(2)
+		}
+	}
+	from := redis.NewClient[string, []byte](config)
+	defer from.Close()
+
+	var skipped int
+	switch {
+	case *restoreFlag:
+		skipped = restore(from, bufio.NewReader(os.Stdin))
+
+	case *toFlag != "":
+		config.Addr = *toFlag
+		config.DB = *toDBFlag
+		to := redis.NewClient[string, []byte](config)
+		defer to.Close()
+
+		walk(from, pattern, func(key string, ttl int64, dump []byte) {
+			if !put(to, key, ttl, dump) {
+				skipped++
+			}
+		})
+
+	default:
+		w := bufio.NewWriter(os.Stdout)
+		w.WriteString(magic)
+		var buf [binary.MaxVarintLen64]byte
+		walk(from, pattern, func(key string, ttl int64, dump []byte) {
+			w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+			w.WriteString(key)
+			w.Write(buf[:binary.PutUvarint(buf[:], uint64(ttl))])
+			w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(dump)))])
+			w.Write(dump)
+		})
+		if err := w.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, "redump: output with", err)
+			os.Exit(255)
+		}
+	}
+
+	if skipped != 0 {
+		fmt.Fprintf(os.Stderr, "redump: %d existing keys skipped\n", skipped)
+		os.Exit(1)
+	}
+}
+
+// Walk passes each key on the node which matches pattern to f, with its time
+// to live in milliseconds, or zero for no expiry. Keys which expire during the
+// walk are omitted.
+func walk(c *redis.Client[string, []byte], pattern string, f func(key string, ttl int64, dump []byte)) {
+	var cursor uint64
+	for {
+		next, keys, err := c.SCAN(cursor, pattern, *countFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "redump: SCAN %d with %s\n", cursor, err)
+			os.Exit(255)
+		}
+
+		for _, key := range keys {
+			ttl, err := c.PTTL(key)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "redump: PTTL %q with %s\n", key, err)
+				os.Exit(255)
+			}
+			switch ttl {
+			case -2:
+				continue // expired meanwhile
+			case -1:
+				ttl = 0 // no expiry
+			}
+
+			dump, err := c.DUMP(key)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "redump: DUMP %q with %s\n", key, err)
+				os.Exit(255)
+			}
+			if len(dump) == 0 {
+				continue // expired meanwhile
+			}
+			f(key, ttl, dump)
+		}
+
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// Restore applies each record from r on the node. The return is the number of
+// keys skipped.
+func restore(c *redis.Client[string, []byte], r *bufio.Reader) (skipped int) {
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != magic {
+		fmt.Fprintln(os.Stderr, "redump: standard input is not a dump")
+		os.Exit(2)
+	}
+
+	for {
+		keySize, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return skipped
+		}
+		key := readRecordBytes(r, keySize, err)
+		ttl, err := binary.ReadUvarint(r)
+		if err != nil {
+			dumpError(err)
+		}
+		dumpSize, err := binary.ReadUvarint(r)
+		dump := readRecordBytes(r, dumpSize, err)
+
+		if !put(c, string(key), int64(ttl), dump) {
+			skipped++
+		}
+	}
+}
+
+// ReadRecordBytes reads size bytes from r, unless err is set.
+func readRecordBytes(r *bufio.Reader, size uint64, err error) []byte {
+	if err != nil {
+		dumpError(err)
+	}
+	if size > redis.SizeMax {
+		dumpError(fmt.Errorf("record of %d bytes exceeds the maximum", size))
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		dumpError(err)
+	}
+	return buf
+}
+
+func dumpError(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	fmt.Fprintln(os.Stderr, "redump: dump from standard input with", err)
+	os.Exit(255)
+}
+
+// Put executes RESTORE. The return is false when the key was skipped.
+func put(c *redis.Client[string, []byte], key string, ttl int64, dump []byte) bool {
+	err := c.RESTORE(key, ttl, dump, *replaceFlag)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, redis.ErrBusyKey):
+		return false
+	default:
+		fmt.Fprintf(os.Stderr, "redump: RESTORE %q with %s\n", key, err)
+		os.Exit(255)
+		return false
+	}
+}
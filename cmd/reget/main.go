@@ -1,20 +1,36 @@
 package main
 
 import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pascaldekloe/redis/v2"
+	"github.com/pascaldekloe/redis/v2/internal/cli"
 )
 
 var (
 	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
-	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input. The password\nis the first line (or record) when keys come from the\nstandard input too.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to SELECT.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	nulFlag   = flag.Bool("0", false, "Keys from the standard input are NUL delimited, instead\nof newline delimited.")
+	batchFlag = flag.Int("batch", 512, "Maximum `number` of keys per MGET from the standard input.")
+
+	typeFlag = flag.String("type", "string,hash,list,set,zset", "Comma separated `list` of types to resolve. Keys of any\nother type print as absent.")
 
 	rawFlag       = flag.Bool("raw", false, "Output values as is, instead of quoted strings.")
+	base64Flag    = flag.Bool("base64", false, "Output values in base64 encoding, instead of quoted strings.")
+	jsonFlag      = flag.Bool("json", false, "Output a JSON object with the keys mapped to their value,\nor null for key absence. Values are JSON strings, with\ninvalid UTF-8 replaced, unless base64 is set.")
 	delimitFlag   = flag.String("delimit", "\n", "The output `separator` between values.")
 	terminateFlag = flag.String("terminate", "\n", "The output `suffix` on the last value.")
 	nullFlag      = flag.String("null", "<null>", "The output `value` for key absence.")
@@ -23,10 +39,14 @@ var (
 // Redis manages the connection.
 var Redis *redis.Client[string, []byte]
 
+// ResolveTypes has the data types enabled.
+var resolveTypes = make(map[string]bool)
+
 func main() {
 	flag.Parse()
 	keys := flag.Args()
-	if len(keys) == 0 {
+	// keys from standard input, unless interactive
+	if len(keys) == 0 && cli.IsTerminal(os.Stdin) {
 		os.Stderr.WriteString(`NAME
 	reget — resolve Redis content
 
@@ -35,7 +55,14 @@ SYNOPSIS
 
 DESCRIPTION
 	For each operand, reget prints the associated value according to
-	the node.
+	the node. Keys are read from the standard input in the absence of
+	any operands.
+
+	Keys other than strings print their content. Lists print as in
+	["a" "b"], sets as in {"a" "b"}, hashes as in {"field":"value"},
+	and sorted sets as in {"member":1.5}, all in order of the node.
+	JSON output has arrays for lists and sets, and objects for both
+	hashes and sorted sets.
 
 	The following options are available:
 
@@ -43,39 +70,286 @@ DESCRIPTION
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
+	if *rawFlag && *base64Flag {
+		fmt.Fprintln(os.Stderr, "reget: raw and base64 are mutually exclusive")
+		os.Exit(2)
+	}
+	for _, t := range strings.Split(*typeFlag, ",") {
+		switch t {
+		case "string", "hash", "list", "set", "zset":
+			resolveTypes[t] = true
+		default:
+			fmt.Fprintf(os.Stderr, "reget: type %q not supported\n", t)
+			os.Exit(2)
+		}
+	}
+	if *batchFlag < 1 {
+		fmt.Fprintln(os.Stderr, "reget: batch must be positive")
+		os.Exit(2)
+	}
+
+	delim := byte('\n')
+	if *nulFlag {
+		delim = 0
+	}
+	stdin := bufio.NewReader(os.Stdin)
 
-	config := redis.ClientConfig{Addr: *addrFlag}
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		DB:             *dbFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
 	if *authFlag {
-		config.Password, _ = ioutil.ReadAll(os.Stdin)
+		if len(keys) == 0 {
+			password, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				fmt.Fprintln(os.Stderr, "reget: no password on standard input")
+				os.Exit(2)
+			}
+			config.Password = []byte(password)
+		} else {
+			config.Password, _ = ioutil.ReadAll(stdin)
+		}
 	}
 	Redis = redis.NewClient[string, []byte](config)
 	defer Redis.Close()
 
-	print(keys)
+	out := output{w: bufio.NewWriter(os.Stdout)}
+	if len(keys) != 0 {
+		out.print(keys)
+	} else {
+		batch := make([]string, 0, *batchFlag)
+		for {
+			key, ok := cli.ReadRecord(stdin, delim)
+			if ok {
+				batch = append(batch, key)
+			}
+			if len(batch) == cap(batch) || !ok && len(batch) != 0 {
+				out.print(batch)
+				batch = batch[:0]
+			}
+			if !ok {
+				break
+			}
+		}
+	}
+	out.finish()
 }
 
-func print(keys []string) {
-	values, err := Redis.MGET(keys...)
+// Output streams values in the configured format.
+type output struct {
+	w     *bufio.Writer
+	count int // number of values written
+}
+
+// Value is the content of a key.
+type value struct {
+	typ string // "none" on absence
+
+	bulk     []byte   // string content
+	elements [][]byte // list, set, or hash and sorted set (member) keys
+	fields   [][]byte // hash values
+	scores   []float64
+}
+
+// Print resolves the keys, and it writes their values.
+func (out *output) print(keys []string) {
+	bulks, err := Redis.MGET(keys...)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "reget: MGET with", err)
 		os.Exit(255)
 	}
 
-	w := os.Stdout
-	for i, v := range values {
-		switch {
-		case v == nil:
-			w.WriteString(*nullFlag)
-		case *rawFlag:
-			w.Write(v)
-		default:
-			w.WriteString(strconv.QuoteToGraphic(string(v)))
+	for i, bulk := range bulks {
+		v := value{typ: "string", bulk: bulk}
+		if bulk == nil {
+			// absent or not a string
+			v = resolve(keys[i])
+		}
+		if !resolveTypes[v.typ] {
+			v = value{typ: "none"}
+		}
+
+		if *jsonFlag {
+			out.writeJSON(keys[i], &v)
+		} else {
+			out.writeText(&v)
+		}
+		out.count++
+	}
+}
+
+// Resolve gets the content of any type other than string.
+func resolve(key string) value {
+	typ, err := Redis.TYPE(key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reget: TYPE %q with %s\n", key, err)
+		os.Exit(255)
+	}
+	v := value{typ: typ}
+	if !resolveTypes[typ] {
+		return v
+	}
+
+	switch typ {
+	case "list":
+		v.elements, err = Redis.LRANGE(key, 0, -1)
+	case "set":
+		v.elements, err = Redis.SMEMBERS(key)
+	case "hash":
+		var fields []string
+		fields, v.fields, err = Redis.HGETALL(key)
+		v.elements = make([][]byte, len(fields))
+		for i := range fields {
+			v.elements[i] = []byte(fields[i])
 		}
+	case "zset":
+		v.elements, v.scores, err = Redis.ZRANGEWithScores(key, 0, -1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reget: %s %q content with %s\n", typ, key, err)
+		os.Exit(255)
+	}
+	return v
+}
+
+// Finish terminates the output.
+func (out *output) finish() {
+	switch {
+	case *jsonFlag:
+		if out.count == 0 {
+			out.w.WriteByte('{')
+		}
+		out.w.WriteString("}\n")
+	case out.count != 0:
+		out.w.WriteString(*terminateFlag)
+	}
 
-		if i < len(values)-1 {
-			w.WriteString(*delimitFlag)
+	if err := out.w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "reget: output with", err)
+		os.Exit(255)
+	}
+}
+
+func (out *output) writeText(v *value) {
+	if out.count != 0 {
+		out.w.WriteString(*delimitFlag)
+	}
+
+	switch v.typ {
+	case "none":
+		out.w.WriteString(*nullFlag)
+	case "string":
+		out.writeBulk(v.bulk)
+	case "list", "set":
+		if v.typ == "list" {
+			out.w.WriteByte('[')
+		} else {
+			out.w.WriteByte('{')
+		}
+		for i, e := range v.elements {
+			if i != 0 {
+				out.w.WriteByte(' ')
+			}
+			out.writeBulk(e)
+		}
+		if v.typ == "list" {
+			out.w.WriteByte(']')
 		} else {
-			w.WriteString(*terminateFlag)
+			out.w.WriteByte('}')
 		}
+	case "hash", "zset":
+		out.w.WriteByte('{')
+		for i, e := range v.elements {
+			if i != 0 {
+				out.w.WriteByte(' ')
+			}
+			out.writeBulk(e)
+			out.w.WriteByte(':')
+			if v.typ == "hash" {
+				out.writeBulk(v.fields[i])
+			} else {
+				out.w.WriteString(strconv.FormatFloat(v.scores[i], 'g', -1, 64))
+			}
+		}
+		out.w.WriteByte('}')
+	}
+}
+
+func (out *output) writeBulk(b []byte) {
+	switch {
+	case *rawFlag:
+		out.w.Write(b)
+	case *base64Flag:
+		out.w.WriteString(base64.StdEncoding.EncodeToString(b))
+	default:
+		out.w.WriteString(strconv.QuoteToGraphic(string(b)))
+	}
+}
+
+// WriteJSON writes an object member in order of appearance. Duplicate keys are
+// written as such.
+func (out *output) writeJSON(key string, v *value) {
+	if out.count == 0 {
+		out.w.WriteByte('{')
+	} else {
+		out.w.WriteByte(',')
+	}
+	out.writeJSONString([]byte(key), false)
+	out.w.WriteByte(':')
+
+	switch v.typ {
+	case "none":
+		out.w.WriteString("null")
+	case "string":
+		out.writeJSONString(v.bulk, *base64Flag)
+	case "list", "set":
+		out.w.WriteByte('[')
+		for i, e := range v.elements {
+			if i != 0 {
+				out.w.WriteByte(',')
+			}
+			out.writeJSONString(e, *base64Flag)
+		}
+		out.w.WriteByte(']')
+	case "hash", "zset":
+		out.w.WriteByte('{')
+		for i, e := range v.elements {
+			if i != 0 {
+				out.w.WriteByte(',')
+			}
+			out.writeJSONString(e, *base64Flag)
+			out.w.WriteByte(':')
+			if v.typ == "hash" {
+				out.writeJSONString(v.fields[i], *base64Flag)
+			} else {
+				out.writeJSONNumber(v.scores[i])
+			}
+		}
+		out.w.WriteByte('}')
+	}
+}
+
+func (out *output) writeJSONString(b []byte, base64Encoding bool) {
+	if base64Encoding {
+		out.w.WriteByte('"')
+		out.w.WriteString(base64.StdEncoding.EncodeToString(b))
+		out.w.WriteByte('"')
+		return
+	}
+	text, _ := json.Marshal(string(b))
+	out.w.Write(text)
+}
+
+// WriteJSONNumber writes infinity as a string, as JSON has no such number.
+func (out *output) writeJSONNumber(f float64) {
+	switch {
+	case math.IsInf(f, 1):
+		out.w.WriteString(`"inf"`)
+	case math.IsInf(f, -1):
+		out.w.WriteString(`"-inf"`)
+	default:
+		out.w.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
 	}
 }
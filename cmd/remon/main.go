@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to observe.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	configFlag = flag.String("config", "", "Set notify-keyspace-events to `flags` (e.g., \"KEA\" for\nall events) during the run. The previous setting is\nrestored on exit.")
+	eventsFlag = flag.String("events", "", "Comma separated `list` of events to print, such as\n\"expired,evicted\". All events print when empty.")
+	countFlag  = flag.Int("count", 0, "Exit after `number` events when positive.")
+
+	jsonFlag = flag.Bool("json", false, "Output a JSON object per line, with the event and the\nkey. Keys are JSON strings, with invalid UTF-8 replaced.")
+	timeFlag = flag.Bool("time", false, "Output the time of reception with each event, in\nRFC 3339 format.")
+)
+
+// NotifyConfig is the parameter for keyspace notifications.
+const notifyConfig = "notify-keyspace-events"
+
+// Out has events written in the configured format.
+var out = bufio.NewWriter(os.Stdout)
+
+// Events has the filter, with nil for all events.
+var events map[string]bool
+
+// ChannelPrefix precedes each pattern in subscriptions.
+var channelPrefix string
+
+// Received counts the events written.
+var received int
+
+// Done signals the count limit.
+var done = make(chan struct{})
+
+func main() {
+	flag.Usage = printUsage
+	flag.Parse()
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+
+	if *eventsFlag != "" {
+		events = make(map[string]bool)
+		for _, e := range strings.Split(*eventsFlag, ",") {
+			events[e] = true
+		}
+	}
+	channelPrefix = "__keyspace@" + strconv.FormatInt(*dbFlag, 10) + "__:"
+
+	var password []byte
+	if *authFlag {
+		password, _ = ioutil.ReadAll(os.Stdin)
+	}
+
+	Redis := redis.NewClient[string, string](redis.ClientConfig{
+		Addr:           *addrFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+		Password:       password,
+	})
+	defer Redis.Close()
+	if *configFlag != "" {
+		config, err := Redis.CONFIGGET(notifyConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "remon: CONFIG GET with", err)
+			os.Exit(255)
+		}
+		previous := config[notifyConfig]
+		if err := Redis.NotifyKeyspaceEvents(*configFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "remon: CONFIG SET with", err)
+			os.Exit(255)
+		}
+		defer func() {
+			if err := Redis.NotifyKeyspaceEvents(previous); err != nil {
+				fmt.Fprintf(os.Stderr, "remon: CONFIG SET %s %q (restore) with %s\n", notifyConfig, previous, err)
+			}
+		}()
+	} else if config, err := Redis.CONFIGGET(notifyConfig); err == nil && config[notifyConfig] == "" {
+		fmt.Fprintln(os.Stderr, "remon: keyspace notifications disabled on the node; see the -config option")
+	}
+
+	l := redis.NewListener(redis.ListenerConfig{
+		Func:           onMessage,
+		PatternFunc:    onPatternMessage,
+		Addr:           *addrFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+		Password:       password,
+	})
+	channels := make([]string, len(patterns))
+	for i, p := range patterns {
+		channels[i] = channelPrefix + p
+	}
+	if err := <-l.PSUBSCRIBEAck(channels...); err != nil {
+		fmt.Fprintln(os.Stderr, "remon: subscription with", err)
+		l.Close()
+		os.Exit(255)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-interrupt:
+	case <-done:
+	}
+	// Close awaits any pending callback.
+	l.Close()
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "remon: output with", err)
+	}
+}
+
+func printUsage() {
+	os.Stderr.WriteString(`NAME
+	remon — observe Redis keys
+
+SYNOPSIS
+	remon [ options ] [ pattern ... ]
+
+DESCRIPTION
+	Remon prints keyspace notifications for each key which matches
+	any of the glob-style patterns, or for all keys in the absence of
+	any pattern, until interrupted. Each line has the event, such as
+	"set", "del", "expired" or "evicted", followed by the quoted key.
+
+	Notifications must be enabled on the node, either with the -config
+	option, or with the notify-keyspace-events parameter directly. Note
+	that the K flag is required for keyspace events.
+
+	The following options are available:
+
+`)
+	flag.PrintDefaults()
+	os.Exit(1)
+}
+
+// OnMessage is the Func of the Listener. Callbacks are sequential.
+func onMessage(channel string, message []byte, err error) {
+	if err != nil {
+		if !errors.Is(err, redis.ErrClosed) {
+			fmt.Fprintln(os.Stderr, "remon:", err)
+		}
+		return
+	}
+	onPatternMessage("", channel, message)
+}
+
+// OnPatternMessage is the PatternFunc of the Listener.
+func onPatternMessage(pattern, channel string, message []byte) {
+	event, ok := redis.ParseKeyspaceEvent(channel, message)
+	if !ok || event.DB != *dbFlag {
+		return // not a keyspace notification
+	}
+	if events != nil && !events[event.Op] {
+		return
+	}
+	if *countFlag > 0 && received >= *countFlag {
+		return // awaiting close
+	}
+
+	if *jsonFlag {
+		out.WriteByte('{')
+		if *timeFlag {
+			out.WriteString(`"time":"`)
+			out.WriteString(time.Now().Format(time.RFC3339Nano))
+			out.WriteString(`",`)
+		}
+		out.WriteString(`"event":`)
+		writeJSONString(event.Op)
+		out.WriteString(`,"key":`)
+		writeJSONString(event.Key)
+		out.WriteString("}\n")
+	} else {
+		if *timeFlag {
+			out.WriteString(time.Now().Format(time.RFC3339Nano))
+			out.WriteByte(' ')
+		}
+		out.WriteString(event.Op)
+		out.WriteByte(' ')
+		out.WriteString(strconv.QuoteToGraphic(event.Key))
+		out.WriteByte('\n')
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "remon: output with", err)
+		os.Exit(255)
+	}
+
+	received++
+	if received == *countFlag {
+		close(done)
+	}
+}
+
+func writeJSONString(s string) {
+	text, _ := json.Marshal(s)
+	out.Write(text)
+}
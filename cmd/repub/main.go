@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/pascaldekloe/redis/v2/internal/cli"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input. The password\nis the first line (or record) when messages come from the\nstandard input too.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	nulFlag  = flag.Bool("0", false, "Messages from the standard input are NUL delimited,\ninstead of newline delimited.")
+	rateFlag = flag.Float64("rate", 0, "Maximum `number` of messages per second when positive.")
+
+	receiversFlag = flag.Bool("receivers", false, "Output the number of receivers per message.")
+)
+
+// Redis manages the connection.
+var Redis *redis.Client[string, []byte]
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	// messages from standard input, unless interactive
+	if len(args) == 0 || len(args) == 1 && cli.IsTerminal(os.Stdin) {
+		os.Stderr.WriteString(`NAME
+	repub — send Redis messages
+
+SYNOPSIS
+	repub [ options ] channel [ message ... ]
+
+DESCRIPTION
+	Repub publishes each message operand to the channel, in order of
+	appearance. Messages are read from the standard input, one per
+	line (or record), in the absence of any message operands.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *rateFlag < 0 {
+		fmt.Fprintln(os.Stderr, "repub: negative rate")
+		os.Exit(2)
+	}
+	channel, messages := args[0], args[1:]
+
+	delim := byte('\n')
+	if *nulFlag {
+		delim = 0
+	}
+	stdin := bufio.NewReader(os.Stdin)
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		if len(messages) == 0 {
+			password, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				fmt.Fprintln(os.Stderr, "repub: no password on standard input")
+				os.Exit(2)
+			}
+			config.Password = []byte(password)
+		} else {
+			config.Password, _ = ioutil.ReadAll(stdin)
+		}
+	}
+	Redis = redis.NewClient[string, []byte](config)
+	defer Redis.Close()
+
+	var pub publisher
+	if *rateFlag > 0 {
+		pub.ticker = time.NewTicker(time.Duration(float64(time.Second) / *rateFlag))
+		defer pub.ticker.Stop()
+	}
+	pub.out = bufio.NewWriter(os.Stdout)
+
+	if len(messages) != 0 {
+		for _, m := range messages {
+			pub.publish(channel, []byte(m))
+		}
+	} else {
+		for {
+			m, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				break
+			}
+			pub.publish(channel, []byte(m))
+		}
+	}
+
+	if err := pub.out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "repub: output with", err)
+		os.Exit(255)
+	}
+}
+
+// Publisher sends messages at the configured rate.
+type publisher struct {
+	ticker *time.Ticker // nil for no limit
+	out    *bufio.Writer
+	count  int // number of messages sent
+}
+
+func (pub *publisher) publish(channel string, message []byte) {
+	if pub.ticker != nil && pub.count != 0 {
+		<-pub.ticker.C
+	}
+
+	n, err := Redis.PUBLISH(channel, message)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "repub: PUBLISH with", err)
+		pub.out.Flush()
+		os.Exit(255)
+	}
+	pub.count++
+
+	if *receiversFlag {
+		fmt.Fprintln(pub.out, n)
+		// keep pace with the messages
+		if err := pub.out.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, "repub: output with", err)
+			os.Exit(255)
+		}
+	}
+}
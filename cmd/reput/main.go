@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/pascaldekloe/redis/v2/internal/cli"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input. The password\nis the first line (or record) when pairs come from the\nstandard input too.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to SELECT.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	fileFlag  = flag.Bool("f", false, "Values in operands are file paths, and the content of\neach file is set.")
+	nulFlag   = flag.Bool("0", false, "Records from the standard input are NUL delimited,\ninstead of newline delimited.")
+	batchFlag = flag.Int("batch", 512, "Maximum `number` of pairs per MSET.")
+
+	nxFlag  = flag.Bool("nx", false, "Only set keys which do not exist yet.")
+	xxFlag  = flag.Bool("xx", false, "Only set keys which exist already.")
+	ttlFlag = flag.Duration("ttl", 0, "Expire keys after `duration`, with millisecond precision.")
+)
+
+// Redis manages the connection.
+var Redis *redis.Client[string, []byte]
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	// pairs from standard input, unless interactive
+	if len(args) == 0 && cli.IsTerminal(os.Stdin) || len(args)%2 != 0 {
+		os.Stderr.WriteString(`NAME
+	reput — store Redis content
+
+SYNOPSIS
+	reput [ options ] [ key value ... ]
+
+DESCRIPTION
+	For each pair of operands, reput sets the key to the value on the
+	node. Pairs are read from the standard input in the absence of any
+	operands, with a key record followed by a value record each.
+
+	Keys are set in batches with MSET, unless conditions or expiry
+	apply, in which case each key gets a SET. The exit status is 1
+	when one or more keys were not set due to the nx or xx condition.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *nxFlag && *xxFlag {
+		fmt.Fprintln(os.Stderr, "reput: nx and xx are mutually exclusive")
+		os.Exit(2)
+	}
+	if *ttlFlag < 0 {
+		fmt.Fprintln(os.Stderr, "reput: negative ttl")
+		os.Exit(2)
+	}
+	if *batchFlag < 1 {
+		fmt.Fprintln(os.Stderr, "reput: batch must be positive")
+		os.Exit(2)
+	}
+	if *fileFlag && len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "reput: files require operands")
+		os.Exit(2)
+	}
+
+	delim := byte('\n')
+	if *nulFlag {
+		delim = 0
+	}
+	stdin := bufio.NewReader(os.Stdin)
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		DB:             *dbFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		if len(args) == 0 {
+			password, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				fmt.Fprintln(os.Stderr, "reput: no password on standard input")
+				os.Exit(2)
+			}
+			config.Password = []byte(password)
+		} else {
+			config.Password, _ = ioutil.ReadAll(stdin)
+		}
+	}
+	Redis = redis.NewClient[string, []byte](config)
+	defer Redis.Close()
+
+	var b batch
+	if len(args) != 0 {
+		for i := 0; i < len(args); i += 2 {
+			value := []byte(args[i+1])
+			if *fileFlag {
+				var err error
+				value, err = os.ReadFile(args[i+1])
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "reput:", err)
+					os.Exit(255)
+				}
+			}
+			b.add(args[i], value)
+		}
+	} else {
+		for {
+			key, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				break
+			}
+			value, ok := cli.ReadRecord(stdin, delim)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "reput: key %q without value on standard input\n", key)
+				b.flush()
+				os.Exit(2)
+			}
+			b.add(key, []byte(value))
+		}
+	}
+	b.flush()
+
+	if b.skipped != 0 {
+		os.Exit(1)
+	}
+}
+
+// Batch collects pairs for MSET.
+type batch struct {
+	keys    []string
+	values  [][]byte
+	skipped int // number of keys not set
+}
+
+func (b *batch) add(key string, value []byte) {
+	if *nxFlag || *xxFlag || *ttlFlag != 0 {
+		b.set(key, value)
+		return
+	}
+
+	b.keys = append(b.keys, key)
+	b.values = append(b.values, value)
+	if len(b.keys) >= *batchFlag {
+		b.flush()
+	}
+}
+
+// Flush sets any pending pairs.
+func (b *batch) flush() {
+	if len(b.keys) == 0 {
+		return
+	}
+	if err := Redis.MSET(b.keys, b.values); err != nil {
+		fmt.Fprintln(os.Stderr, "reput: MSET with", err)
+		os.Exit(255)
+	}
+	b.keys = b.keys[:0]
+	b.values = b.values[:0]
+}
+
+// Set executes SET with the options from the command line.
+func (b *batch) set(key string, value []byte) {
+	var o redis.SETOptions
+	switch {
+	case *nxFlag:
+		o.Flags |= redis.NX
+	case *xxFlag:
+		o.Flags |= redis.XX
+	}
+	if *ttlFlag != 0 {
+		o.Flags |= redis.PX
+		// round up to whole milliseconds
+		o.Expire = (*ttlFlag + time.Millisecond - 1).Truncate(time.Millisecond)
+	}
+
+	ok, err := Redis.SETWithOptions(key, value, o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reput: SET %q with %s\n", key, err)
+		os.Exit(255)
+	}
+	if !ok {
+		b.skipped++
+	}
+}
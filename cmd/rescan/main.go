@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+	dbFlag   = flag.Int64("db", 0, "Logical database `number` to SELECT.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	countFlag  = flag.Int64("count", 0, "Hint the `number` of keys per SCAN when positive.")
+	cursorFlag = flag.Uint64("cursor", 0, "Resume iteration from `position`.")
+	limitFlag  = flag.Int("limit", 0, "Stop after `number` keys when positive. The cursor to\nresume with is reported on the standard error.")
+
+	typeFlag = flag.Bool("type", false, "Output the data type of each key in a column.")
+	ttlFlag  = flag.Bool("ttl", false, "Output the time to live of each key in a column, with\n\"-\" for no expiry.")
+	nulFlag  = flag.Bool("0", false, "Output keys NUL terminated, instead of newline terminated.")
+)
+
+// Redis manages the connection.
+var Redis *redis.Client[string, []byte]
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	if len(args) > 1 {
+		os.Stderr.WriteString(`NAME
+	rescan — list Redis keys
+
+SYNOPSIS
+	rescan [ options ] [ pattern ]
+
+DESCRIPTION
+	Rescan prints each key on the node which matches the glob-style
+	pattern, or all keys in the absence of a pattern. Iteration with
+	SCAN keeps the node responsive, as opposed to KEYS. Keys may print
+	more than once. Columns, if any, are tab separated.
+
+	An interrupt stops iteration after the pending SCAN, with the
+	cursor to resume with reported on the standard error, and an exit
+	status of 1.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	var pattern string
+	if len(args) != 0 {
+		pattern = args[0]
+	}
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		DB:             *dbFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		config.Password, _ = ioutil.ReadAll(os.Stdin)
+	}
+	Redis = redis.NewClient[string, []byte](config)
+	defer Redis.Close()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	out := bufio.NewWriter(os.Stdout)
+	var count int
+	cursor := *cursorFlag
+	for {
+		next, keys, err := Redis.SCAN(cursor, pattern, *countFlag)
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "rescan: SCAN %d with %s\n", cursor, err)
+			os.Exit(255)
+		}
+		for _, key := range keys {
+			writeKey(out, key)
+		}
+		count += len(keys)
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+
+		var stop bool
+		select {
+		case <-interrupt:
+			stop = true
+		default:
+			stop = *limitFlag > 0 && count >= *limitFlag
+		}
+		if stop {
+			if err := out.Flush(); err != nil {
+				fmt.Fprintln(os.Stderr, "rescan: output with", err)
+				os.Exit(255)
+			}
+			fmt.Fprintf(os.Stderr, "rescan: resume with -cursor %d\n", cursor)
+			os.Exit(1)
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "rescan: output with", err)
+		os.Exit(255)
+	}
+}
+
+// WriteKey prints a line (or record) with the columns enabled.
+func writeKey(out *bufio.Writer, key string) {
+	out.WriteString(key)
+	if *typeFlag {
+		typ, err := Redis.TYPE(key)
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "rescan: TYPE %q with %s\n", key, err)
+			os.Exit(255)
+		}
+		out.WriteByte('\t')
+		out.WriteString(typ)
+	}
+	if *ttlFlag {
+		ms, err := Redis.PTTL(key)
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "rescan: PTTL %q with %s\n", key, err)
+			os.Exit(255)
+		}
+		out.WriteByte('\t')
+		switch ms {
+		case -1:
+			out.WriteByte('-') // no expiry
+		case -2:
+			out.WriteString("none") // expired meanwhile
+		default:
+			out.WriteString((time.Duration(ms) * time.Millisecond).String())
+		}
+	}
+
+	if *nulFlag {
+		out.WriteByte(0)
+	} else {
+		out.WriteByte('\n')
+	}
+}
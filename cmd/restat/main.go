@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and command\nexecution to `duration` when non-zero.")
+
+	intervalFlag = flag.Duration("interval", time.Second, "Poll INFO every `duration`.")
+	countFlag    = flag.Int("count", 0, "Exit after `number` rows when positive.")
+	headerFlag   = flag.Int("header", 20, "Repeat the column names every `number` rows when positive.")
+)
+
+// Columns has the names, right aligned to the width of each column.
+const columns = "    time    ops/s clients blocked   memory hit%  expired/s evicted/s     keys\n"
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 0 {
+		os.Stderr.WriteString(`NAME
+	restat — monitor a Redis node
+
+SYNOPSIS
+	restat [ options ]
+
+DESCRIPTION
+	Restat polls INFO on the node, and it prints a row of statistics
+	per interval, until interrupted. Rates apply to the interval. The
+	first row has averages since startup of the node instead.
+
+	The columns are the local time of day, the number of commands per
+	second (ops/s), the number of connected clients, the number of
+	clients in a blocking call, the memory in use, the percentage of
+	key lookups found (hit%), the number of keys expired and evicted
+	per second, and the total number of keys in all databases. Hit%
+	shows a dash when no lookups were made.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *intervalFlag <= 0 {
+		fmt.Fprintln(os.Stderr, "restat: interval must be positive")
+		os.Exit(2)
+	}
+
+	config := redis.ClientConfig{
+		Addr:           *addrFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		config.Password, _ = ioutil.ReadAll(os.Stdin)
+	}
+	Redis := redis.NewClient[string, string](config)
+	defer Redis.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	ticker := time.NewTicker(*intervalFlag)
+	defer ticker.Stop()
+
+	var prev *redis.Info
+	var prevTime time.Time
+	for row := 0; *countFlag <= 0 || row < *countFlag; row++ {
+		if row != 0 {
+			<-ticker.C
+		}
+
+		info, err := Redis.INFO("server", "clients", "memory", "stats", "keyspace")
+		if _, ok := err.(redis.ServerError); ok {
+			// Redis versions before 7 don't accept multiple sections.
+			info, err = Redis.INFO()
+		}
+		if err != nil {
+			out.Flush()
+			fmt.Fprintln(os.Stderr, "restat: INFO with", err)
+			os.Exit(255)
+		}
+		now := time.Now()
+
+		// averages since startup on the first row
+		elapsed := float64(info.UptimeSeconds)
+		since := &redis.Info{}
+		if prev != nil {
+			elapsed = now.Sub(prevTime).Seconds()
+			since = prev
+		}
+
+		if *headerFlag > 0 && row%*headerFlag == 0 {
+			out.WriteString(columns)
+		}
+		writeRow(out, now, info, since, elapsed)
+		if err := out.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, "restat: output with", err)
+			os.Exit(255)
+		}
+
+		prev, prevTime = info, now
+	}
+}
+
+// WriteRow prints the state of info, with rates since the state of prev over
+// elapsed seconds.
+func writeRow(out *bufio.Writer, now time.Time, info, prev *redis.Info, elapsed float64) {
+	var keys int64
+	for _, ks := range info.Keyspace {
+		keys += ks.Keys
+	}
+
+	hitRate := "-"
+	hits := info.KeyspaceHits - prev.KeyspaceHits
+	lookups := hits + info.KeyspaceMisses - prev.KeyspaceMisses
+	if lookups > 0 {
+		hitRate = strconv.FormatFloat(float64(100*hits)/float64(lookups), 'f', 0, 64)
+	}
+
+	fmt.Fprintf(out, "%s %8s %7d %7d %8s %4s %10s %9s %8d\n",
+		now.Format("15:04:05"),
+		rate(info.TotalCommandsProcessed-prev.TotalCommandsProcessed, elapsed),
+		info.ConnectedClients,
+		info.BlockedClients,
+		byteSize(info.UsedMemory),
+		hitRate,
+		rate(info.ExpiredKeys-prev.ExpiredKeys, elapsed),
+		rate(info.EvictedKeys-prev.EvictedKeys, elapsed),
+		keys,
+	)
+}
+
+// Rate formats n per second, or a dash on absence of a duration.
+func rate(n int64, seconds float64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(n)/seconds, 'f', 0, 64)
+}
+
+// ByteSize formats n with a binary unit prefix.
+func byteSize(n int64) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	f := float64(n)
+	i := -1
+	for f >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+	return strconv.FormatFloat(f, 'f', 1, 64) + units[i:i+1]
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+var (
+	addrFlag = flag.String("addr", "localhost:6379", "Redis node `address`.")
+	authFlag = flag.Bool("auth", false, "Reads a password from the standard input.")
+
+	timeoutFlag = flag.Duration("timeout", 0, "Limits both connection establishment and subscription\ncommands to `duration` when non-zero.")
+
+	patternFlag = flag.Bool("p", false, "Operands are glob-style patterns for PSUBSCRIBE, instead\nof channels for SUBSCRIBE.")
+	countFlag   = flag.Int("count", 0, "Exit after `number` messages when positive.")
+
+	rawFlag     = flag.Bool("raw", false, "Output messages as is, without the channel, instead of\nquoted strings.")
+	base64Flag  = flag.Bool("base64", false, "Output messages in base64 encoding, instead of quoted\nstrings.")
+	jsonFlag    = flag.Bool("json", false, "Output a JSON object per line, with the channel, the\npattern if any, and the message. Messages are JSON\nstrings, with invalid UTF-8 replaced, unless base64\nis set.")
+	timeFlag    = flag.Bool("time", false, "Output the time of reception with each message, in\nRFC 3339 format.")
+	delimitFlag = flag.String("delimit", "\n", "The output `separator` after each message.")
+)
+
+// Out has messages written in the configured format.
+var out = bufio.NewWriter(os.Stdout)
+
+// Received counts the messages written.
+var received int
+
+// Done signals the count limit.
+var done = make(chan struct{})
+
+func main() {
+	flag.Parse()
+	names := flag.Args()
+	if len(names) == 0 {
+		os.Stderr.WriteString(`NAME
+	resub — print Redis messages
+
+SYNOPSIS
+	resub [ options ] channel ...
+	resub [ options ] -p pattern ...
+
+DESCRIPTION
+	Resub subscribes to each operand, and it prints the messages as
+	they arrive, until interrupted. Text output has the channel and
+	the message quoted, as in news "hello", with the pattern first
+	for pattern subscriptions. Connection loss is reported on the
+	standard error, followed by a reconnect.
+
+	The following options are available:
+
+`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *rawFlag && (*base64Flag || *jsonFlag) {
+		fmt.Fprintln(os.Stderr, "resub: raw is mutually exclusive with base64 and json")
+		os.Exit(2)
+	}
+
+	config := redis.ListenerConfig{
+		Func:           onMessage,
+		PatternFunc:    onPatternMessage,
+		Addr:           *addrFlag,
+		CommandTimeout: *timeoutFlag,
+		DialTimeout:    *timeoutFlag,
+	}
+	if *authFlag {
+		config.Password, _ = ioutil.ReadAll(os.Stdin)
+	}
+	l := redis.NewListener(config)
+
+	var ack <-chan error
+	if *patternFlag {
+		ack = l.PSUBSCRIBEAck(names...)
+	} else {
+		ack = l.SUBSCRIBEAck(names...)
+	}
+	if err := <-ack; err != nil {
+		fmt.Fprintln(os.Stderr, "resub: subscription with", err)
+		l.Close()
+		os.Exit(255)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-interrupt:
+	case <-done:
+	}
+	// Close awaits any pending callback.
+	l.Close()
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "resub: output with", err)
+		os.Exit(255)
+	}
+}
+
+// OnMessage is the Func of the Listener. Callbacks are sequential.
+func onMessage(channel string, message []byte, err error) {
+	if err != nil {
+		if !errors.Is(err, redis.ErrClosed) {
+			fmt.Fprintln(os.Stderr, "resub:", err)
+		}
+		return
+	}
+	write("", channel, message)
+}
+
+// OnPatternMessage is the PatternFunc of the Listener.
+func onPatternMessage(pattern, channel string, message []byte) {
+	write(pattern, channel, message)
+}
+
+// Write prints a message, up to the count limit.
+func write(pattern, channel string, message []byte) {
+	if *countFlag > 0 && received >= *countFlag {
+		return // awaiting close
+	}
+
+	if *jsonFlag {
+		writeJSON(pattern, channel, message)
+	} else {
+		writeText(pattern, channel, message)
+	}
+	out.WriteString(*delimitFlag)
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "resub: output with", err)
+		os.Exit(255)
+	}
+
+	received++
+	if received == *countFlag {
+		close(done)
+	}
+}
+
+func writeText(pattern, channel string, message []byte) {
+	if *timeFlag {
+		out.WriteString(time.Now().Format(time.RFC3339Nano))
+		out.WriteByte(' ')
+	}
+	switch {
+	case *rawFlag:
+		out.Write(message)
+		return
+	case pattern != "":
+		out.WriteString(strconv.QuoteToGraphic(pattern))
+		out.WriteByte(' ')
+	}
+	out.WriteString(strconv.QuoteToGraphic(channel))
+	out.WriteByte(' ')
+	if *base64Flag {
+		out.WriteString(base64.StdEncoding.EncodeToString(message))
+	} else {
+		out.WriteString(strconv.QuoteToGraphic(string(message)))
+	}
+}
+
+func writeJSON(pattern, channel string, message []byte) {
+	out.WriteByte('{')
+	if *timeFlag {
+		out.WriteString(`"time":"`)
+		out.WriteString(time.Now().Format(time.RFC3339Nano))
+		out.WriteString(`",`)
+	}
+	if pattern != "" {
+		out.WriteString(`"pattern":`)
+		writeJSONString(pattern)
+		out.WriteByte(',')
+	}
+	out.WriteString(`"channel":`)
+	writeJSONString(channel)
+	out.WriteString(`,"message":`)
+	if *base64Flag {
+		out.WriteByte('"')
+		out.WriteString(base64.StdEncoding.EncodeToString(message))
+		out.WriteByte('"')
+	} else {
+		writeJSONString(string(message))
+	}
+	out.WriteByte('}')
+}
+
+func writeJSONString(s string) {
+	text, _ := json.Marshal(s)
+	out.Write(text)
+}
@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CodecClient is a Client view with values of type T, as serialized with a
+// marshal and unmarshal function pair, e.g., json.Marshal and json.Unmarshal
+// from encoding/json. Empty values, including those of non-existing Keys,
+// result in the zero value of T. Encoding errors are returned as is, and
+// decoding errors are wrapped with the respective Key.
+//
+// Multiple goroutines may invoke methods on a CodecClient simultaneously.
+type CodecClient[T any, Key, Value String] struct {
+	*Client[Key, Value]
+
+	marshal   func(any) ([]byte, error)
+	unmarshal func([]byte, any) error
+}
+
+// NewCodecClient returns a view of c with values of type T. Close on either
+// the view or c applies to both.
+func NewCodecClient[T any, Key, Value String](c *Client[Key, Value], marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) *CodecClient[T, Key, Value] {
+	return &CodecClient[T, Key, Value]{Client: c, marshal: marshal, unmarshal: unmarshal}
+}
+
+func (c *CodecClient[T, Key, Value]) encode(v T) (Value, error) {
+	bytes, err := c.marshal(v)
+	return Value(bytes), err
+}
+
+func (c *CodecClient[T, Key, Value]) decode(k Key, v Value) (T, error) {
+	var t T
+	if len(v) == 0 {
+		return t, nil
+	}
+	if err := c.unmarshal([]byte(v), &t); err != nil {
+		return t, fmt.Errorf("redis: value of %q: %w", k, err)
+	}
+	return t, nil
+}
+
+// GET executes <https://redis.io/commands/get>.
+// The return is zero if the Key does not exist.
+func (c *CodecClient[T, Key, Value]) GET(k Key) (T, error) {
+	v, err := c.Client.GET(k)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return c.decode(k, v)
+}
+
+// MGET executes <https://redis.io/commands/mget>.
+// The values for non-existing Keys stay zero.
+func (c *CodecClient[T, Key, Value]) MGET(m ...Key) ([]T, error) {
+	values, err := c.Client.MGET(m...)
+	if err != nil {
+		return nil, err
+	}
+	a := make([]T, len(values))
+	for i, v := range values {
+		a[i], err = c.decode(m[i], v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// SET executes <https://redis.io/commands/set>.
+func (c *CodecClient[T, Key, Value]) SET(k Key, v T) error {
+	encoded, err := c.encode(v)
+	if err != nil {
+		return err
+	}
+	return c.Client.SET(k, encoded)
+}
+
+// HGET executes <https://redis.io/commands/hget>.
+// The return is zero if the Key or field does not exist.
+func (c *CodecClient[T, Key, Value]) HGET(k, f Key) (T, error) {
+	v, err := c.Client.HGET(k, f)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return c.decode(k, v)
+}
+
+// HSET executes <https://redis.io/commands/hset>.
+func (c *CodecClient[T, Key, Value]) HSET(k, f Key, v T) (newField bool, err error) {
+	encoded, err := c.encode(v)
+	if err != nil {
+		return false, err
+	}
+	return c.Client.HSET(k, f, encoded)
+}
+
+// Number is a value type for NewNumberClient.
+type Number interface {
+	int64 | float64
+}
+
+// NewNumberClient returns a view of c with numeric values, formatted in
+// decimal notation, as used by INCRBY and INCRBYFLOAT. Close on either the
+// view or c applies to both.
+func NewNumberClient[N Number, Key, Value String](c *Client[Key, Value]) *CodecClient[N, Key, Value] {
+	return NewCodecClient[N](c, marshalNumber, unmarshalNumber)
+}
+
+func marshalNumber(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), nil
+	default:
+		return nil, fmt.Errorf("redis: %T not a number", v)
+	}
+}
+
+func unmarshalNumber(b []byte, p any) error {
+	var err error
+	switch p := p.(type) {
+	case *int64:
+		*p, err = strconv.ParseInt(string(b), 10, 64)
+	case *float64:
+		*p, err = strconv.ParseFloat(string(b), 64)
+	default:
+		return fmt.Errorf("redis: %T not a number pointer", p)
+	}
+	return err
+}
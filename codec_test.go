@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCodecClient(t *testing.T) {
+	t.Parallel()
+
+	type Point struct{ X, Y int }
+	c := NewCodecClient[Point](testClient, json.Marshal, json.Unmarshal)
+
+	key1, key2, absent := randomKey("test"), randomKey("test"), randomKey("test")
+	if err := c.SET(key1, Point{1, 2}); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if err := c.SET(key2, Point{3, 4}); err != nil {
+		t.Fatal("SET error:", err)
+	}
+
+	if got, err := c.GET(key1); err != nil {
+		t.Error("GET error:", err)
+	} else if got != (Point{1, 2}) {
+		t.Errorf("GET got %+v, want {X:1 Y:2}", got)
+	}
+	if got, err := c.MGET(key1, absent, key2); err != nil {
+		t.Error("MGET error:", err)
+	} else if want := []Point{{1, 2}, {}, {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MGET got %+v, want %+v", got, want)
+	}
+
+	if _, err := c.HSET(absent, "f", Point{5, 6}); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if got, err := c.HGET(absent, "f"); err != nil {
+		t.Error("HGET error:", err)
+	} else if got != (Point{5, 6}) {
+		t.Errorf("HGET got %+v, want {X:5 Y:6}", got)
+	}
+
+	// raw access through the embedded Client
+	if err := c.Client.SET(key1, "{"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if _, err := c.GET(key1); err == nil {
+		t.Error("GET of malformed JSON got no error")
+	}
+}
+
+func TestNumberClient(t *testing.T) {
+	t.Parallel()
+
+	ints := NewNumberClient[int64](testClient)
+	floats := NewNumberClient[float64](testClient)
+	key1, key2, absent := randomKey("test"), randomKey("test"), randomKey("test")
+
+	if err := ints.SET(key1, -42); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if n, err := testClient.INCRBY(key1, 2); err != nil {
+		t.Error("INCRBY error:", err)
+	} else if n != -40 {
+		t.Errorf("INCRBY got %d, want -40", n)
+	}
+	if got, err := ints.MGET(key1, absent); err != nil {
+		t.Error("MGET error:", err)
+	} else if want := []int64{-40, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MGET got %d, want %d", got, want)
+	}
+
+	if err := floats.SET(key2, 0.125); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if got, err := floats.GET(key2); err != nil {
+		t.Error("GET error:", err)
+	} else if got != 0.125 {
+		t.Errorf("GET got %g, want 0.125", got)
+	}
+	if _, err := ints.GET(key2); err == nil {
+		t.Error("GET of float as int64 got no error")
+	}
+}
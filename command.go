@@ -2,6 +2,7 @@ package redis
 
 import (
 	"errors"
+	"strconv"
 	"time"
 )
 
@@ -18,6 +19,16 @@ const (
 	PX
 )
 
+// More flags for SETOptions.
+const (
+	// KEEPTTL retains the expire time of the key, if any.
+	KEEPTTL = 128 << iota
+	// EXAT sets an expire time, as a Unix time in seconds.
+	EXAT
+	// PXAT sets an expire time, as a Unix time in milliseconds.
+	PXAT
+)
+
 // EXPIRE flags include NX And XX.
 const (
 	// GT sets expiry only when the new expiry is greater than current one.
@@ -26,17 +37,150 @@ const (
 	LT
 )
 
-// SETOptions are extra arguments for the SET command.
+// SETOptions are extra arguments for the SET command. NewSETOptions composes
+// them from typed arguments instead.
 type SETOptions struct {
-	// Composotion of NX, XX, EX or PX. The combinations
-	// (NX | XX) and (EX | PX) are rejected to prevent
-	// mistakes.
+	// Composotion of NX, XX, EX, PX, EXAT, PXAT or KEEPTTL.
+	// The combination (NX | XX) is rejected, and so is any
+	// combination of EX, PX, EXAT, PXAT and KEEPTTL, to
+	// prevent mistakes.
 	Flags uint
 
 	// The value is truncated to seconds with the EX flag,
 	// or milliseconds with PX. Non-zero values without any
-	// expiry Flags are rejected to prevent mistakes.
+	// such Flags are rejected to prevent mistakes.
 	Expire time.Duration
+
+	// The value is truncated to seconds with the EXAT flag,
+	// or milliseconds with PXAT. Non-zero values without
+	// any such Flags are rejected to prevent mistakes.
+	ExpireAt time.Time
+}
+
+// Exist is a condition on the presence of a Key, as a typed alternative to the
+// NX and XX flags.
+type Exist uint
+
+// Exist Conditions
+const (
+	// IfNotExist is the NX condition.
+	IfNotExist Exist = NX
+	// IfExist is the XX condition.
+	IfExist Exist = XX
+)
+
+// TTL is an expire setting for SET, as a typed alternative to the EX, PX, EXAT,
+// PXAT and KEEPTTL flags. The zero value sets no expire time, which clears any
+// previous one.
+type TTL struct {
+	flag     uint
+	expire   time.Duration
+	expireAt time.Time
+}
+
+// ExpireIn returns the PX setting, with d truncated to milliseconds.
+func ExpireIn(d time.Duration) TTL {
+	return TTL{flag: PX, expire: d}
+}
+
+// ExpireAt returns the PXAT setting, with t truncated to milliseconds.
+func ExpireAt(t time.Time) TTL {
+	return TTL{flag: PXAT, expireAt: t}
+}
+
+// KeepTTL returns the KEEPTTL setting, which retains any expire time present.
+func KeepTTL() TTL {
+	return TTL{flag: KEEPTTL}
+}
+
+// NewSETOptions returns the equivalent of the typed arguments. Condition is
+// either zero, IfNotExist or IfExist. Use the zero TTL for no expire time.
+func NewSETOptions(condition Exist, ttl TTL) SETOptions {
+	return SETOptions{
+		Flags:    uint(condition) | ttl.flag,
+		Expire:   ttl.expire,
+		ExpireAt: ttl.expireAt,
+	}
+}
+
+// SETPrefixes have a request prefix per argument count, starting at 3.
+var setPrefixes = [...]string{
+	"*3\r\n$3\r\nSET\r\n$",
+	"*4\r\n$3\r\nSET\r\n$",
+	"*5\r\n$3\r\nSET\r\n$",
+	"*6\r\n$3\r\nSET\r\n$",
+	"*7\r\n$3\r\nSET\r\n$",
+}
+
+// SETRequest returns the SET command with options, plus GET when get is set.
+func setRequest[Key, Value String](k Key, v Value, o *SETOptions, get bool) (*request, error) {
+	if unknown := o.Flags &^ (NX | XX | EX | PX | EXAT | PXAT | KEEPTTL); unknown != 0 {
+		return nil, errors.New("redis: unknown SET flags")
+	}
+	argCount := 3
+
+	var existArg string
+	switch o.Flags & (NX | XX) {
+	case 0:
+		break
+	case NX:
+		existArg = "$2\r\nNX\r\n"
+		argCount++
+	case XX:
+		existArg = "$2\r\nXX\r\n"
+		argCount++
+	default:
+		return nil, errors.New("redis: combination of NX and XX not allowed")
+	}
+
+	var expireArg string
+	var expire int64
+	switch o.Flags & (EX | PX | EXAT | PXAT | KEEPTTL) {
+	case 0:
+		break
+	case EX:
+		expireArg = "$2\r\nEX\r\n$"
+		expire = int64(o.Expire / time.Second)
+	case PX:
+		expireArg = "$2\r\nPX\r\n$"
+		expire = int64(o.Expire / time.Millisecond)
+	case EXAT:
+		expireArg = "$4\r\nEXAT\r\n$"
+		expire = o.ExpireAt.Unix()
+	case PXAT:
+		expireArg = "$4\r\nPXAT\r\n$"
+		expire = o.ExpireAt.UnixMilli()
+	case KEEPTTL:
+		expireArg = "$7\r\nKEEPTTL\r\n"
+	default:
+		return nil, errors.New("redis: combination of EX, PX, EXAT, PXAT or KEEPTTL not allowed")
+	}
+	if o.Expire != 0 && o.Flags&(EX|PX) == 0 {
+		return nil, errors.New("redis: expire time without EX or PX not allowed")
+	}
+	if !o.ExpireAt.IsZero() && o.Flags&(EXAT|PXAT) == 0 {
+		return nil, errors.New("redis: expire time without EXAT or PXAT not allowed")
+	}
+	switch {
+	case o.Flags&KEEPTTL != 0:
+		argCount++
+	case expireArg != "":
+		argCount += 2
+	}
+	if get {
+		argCount++
+	}
+
+	r := requestWith2Strings(setPrefixes[argCount-3], k, v)
+	r.buf = append(r.buf, existArg...)
+	r.buf = append(r.buf, expireArg...)
+	if expireArg != "" && o.Flags&KEEPTTL == 0 {
+		r.addDecimalToDollar(expire)
+	}
+	if get {
+		r.buf = append(r.buf, "$3\r\nGET\r\n"...)
+	}
+	return r, nil
 }
 
 // MOVE executes <https://redis.io/commands/move>.
@@ -82,6 +226,67 @@ func (c *Client[Key, Value]) EXPIRE(k Key, seconds int64, flags uint) (bool, err
 	return n != 0, err
 }
 
+// PEXPIRE executes <https://redis.io/commands/pexpire>.
+// Flags can be any of NX, XX, GT or LT.
+func (c *Client[Key, Value]) PEXPIRE(k Key, milliseconds int64, flags uint) (bool, error) {
+	if unknown := flags &^ (NX | XX | GT | LT); unknown != 0 {
+		return false, errors.New("redis: unknown PEXPIRE flags")
+	}
+
+	var n int64
+	var err error
+	switch flags {
+	case 0:
+		n, err = c.commandInteger(requestWithStringAndDecimal("*3\r\n$7\r\nPEXPIRE\r\n$", k, milliseconds))
+	case NX:
+		n, err = c.commandInteger(requestWithStringAndDecimalAndString("*4\r\n$7\r\nPEXPIRE\r\n$", k, milliseconds, "NX"))
+	case XX:
+		n, err = c.commandInteger(requestWithStringAndDecimalAndString("*4\r\n$7\r\nPEXPIRE\r\n$", k, milliseconds, "XX"))
+	case GT:
+		n, err = c.commandInteger(requestWithStringAndDecimalAndString("*4\r\n$7\r\nPEXPIRE\r\n$", k, milliseconds, "GT"))
+	case LT:
+		n, err = c.commandInteger(requestWithStringAndDecimalAndString("*4\r\n$7\r\nPEXPIRE\r\n$", k, milliseconds, "LT"))
+	default:
+		return false, errors.New("redis: multiple PEXPIRE flags denied")
+	}
+	return n != 0, err
+}
+
+// EXPIREWithDuration executes <https://redis.io/commands/pexpire> with the
+// duration rounded up to whole milliseconds, such that any positive duration
+// keeps the Key for at least a millisecond. Zero and negative durations delete
+// the Key. Flags can be any of NX, XX, GT or LT.
+func (c *Client[Key, Value]) EXPIREWithDuration(k Key, d time.Duration, flags uint) (bool, error) {
+	ms := int64(d / time.Millisecond)
+	if d%time.Millisecond > 0 {
+		ms++
+	}
+	return c.PEXPIRE(k, ms, flags)
+}
+
+// ExpireCondition limits expire updates, as a typed alternative to the NX, XX,
+// GT and LT flags of EXPIRE.
+type ExpireCondition uint
+
+// Expire Conditions
+const (
+	// ExpireIfNone is the NX condition, for Keys without an expire time.
+	ExpireIfNone ExpireCondition = NX
+	// ExpireIfAny is the XX condition, for Keys with an expire time.
+	ExpireIfAny ExpireCondition = XX
+	// ExpireIfGreater is the GT condition.
+	ExpireIfGreater ExpireCondition = GT
+	// ExpireIfLess is the LT condition.
+	ExpireIfLess ExpireCondition = LT
+)
+
+// EXPIREIf is like EXPIREWithDuration, yet with a typed condition. Condition
+// zero applies regardless. The return is false if the Key does not exist, or
+// if the condition was not met.
+func (c *Client[Key, Value]) EXPIREIf(k Key, d time.Duration, condition ExpireCondition) (bool, error) {
+	return c.EXPIREWithDuration(k, d, uint(condition))
+}
+
 // FLUSHALL executes <https://redis.io/commands/flushall>.
 func (c *Client[Key, Value]) FLUSHALL(async bool) error {
 	var r *request
@@ -96,13 +301,32 @@ func (c *Client[Key, Value]) FLUSHALL(async bool) error {
 // GET executes <https://redis.io/commands/get>.
 // The return is zero if the Key does not exist.
 func (c *Client[Key, Value]) GET(k Key) (Value, error) {
-	return c.commandBulk(requestWithString("*2\r\n$3\r\nGET\r\n$", k))
+	return c.commandBulk(requestWithString("*2\r\n$3\r\nGET\r\n$", k).idempotent())
+}
+
+// GETOk executes <https://redis.io/commands/get>.
+// The return is false if the Key does not exist.
+func (c *Client[Key, Value]) GETOk(k Key) (v Value, ok bool, err error) {
+	return c.commandBulkOk(requestWithString("*2\r\n$3\r\nGET\r\n$", k).idempotent())
+}
+
+// GETInto executes <https://redis.io/commands/get> with the value copied into
+// buf. The return is the number of bytes, which is zero if the Key does not
+// exist. A buf too small gets io.ErrShortBuffer, with the size required.
+func (c *Client[Key, Value]) GETInto(k Key, buf []byte) (n int, err error) {
+	return c.commandBulkInto(requestWithString("*2\r\n$3\r\nGET\r\n$", k).idempotent(), buf)
 }
 
 // MGET executes <https://redis.io/commands/mget>.
 // The Values for non-existing Keys stay zero.
 func (c *Client[Key, Value]) MGET(m ...Key) ([]Value, error) {
-	return c.commandArray(requestWithList("\r\n$4\r\nMGET", m))
+	return c.commandArray(requestWithList("\r\n$4\r\nMGET", m).idempotent())
+}
+
+// MGETOk executes <https://redis.io/commands/mget>. The return has the
+// presence of each Key, in order of appearance, next to the Values.
+func (c *Client[Key, Value]) MGETOk(m ...Key) (values []Value, ok []bool, err error) {
+	return c.commandArrayOk(requestWithList("\r\n$4\r\nMGET", m).idempotent())
 }
 
 // SET executes <https://redis.io/commands/set>.
@@ -114,59 +338,29 @@ func (c *Client[Key, Value]) SET(k Key, v Value) error {
 // The return is false if the SET operation was not performed due to an NX or XX
 // condition.
 func (c *Client[Key, Value]) SETWithOptions(k Key, v Value, o SETOptions) (bool, error) {
-	if unknown := o.Flags &^ (NX | XX | EX | PX); unknown != 0 {
-		return false, errors.New("redis: unknown SET flags")
-	}
-
-	var existArg string
-	switch o.Flags & (NX | XX) {
-	case 0:
-		break
-	case NX:
-		existArg = "NX"
-	case XX:
-		existArg = "XX"
-	default:
-		return false, errors.New("redis: combination of NX and XX not allowed")
-	}
-
-	var expireArg string
-	var expire int64
-	switch o.Flags & (EX | PX) {
-	case 0:
-		if o.Expire != 0 {
-			return false, errors.New("redis: expire time without EX or PX not allowed")
-		}
-	case EX:
-		expireArg = "EX"
-		expire = int64(o.Expire / time.Second)
-	case PX:
-		expireArg = "PX"
-		expire = int64(o.Expire / time.Millisecond)
-	default:
-		return false, errors.New("redis: combination of EX and PX not allowed")
-	}
-
-	var r *request
-	switch {
-	case existArg != "" && expireArg == "":
-		r = requestWith3Strings("*4\r\n$3\r\nSET\r\n$", k, v, existArg)
-	case existArg == "" && expireArg != "":
-		r = requestWith3StringsAndDecimal("*5\r\n$3\r\nSET\r\n$", k, v, expireArg, expire)
-	case existArg != "" && expireArg != "":
-		r = requestWith4StringsAndDecimal("*6\r\n$3\r\nSET\r\n$", k, v, existArg, expireArg, expire)
-	default:
-		err := c.SET(k, v)
-		return err == nil, err
+	r, err := setRequest(k, v, &o, false)
+	if err != nil {
+		return false, err
 	}
-
-	err := c.commandOK(r)
+	err = c.commandOK(r)
 	if err == errNull {
 		return false, nil
 	}
 	return err == nil, err
 }
 
+// SETGET executes <https://redis.io/commands/set> with options and the GET
+// flag. The return is the previous value, with false when the Key did not
+// exist. Whether the SET operation was performed is not reported. Requires
+// Redis version 6.2 or later, and the NX flag requires version 7.0 or later.
+func (c *Client[Key, Value]) SETGET(k Key, v Value, o SETOptions) (previous Value, ok bool, err error) {
+	r, err := setRequest(k, v, &o, true)
+	if err != nil {
+		return previous, false, err
+	}
+	return c.commandBulkOk(r)
+}
+
 // MSET executes <https://redis.io/commands/mset>.
 func (c *Client[Key, Value]) MSET(mk []Key, mv []Value) error {
 	r, err := requestWithMap("\r\n$4\r\nMSET", mk, mv)
@@ -187,6 +381,88 @@ func (c *Client[Key, Value]) DELArgs(m ...Key) (int64, error) {
 	return c.commandInteger(requestWithList("\r\n$3\r\nDEL", m))
 }
 
+// UNLINK executes <https://redis.io/commands/unlink>. Memory reclaims in the
+// background, as opposed to DEL. Redis version 4 or later is required.
+func (c *Client[Key, Value]) UNLINK(k Key) (bool, error) {
+	removed, err := c.commandInteger(requestWithString("*2\r\n$6\r\nUNLINK\r\n$", k))
+	return removed != 0, err
+}
+
+// UNLINKArgs executes <https://redis.io/commands/unlink>.
+func (c *Client[Key, Value]) UNLINKArgs(m ...Key) (int64, error) {
+	return c.commandInteger(requestWithList("\r\n$6\r\nUNLINK", m))
+}
+
+// TYPE executes <https://redis.io/commands/type>. The return is "none" if the
+// Key does not exist. Other types include "string", "list", "set", "zset",
+// "hash" and "stream".
+func (c *Client[Key, Value]) TYPE(k Key) (string, error) {
+	return c.commandString(requestWithString("*2\r\n$4\r\nTYPE\r\n$", k).idempotent())
+}
+
+// PTTL executes <https://redis.io/commands/pttl>. The return is -2 if the Key
+// does not exist, or -1 if the Key has no expiry.
+func (c *Client[Key, Value]) PTTL(k Key) (milliseconds int64, err error) {
+	return c.commandInteger(requestWithString("*2\r\n$4\r\nPTTL\r\n$", k).idempotent())
+}
+
+// DUMP executes <https://redis.io/commands/dump>. The serialization is in an
+// opaque format of the node, for use with RESTORE. The return is empty if the
+// Key does not exist.
+func (c *Client[Key, Value]) DUMP(k Key) (serialized Value, err error) {
+	return c.commandBulk(requestWithString("*2\r\n$4\r\nDUMP\r\n$", k).idempotent())
+}
+
+// RESTORE executes <https://redis.io/commands/restore>. Zero milliseconds
+// means no expiry. An existing Key gets an ErrBusyKey, unless replace is set.
+func (c *Client[Key, Value]) RESTORE(k Key, milliseconds int64, serialized Value, replace bool) error {
+	if !replace {
+		return c.commandOK(requestWithStringAndDecimalAndString("*4\r\n$7\r\nRESTORE\r\n$", k, milliseconds, serialized))
+	}
+	r := requestWithStringAndDecimalAndString("*5\r\n$7\r\nRESTORE\r\n$", k, milliseconds, serialized)
+	r.buf = append(r.buf, "$7\r\nREPLACE\r\n"...)
+	return c.commandOK(r)
+}
+
+// SCAN executes <https://redis.io/commands/scan>. Iteration starts with cursor
+// zero, and it completes when the next cursor is zero. Keys may be returned
+// more than once. The match pattern applies when not empty, and so does count
+// when positive. Views from WithPrefix match within their namespace only, with
+// the prefix removed from the Keys returned.
+func (c *Client[Key, Value]) SCAN(cursor uint64, match Key, count int64) (next uint64, keys []Key, err error) {
+	withMatch := len(match) != 0 || c.keyPrefix != ""
+	var prefix string
+	switch {
+	case !withMatch && count <= 0:
+		prefix = "*2\r\n$4\r\nSCAN\r\n$"
+	case !withMatch || count <= 0:
+		prefix = "*4\r\n$4\r\nSCAN\r\n$"
+	default:
+		prefix = "*6\r\n$4\r\nSCAN\r\n$"
+	}
+	r := requestWithString(prefix, strconv.FormatUint(cursor, 10))
+	if withMatch {
+		r.buf = append(r.buf, "$5\r\nMATCH\r\n$"...)
+		if c.keyPrefix == "" {
+			r.buf = appendStringToDollar(r.buf, match)
+		} else {
+			pattern := appendGlobEscape(make([]byte, 0, len(c.keyPrefix)+len(match)+1), c.keyPrefix)
+			if len(match) == 0 {
+				pattern = append(pattern, '*')
+			} else {
+				pattern = append(pattern, match...)
+			}
+			r.buf = appendStringToDollar(r.buf, pattern)
+			r.prefixed = true
+		}
+	}
+	if count > 0 {
+		r.buf = append(r.buf, "$5\r\nCOUNT\r\n$"...)
+		r.addDecimalToDollar(count)
+	}
+	return c.commandScan(r.idempotent())
+}
+
 // INCR executes <https://redis.io/commands/incr>.
 func (c *Client[Key, Value]) INCR(k Key) (newValue int64, err error) {
 	return c.commandInteger(requestWithString("*2\r\n$4\r\nINCR\r\n$", k))
@@ -199,13 +475,13 @@ func (c *Client[Key, Value]) INCRBY(k Key, increment int64) (newValue int64, err
 
 // STRLEN executes <https://redis.io/commands/strlen>.
 func (c *Client[Key, Value]) STRLEN(k Key) (int64, error) {
-	return c.commandInteger(requestWithString("*2\r\n$6\r\nSTRLEN\r\n$", k))
+	return c.commandInteger(requestWithString("*2\r\n$6\r\nSTRLEN\r\n$", k).idempotent())
 }
 
 // GETRANGE executes <https://redis.io/commands/getrange>.
 // The return is empty if the Key does not exist.
 func (c *Client[Key, Value]) GETRANGE(k Key, start, end int64) (Value, error) {
-	return c.commandBulk(requestWithStringAnd2Decimals("*4\r\n$8\r\nGETRANGE\r\n$", k, start, end))
+	return c.commandBulk(requestWithStringAnd2Decimals("*4\r\n$8\r\nGETRANGE\r\n$", k, start, end).idempotent())
 }
 
 // APPEND executes <https://redis.io/commands/append>.
@@ -216,20 +492,27 @@ func (c *Client[Key, Value]) APPEND(k Key, v Value) (newLen int64, err error) {
 // LLEN executes <https://redis.io/commands/llen>.
 // The return is 0 if the Key does not exist.
 func (c *Client[Key, Value]) LLEN(k Key) (int64, error) {
-	return c.commandInteger(requestWithString("*2\r\n$4\r\nLLEN\r\n$", k))
+	return c.commandInteger(requestWithString("*2\r\n$4\r\nLLEN\r\n$", k).idempotent())
 }
 
 // LINDEX executes <https://redis.io/commands/lindex>.
 // The return is zero if the Key does not exist.
 // The return is zero if index is out of range.
 func (c *Client[Key, Value]) LINDEX(k Key, index int64) (Value, error) {
-	return c.commandBulk(requestWithStringAndDecimal("*3\r\n$6\r\nLINDEX\r\n$", k, index))
+	return c.commandBulk(requestWithStringAndDecimal("*3\r\n$6\r\nLINDEX\r\n$", k, index).idempotent())
+}
+
+// LINDEXOk executes <https://redis.io/commands/lindex>.
+// The return is false if the Key does not exist.
+// The return is false if index is out of range.
+func (c *Client[Key, Value]) LINDEXOk(k Key, index int64) (v Value, ok bool, err error) {
+	return c.commandBulkOk(requestWithStringAndDecimal("*3\r\n$6\r\nLINDEX\r\n$", k, index).idempotent())
 }
 
 // LRANGE executes <https://redis.io/commands/lrange>.
 // The return is empty if the Key does not exist.
 func (c *Client[Key, Value]) LRANGE(k Key, start, stop int64) ([]Value, error) {
-	return c.commandArray(requestWithStringAnd2Decimals("*4\r\n$6\r\nLRANGE\r\n$", k, start, stop))
+	return c.commandArray(requestWithStringAnd2Decimals("*4\r\n$6\r\nLRANGE\r\n$", k, start, stop).idempotent())
 }
 
 // LPOP executes <https://redis.io/commands/lpop>.
@@ -238,12 +521,59 @@ func (c *Client[Key, Value]) LPOP(k Key) (Value, error) {
 	return c.commandBulk(requestWithString("*2\r\n$4\r\nLPOP\r\n$", k))
 }
 
+// LPOPOk executes <https://redis.io/commands/lpop>.
+// The return is false if the Key does not exist.
+func (c *Client[Key, Value]) LPOPOk(k Key) (v Value, ok bool, err error) {
+	return c.commandBulkOk(requestWithString("*2\r\n$4\r\nLPOP\r\n$", k))
+}
+
+// LPOPInto executes <https://redis.io/commands/lpop> with the element copied
+// into buf. The return is the number of bytes, which is zero if the Key does
+// not exist. A buf too small gets io.ErrShortBuffer, with the size required,
+// and the element is lost.
+func (c *Client[Key, Value]) LPOPInto(k Key, buf []byte) (n int, err error) {
+	return c.commandBulkInto(requestWithString("*2\r\n$4\r\nLPOP\r\n$", k), buf)
+}
+
 // RPOP executes <https://redis.io/commands/rpop>.
 // The return is zero if the Key does not exist.
 func (c *Client[Key, Value]) RPOP(k Key) (Value, error) {
 	return c.commandBulk(requestWithString("*2\r\n$4\r\nRPOP\r\n$", k))
 }
 
+// RPOPOk executes <https://redis.io/commands/rpop>.
+// The return is false if the Key does not exist.
+func (c *Client[Key, Value]) RPOPOk(k Key) (v Value, ok bool, err error) {
+	return c.commandBulkOk(requestWithString("*2\r\n$4\r\nRPOP\r\n$", k))
+}
+
+// BLPOP executes <https://redis.io/commands/blpop> on a dedicated connection.
+// The return is the Key popped from plus its element, or zero for both on
+// timeout. A zero timeout blocks indefinitely. Timeouts other than whole
+// seconds require Redis version 6 or later.
+func (c *Client[Key, Value]) BLPOP(timeout time.Duration, keys ...Key) (Key, Value, error) {
+	return c.blockingPop("\r\n$5\r\nBLPOP", timeout, keys)
+}
+
+// BRPOP executes <https://redis.io/commands/brpop> on a dedicated connection.
+// The return is the Key popped from plus its element, or zero for both on
+// timeout. A zero timeout blocks indefinitely. Timeouts other than whole
+// seconds require Redis version 6 or later.
+func (c *Client[Key, Value]) BRPOP(timeout time.Duration, keys ...Key) (Key, Value, error) {
+	return c.blockingPop("\r\n$5\r\nBRPOP", timeout, keys)
+}
+
+func (c *Client[Key, Value]) blockingPop(prefix string, timeout time.Duration, keys []Key) (k Key, v Value, _ error) {
+	r := requestSize(prefix, len(keys)+2)
+	r.buf = appendCRLFAndList(r.buf, keys)
+	r.buf = appendTimeout(r.buf, timeout)
+	popKeys, popValues, err := c.commandBlockingMap(r.blocking(timeout))
+	if err != nil || len(popKeys) == 0 {
+		return k, v, err
+	}
+	return c.trimKeyPrefix(popKeys[0]), popValues[0], nil
+}
+
 // LTRIM executes <https://redis.io/commands/ltrim>.
 func (c *Client[Key, Value]) LTRIM(k Key, start, stop int64) error {
 	return c.commandOK(requestWithStringAnd2Decimals("*4\r\n$5\r\nLTRIM\r\n$", k, start, stop))
@@ -266,7 +596,7 @@ func (c *Client[Key, Value]) RPUSH(k Key, v Value) (newLen int64, err error) {
 
 // SCARD executes <https://redis.io/commands/scard>.
 func (c *Client[Key, Value]) SCARD(k Key) (int64, error) {
-	return c.commandInteger(requestWithString("*2\r\n$5\r\nSCARD\r\n$", k))
+	return c.commandInteger(requestWithString("*2\r\n$5\r\nSCARD\r\n$", k).idempotent())
 }
 
 // SADD executes <https://redis.io/commands/sadd>.
@@ -293,23 +623,37 @@ func (c *Client[Key, Value]) SREMArgs(k Key, m ...Key) (int64, error) {
 
 // SMEMBERS executes <https://redis.io/commands/smembers>.
 func (c *Client[Key, Value]) SMEMBERS(k Key) ([]Value, error) {
-	return c.commandArray(requestWithString("*2\r\n$8\r\nSMEMBERS\r\n$", k))
+	return c.commandArray(requestWithString("*2\r\n$8\r\nSMEMBERS\r\n$", k).idempotent())
 }
 
 // SINTER executes <https://redis.io/commands/sinter>.
 func (c *Client[Key, Value]) SINTER(k ...Key) ([]Value, error) {
-	return c.commandArray(requestWithList("\r\n$6\r\nSINTER", k))
+	return c.commandArray(requestWithList("\r\n$6\r\nSINTER", k).idempotent())
 }
 
 // SUNION executes <https://redis.io/commands/sunion>.
 func (c *Client[Key, Value]) SUNION(k ...Key) ([]Value, error) {
-	return c.commandArray(requestWithList("\r\n$6\r\nSUNION", k))
+	return c.commandArray(requestWithList("\r\n$6\r\nSUNION", k).idempotent())
 }
 
 // HGET executes <https://redis.io/commands/hget>.
 // The return is zero if the Key does not exist.
 func (c *Client[Key, Value]) HGET(k, f Key) (Value, error) {
-	return c.commandBulk(requestWith2Strings("*3\r\n$4\r\nHGET\r\n$", k, f))
+	return c.commandBulk(requestWith2Strings("*3\r\n$4\r\nHGET\r\n$", k, f).idempotent())
+}
+
+// HGETOk executes <https://redis.io/commands/hget>.
+// The return is false if the Key or the field does not exist.
+func (c *Client[Key, Value]) HGETOk(k, f Key) (v Value, ok bool, err error) {
+	return c.commandBulkOk(requestWith2Strings("*3\r\n$4\r\nHGET\r\n$", k, f).idempotent())
+}
+
+// HGETInto executes <https://redis.io/commands/hget> with the value copied
+// into buf. The return is the number of bytes, which is zero if the Key or the
+// field does not exist. A buf too small gets io.ErrShortBuffer, with the size
+// required.
+func (c *Client[Key, Value]) HGETInto(k, f Key, buf []byte) (n int, err error) {
+	return c.commandBulkInto(requestWith2Strings("*3\r\n$4\r\nHGET\r\n$", k, f).idempotent(), buf)
 }
 
 // HSET executes <https://redis.io/commands/hset>.
@@ -332,7 +676,19 @@ func (c *Client[Key, Value]) HDELArgs(k Key, mf ...Key) (int64, error) {
 // HMGET executes <https://redis.io/commands/hmget>.
 // The Values for non-existing Keys stay zero.
 func (c *Client[Key, Value]) HMGET(k Key, mf ...Key) ([]Value, error) {
-	return c.commandArray(requestWithStringAndList("\r\n$5\r\nHMGET\r\n$", k, mf))
+	return c.commandArray(requestWithStringAndList("\r\n$5\r\nHMGET\r\n$", k, mf).idempotent())
+}
+
+// HMGETOk executes <https://redis.io/commands/hmget>. The return has the
+// presence of each field, in order of appearance, next to the Values.
+func (c *Client[Key, Value]) HMGETOk(k Key, mf ...Key) (values []Value, ok []bool, err error) {
+	return c.commandArrayOk(requestWithStringAndList("\r\n$5\r\nHMGET\r\n$", k, mf).idempotent())
+}
+
+// HGETALL executes <https://redis.io/commands/hgetall>.
+// The return is empty if the Key does not exist.
+func (c *Client[Key, Value]) HGETALL(k Key) (fields []Key, values []Value, err error) {
+	return c.commandMap(requestWithString("*2\r\n$7\r\nHGETALL\r\n$", k).idempotent())
 }
 
 // HMSET executes <https://redis.io/commands/hmset>.
@@ -343,3 +699,24 @@ func (c *Client[Key, Value]) HMSET(k Key, mf []Key, mv []Value) error {
 	}
 	return c.commandOK(r)
 }
+
+// ZADD executes <https://redis.io/commands/zadd>.
+// The return is false if the member was present already.
+func (c *Client[Key, Value]) ZADD(k Key, score float64, m Value) (bool, error) {
+	n, err := c.commandInteger(requestWith3Strings("*4\r\n$4\r\nZADD\r\n$", k, strconv.FormatFloat(score, 'g', -1, 64), m))
+	return n != 0, err
+}
+
+// ZRANGE executes <https://redis.io/commands/zrange>.
+// The return is empty if the Key does not exist.
+func (c *Client[Key, Value]) ZRANGE(k Key, start, stop int64) ([]Value, error) {
+	return c.commandArray(requestWithStringAnd2Decimals("*4\r\n$6\r\nZRANGE\r\n$", k, start, stop).idempotent())
+}
+
+// ZRANGEWithScores executes <https://redis.io/commands/zrange> with the
+// WITHSCORES option. The return is empty if the Key does not exist.
+func (c *Client[Key, Value]) ZRANGEWithScores(k Key, start, stop int64) (members []Value, scores []float64, err error) {
+	r := requestWithStringAnd2Decimals("*5\r\n$6\r\nZRANGE\r\n$", k, start, stop)
+	r.buf = append(r.buf, "$10\r\nWITHSCORES\r\n"...)
+	return c.commandScored(r.idempotent())
+}
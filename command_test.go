@@ -1,7 +1,9 @@
 package redis
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"testing"
@@ -42,6 +44,51 @@ func TestKeyCRUD(t *testing.T) {
 	}
 }
 
+func TestGETInto(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+	const value = "some-value"
+
+	buf := make([]byte, 32)
+	if n, err := testClient.GETInto(key, buf); err != nil {
+		t.Errorf("GET %q absent error: %s", key, err)
+	} else if n != 0 {
+		t.Errorf("GET %q absent got %d bytes", key, n)
+	}
+
+	if err := testClient.SET(key, value); err != nil {
+		t.Fatalf("SET %q %q error: %s", key, value, err)
+	}
+	if n, err := testClient.GETInto(key, buf); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if got := string(buf[:n]); got != value {
+		t.Errorf("GET %q got %q, want %q", key, got, value)
+	}
+	if n, err := testClient.GETInto(key, buf[:3]); err != io.ErrShortBuffer {
+		t.Errorf("GET %q into 3 bytes got error %v, want %v", key, err, io.ErrShortBuffer)
+	} else if n != len(value) {
+		t.Errorf("GET %q into 3 bytes got size %d, want %d", key, n, len(value))
+	}
+
+	// pipeline must continue after short buffer
+	if _, err := testClient.HSET(key+"-hash", "f", value); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if n, err := testClient.HGETInto(key+"-hash", "f", buf); err != nil {
+		t.Errorf("HGET %q error: %s", key+"-hash", err)
+	} else if got := string(buf[:n]); got != value {
+		t.Errorf("HGET %q got %q, want %q", key+"-hash", got, value)
+	}
+	if _, err := testClient.RPUSH(key+"-list", value); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if n, err := testClient.LPOPInto(key+"-list", buf); err != nil {
+		t.Errorf("LPOP %q error: %s", key+"-list", err)
+	} else if got := string(buf[:n]); got != value {
+		t.Errorf("LPOP %q got %q, want %q", key+"-list", got, value)
+	}
+}
+
 func TestBatchKeyCRUD(t *testing.T) {
 	t.Parallel()
 	key1, key2 := randomKey("test-key"), randomKey("test-key")
@@ -104,6 +151,101 @@ func TestKeyAbsent(t *testing.T) {
 	} else if n != 0 {
 		t.Errorf("DEL %q %q got %d, want 0", key, key2, n)
 	}
+
+	ok, err = testClient.UNLINK(key)
+	if err != nil {
+		t.Errorf("UNLINK %q error: %s", key, err)
+	} else if ok {
+		t.Errorf("UNLINK %q got true, want false", key)
+	}
+
+	n, err = testClient.UNLINKArgs(key, key2)
+	if err != nil {
+		t.Errorf("UNLINK %q %q error: %s", key, key2, err)
+	} else if n != 0 {
+		t.Errorf("UNLINK %q %q got %d, want 0", key, key2, n)
+	}
+}
+
+func TestOkVariants(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+	list := randomKey("test-list")
+	hash := randomKey("test-hash")
+
+	// empty string versus absence
+	if err := testClient.SET(key, ""); err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+	if v, ok, err := testClient.GETOk(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if !ok || v != "" {
+		t.Errorf("GET %q got %q, %t, want empty string, true", key, v, ok)
+	}
+	if v, ok, err := testClient.GETOk(key + "-absent"); err != nil {
+		t.Errorf("GET absent error: %s", err)
+	} else if ok || v != "" {
+		t.Errorf("GET absent got %q, %t, want empty string, false", v, ok)
+	}
+
+	if _, err := testClient.HSET(hash, "f", ""); err != nil {
+		t.Fatalf("HSET %q error: %s", hash, err)
+	}
+	if v, ok, err := testClient.HGETOk(hash, "f"); err != nil {
+		t.Errorf("HGET %q error: %s", hash, err)
+	} else if !ok || v != "" {
+		t.Errorf("HGET %q got %q, %t, want empty string, true", hash, v, ok)
+	}
+	if _, ok, err := testClient.HGETOk(hash, "absent"); err != nil {
+		t.Errorf("HGET absent field error: %s", err)
+	} else if ok {
+		t.Error("HGET absent field got true")
+	}
+
+	if _, err := testClient.RPUSH(list, ""); err != nil {
+		t.Fatalf("RPUSH %q error: %s", list, err)
+	}
+	if _, err := testClient.RPUSH(list, "x"); err != nil {
+		t.Fatalf("RPUSH %q error: %s", list, err)
+	}
+	if v, ok, err := testClient.LINDEXOk(list, 0); err != nil {
+		t.Errorf("LINDEX %q error: %s", list, err)
+	} else if !ok || v != "" {
+		t.Errorf("LINDEX %q 0 got %q, %t, want empty string, true", list, v, ok)
+	}
+	if _, ok, err := testClient.LINDEXOk(list, 2); err != nil {
+		t.Errorf("LINDEX %q out of range error: %s", list, err)
+	} else if ok {
+		t.Errorf("LINDEX %q out of range got true", list)
+	}
+	if v, ok, err := testClient.RPOPOk(list); err != nil {
+		t.Errorf("RPOP %q error: %s", list, err)
+	} else if !ok || v != "x" {
+		t.Errorf("RPOP %q got %q, %t, want %q, true", list, v, ok, "x")
+	}
+	if v, ok, err := testClient.LPOPOk(list); err != nil {
+		t.Errorf("LPOP %q error: %s", list, err)
+	} else if !ok || v != "" {
+		t.Errorf("LPOP %q got %q, %t, want empty string, true", list, v, ok)
+	}
+	if _, ok, err := testClient.LPOPOk(list); err != nil {
+		t.Errorf("LPOP %q on empty error: %s", list, err)
+	} else if ok {
+		t.Errorf("LPOP %q on empty got true", list)
+	}
+
+	values, present, err := testClient.MGETOk(key, key+"-absent")
+	if err != nil {
+		t.Errorf("MGET error: %s", err)
+	} else if want := []bool{true, false}; !reflect.DeepEqual(present, want) || len(values) != 2 {
+		t.Errorf("MGET got %q, %t, want 2 empty strings, %t", values, present, want)
+	}
+	values, present, err = testClient.HMGETOk(hash, "absent", "f")
+	if err != nil {
+		t.Errorf("HMGET error: %s", err)
+	} else if want := []bool{false, true}; !reflect.DeepEqual(present, want) || len(values) != 2 {
+		t.Errorf("HMGET got %q, %t, want 2 empty strings, %t", values, present, want)
+	}
 }
 
 func TestKeyModification(t *testing.T) {
@@ -174,6 +316,82 @@ func TestKeyOptions(t *testing.T) {
 	}
 }
 
+func TestSETRequest(t *testing.T) {
+	golden := []struct {
+		o    SETOptions
+		get  bool
+		want string
+	}{
+		{SETOptions{}, false, "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n"},
+		{SETOptions{}, true, "*4\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$3\r\nGET\r\n"},
+		{SETOptions{Flags: XX | KEEPTTL}, false, "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$2\r\nXX\r\n$7\r\nKEEPTTL\r\n"},
+		{SETOptions{Flags: NX | EX, Expire: 1500 * time.Millisecond}, true, "*7\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$2\r\nNX\r\n$2\r\nEX\r\n$1\r\n1\r\n$3\r\nGET\r\n"},
+		{SETOptions{Flags: EXAT, ExpireAt: time.Unix(1700000000, 999)}, false, "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$4\r\nEXAT\r\n$10\r\n1700000000\r\n"},
+		{SETOptions{Flags: PXAT, ExpireAt: time.UnixMilli(1700000000123)}, false, "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$4\r\nPXAT\r\n$13\r\n1700000000123\r\n"},
+		// typed alternatives
+		{NewSETOptions(0, TTL{}), false, "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n"},
+		{NewSETOptions(IfExist, KeepTTL()), false, "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$2\r\nXX\r\n$7\r\nKEEPTTL\r\n"},
+		{NewSETOptions(IfNotExist, ExpireIn(1500*time.Millisecond)), true, "*7\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$2\r\nNX\r\n$2\r\nPX\r\n$4\r\n1500\r\n$3\r\nGET\r\n"},
+		{NewSETOptions(0, ExpireAt(time.UnixMilli(1700000000123))), false, "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$4\r\nPXAT\r\n$13\r\n1700000000123\r\n"},
+	}
+	for _, gold := range golden {
+		r, err := setRequest("k", "v", &gold.o, gold.get)
+		if err != nil {
+			t.Errorf("%+v got error: %s", gold.o, err)
+			continue
+		}
+		if got := string(r.buf); got != gold.want {
+			t.Errorf("%+v got %q, want %q", gold.o, got, gold.want)
+		}
+		r.free()
+	}
+
+	for _, o := range []SETOptions{
+		{Flags: EX | KEEPTTL},
+		{Flags: EXAT | PXAT},
+		{Flags: KEEPTTL, Expire: time.Second},
+		{Flags: EX, ExpireAt: time.Now()},
+	} {
+		if _, err := setRequest("k", "v", &o, false); err == nil {
+			t.Errorf("%+v got no error", o)
+		}
+	}
+}
+
+func TestSETOptionsExtended(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test")
+
+	if v, ok, err := testClient.SETGET(key, "first", SETOptions{Flags: EX, Expire: time.Hour}); err != nil {
+		t.Fatalf("SET %q GET error: %s", key, err)
+	} else if ok || v != "" {
+		t.Errorf("SET %q GET got %q, %t on absent key, want empty string, false", key, v, ok)
+	}
+	if v, ok, err := testClient.SETGET(key, "second", SETOptions{Flags: KEEPTTL}); err != nil {
+		t.Fatalf("SET %q KEEPTTL GET error: %s", key, err)
+	} else if !ok || v != "first" {
+		t.Errorf("SET %q KEEPTTL GET got %q, %t, want %q, true", key, v, ok, "first")
+	}
+	// expiry retained with KEEPTTL
+	if ok, err := testClient.EXPIRE(key, 60, LT); err != nil {
+		t.Errorf("EXPIRE %q 60 LT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q 60 LT got not OK, want an hour retained", key)
+	}
+
+	at := time.Now().Add(time.Hour)
+	if ok, err := testClient.SETWithOptions(key, "third", SETOptions{Flags: XX | PXAT, ExpireAt: at}); err != nil {
+		t.Errorf("SET %q XX PXAT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("SET %q XX PXAT got false", key)
+	}
+	if ok, err := testClient.EXPIRE(key, 60, LT); err != nil {
+		t.Errorf("EXPIRE %q 60 LT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q 60 LT got not OK, want an hour from PXAT", key)
+	}
+}
+
 func TestStrings(t *testing.T) {
 	t.Parallel()
 	key := randomKey("test")
@@ -442,6 +660,48 @@ func TestListRemove(t *testing.T) {
 	}
 }
 
+func TestListBlocking(t *testing.T) {
+	t.Parallel()
+	key1, key2 := randomKey("array"), randomKey("array")
+
+	// pipeline must not stall on a blocking pop
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		start := time.Now()
+		k, v, err := testClient.BLPOP(200*time.Millisecond, key1, key2)
+		if err != nil {
+			t.Errorf("BLPOP %q %q error: %s", key1, key2, err)
+		} else if k != key2 || v != "a" {
+			t.Errorf(`BLPOP %q %q got %q %q, want %q "a"`, key1, key2, k, v, key2)
+		}
+		if d := time.Since(start); d > 150*time.Millisecond {
+			t.Errorf("BLPOP took %s", d)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if _, err := testClient.RPUSH(key2, "a"); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	<-done
+
+	if _, err := testClient.RPUSH(key2, "b"); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if k, v, err := testClient.BRPOP(time.Second, key1, key2); err != nil {
+		t.Errorf("BRPOP %q %q error: %s", key1, key2, err)
+	} else if k != key2 || v != "b" {
+		t.Errorf(`BRPOP %q %q got %q %q, want %q "b"`, key1, key2, k, v, key2)
+	}
+
+	// timeout
+	if k, v, err := testClient.BLPOP(10*time.Millisecond, key1); err != nil {
+		t.Errorf("BLPOP %q on absent key error: %s", key1, err)
+	} else if k != "" || v != "" {
+		t.Errorf("BLPOP %q on absent key got %q %q, want zero", key1, k, v)
+	}
+}
+
 func TestSetCRUD(t *testing.T) {
 	t.Parallel()
 	key := randomKey("test-set")
@@ -608,6 +868,12 @@ func TestBatchHashCRUD(t *testing.T) {
 		}
 	}
 
+	if fields, values, err := testClient.HGETALL(key); err != nil {
+		t.Errorf("HGETALL %q error: %s", key, err)
+	} else if len(fields) != 1 || fields[0] != field || len(values) != 1 || values[0] != update {
+		t.Errorf("HGETALL %q got fields %q and values %q, want [%q] and [%q]", key, fields, values, field, update)
+	}
+
 	field2 := "doesn't exist"
 	n, err := testClient.HDELArgs(key, field, field2)
 	if err != nil {
@@ -671,6 +937,35 @@ func TestHashAbsent(t *testing.T) {
 	}
 }
 
+func TestEXPIREIf(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+
+	if err := testClient.SET(key, "v"); err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+	if ok, err := testClient.EXPIREIf(key, time.Hour, ExpireIfAny); err != nil {
+		t.Errorf("EXPIRE %q XX error: %s", key, err)
+	} else if ok {
+		t.Errorf("EXPIRE %q XX got OK without expiry", key)
+	}
+	if ok, err := testClient.EXPIREIf(key, time.Hour, ExpireIfNone); err != nil {
+		t.Errorf("EXPIRE %q NX error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q NX got not OK without expiry", key)
+	}
+	if ok, err := testClient.EXPIREIf(key, 2*time.Hour, ExpireIfLess); err != nil {
+		t.Errorf("EXPIRE %q LT error: %s", key, err)
+	} else if ok {
+		t.Errorf("EXPIRE %q LT got OK with lesser expiry", key)
+	}
+	if ok, err := testClient.EXPIREIf(key, 2*time.Hour, ExpireIfGreater); err != nil {
+		t.Errorf("EXPIRE %q GT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q GT got not OK with lesser expiry", key)
+	}
+}
+
 func TestExpiry(t *testing.T) {
 	t.Parallel()
 	key := randomKey("test-key")
@@ -718,3 +1013,223 @@ func TestExpiry(t *testing.T) {
 		t.Errorf("EXPIRE %q 99 GT got not OK on 2 second expiry", key)
 	}
 }
+
+func TestExpiryDuration(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-key")
+
+	ok, err := testClient.PEXPIRE(key, 2000, 0)
+	if err != nil {
+		t.Errorf("PEXPIRE %q 2000 error: %s", key, err)
+	} else if ok {
+		t.Errorf("PEXPIRE %q 2000 got OK on non-existent key", key)
+	}
+	if err := testClient.SET(key, "v"); err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+
+	ok, err = testClient.EXPIREWithDuration(key, 1500*time.Millisecond, 0)
+	if err != nil {
+		t.Errorf("EXPIRE %q 1.5s error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q 1.5s got not OK on existent key", key)
+	}
+	// sub-second precision shows when compared with whole seconds
+	ok, err = testClient.EXPIRE(key, 1, LT)
+	if err != nil {
+		t.Errorf("EXPIRE %q 1 LT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q 1 LT got not OK on 1.5 second expiry", key)
+	}
+
+	ok, err = testClient.PEXPIRE(key, 5000, NX)
+	if err != nil {
+		t.Errorf("PEXPIRE %q 5000 NX error: %s", key, err)
+	} else if ok {
+		t.Errorf("PEXPIRE %q 5000 NX got OK with existing expiry", key)
+	}
+	ok, err = testClient.PEXPIRE(key, 5000, GT)
+	if err != nil {
+		t.Errorf("PEXPIRE %q 5000 GT error: %s", key, err)
+	} else if !ok {
+		t.Errorf("PEXPIRE %q 5000 GT got not OK on 1 second expiry", key)
+	}
+
+	// any positive duration keeps the key
+	ok, err = testClient.EXPIREWithDuration(key, time.Nanosecond, 0)
+	if err != nil {
+		t.Errorf("EXPIRE %q 1ns error: %s", key, err)
+	} else if !ok {
+		t.Errorf("EXPIRE %q 1ns got not OK on existent key", key)
+	}
+	if _, err := testClient.EXPIREWithDuration(key, 0, LT); err != nil {
+		t.Errorf("EXPIRE %q 0 LT error: %s", key, err)
+	}
+	if _, ok, err := testClient.GETOk(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if ok {
+		t.Errorf("GET %q got the value after zero expiry", key)
+	}
+}
+
+func TestSortedSet(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-zset")
+
+	if typ, err := testClient.TYPE(key); err != nil {
+		t.Errorf("TYPE %q error: %s", key, err)
+	} else if typ != "none" {
+		t.Errorf("TYPE %q got %q on absent key, want none", key, typ)
+	}
+
+	for _, m := range []struct {
+		score  float64
+		member string
+	}{{2.5, "b"}, {-1, "a"}, {10, "c"}} {
+		if ok, err := testClient.ZADD(key, m.score, m.member); err != nil {
+			t.Fatalf("ZADD %q %g %q error: %s", key, m.score, m.member, err)
+		} else if !ok {
+			t.Errorf("ZADD %q %g %q got false, want true", key, m.score, m.member)
+		}
+	}
+	if ok, err := testClient.ZADD(key, 3, "b"); err != nil {
+		t.Errorf("ZADD %q update error: %s", key, err)
+	} else if ok {
+		t.Errorf("ZADD %q update got true, want false", key)
+	}
+
+	if typ, err := testClient.TYPE(key); err != nil {
+		t.Errorf("TYPE %q error: %s", key, err)
+	} else if typ != "zset" {
+		t.Errorf("TYPE %q got %q, want zset", key, typ)
+	}
+
+	if members, err := testClient.ZRANGE(key, 0, -1); err != nil {
+		t.Errorf("ZRANGE %q error: %s", key, err)
+	} else if want := []string{"a", "b", "c"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("ZRANGE %q got %q, want %q", key, members, want)
+	}
+	members, scores, err := testClient.ZRANGEWithScores(key, 1, -1)
+	if err != nil {
+		t.Errorf("ZRANGE %q WITHSCORES error: %s", key, err)
+	} else {
+		if want := []string{"b", "c"}; !reflect.DeepEqual(members, want) {
+			t.Errorf("ZRANGE %q WITHSCORES got members %q, want %q", key, members, want)
+		}
+		if want := []float64{3, 10}; !reflect.DeepEqual(scores, want) {
+			t.Errorf("ZRANGE %q WITHSCORES got scores %g, want %g", key, scores, want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+	prefix := randomKey("test-scan") + ":"
+
+	want := []string{prefix + "a", prefix + "b", prefix + "c"}
+	for _, key := range want {
+		if err := testClient.SET(key, "v"); err != nil {
+			t.Fatalf("SET %q error: %s", key, err)
+		}
+	}
+	if ok, err := testClient.PEXPIRE(want[0], 60000, 0); err != nil || !ok {
+		t.Fatalf("PEXPIRE %q got %t, error %v", want[0], ok, err)
+	}
+
+	found := make(map[string]bool)
+	var cursor uint64
+	for {
+		next, keys, err := testClient.SCAN(cursor, prefix+"*", 2)
+		if err != nil {
+			t.Fatalf("SCAN %d MATCH %q COUNT 2 error: %s", cursor, prefix+"*", err)
+		}
+		for _, k := range keys {
+			found[k] = true
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	got := make([]string, 0, len(found))
+	for k := range found {
+		got = append(got, k)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SCAN MATCH %q got %q, want %q", prefix+"*", got, want)
+	}
+
+	if ms, err := testClient.PTTL(want[0]); err != nil {
+		t.Errorf("PTTL %q error: %s", want[0], err)
+	} else if ms <= 0 || ms > 60000 {
+		t.Errorf("PTTL %q got %d, want within (0, 60000]", want[0], ms)
+	}
+	if ms, err := testClient.PTTL(want[1]); err != nil || ms != -1 {
+		t.Errorf("PTTL %q got %d, error %v, want -1 for no expiry", want[1], ms, err)
+	}
+	if ms, err := testClient.PTTL(prefix + "absent"); err != nil || ms != -2 {
+		t.Errorf("PTTL %q got %d, error %v, want -2 for absence", prefix+"absent", ms, err)
+	}
+
+	view := testClient.WithPrefix(prefix)
+	for _, match := range []string{"", "[ab]"} {
+		found := make(map[string]bool)
+		for cursor := uint64(0); ; {
+			next, keys, err := view.SCAN(cursor, match, 0)
+			if err != nil {
+				t.Fatalf("SCAN %d MATCH %q on prefixed view error: %s", cursor, match, err)
+			}
+			for _, k := range keys {
+				found[k] = true
+			}
+			if next == 0 {
+				break
+			}
+			cursor = next
+		}
+		want := map[string]bool{"a": true, "b": true}
+		if match == "" {
+			want["c"] = true
+		}
+		if !reflect.DeepEqual(found, want) {
+			t.Errorf("SCAN MATCH %q on prefixed view got %v, want %v", match, found, want)
+		}
+	}
+}
+
+func TestDumpRestore(t *testing.T) {
+	t.Parallel()
+	key, copyKey := randomKey("test-key"), randomKey("test-key")
+
+	if dump, err := testClient.DUMP(key); err != nil {
+		t.Errorf("DUMP %q error: %s", key, err)
+	} else if dump != "" {
+		t.Errorf("DUMP %q got %q on absent key, want empty", key, dump)
+	}
+
+	if err := testClient.SET(key, "v"); err != nil {
+		t.Fatalf("SET %q error: %s", key, err)
+	}
+	dump, err := testClient.DUMP(key)
+	if err != nil {
+		t.Fatalf("DUMP %q error: %s", key, err)
+	}
+
+	if err := testClient.RESTORE(copyKey, 60000, dump, false); err != nil {
+		t.Fatalf("RESTORE %q error: %s", copyKey, err)
+	}
+	if v, err := testClient.GET(copyKey); err != nil || v != "v" {
+		t.Errorf("GET %q got %q, error %v, want %q", copyKey, v, err, "v")
+	}
+	if ms, err := testClient.PTTL(copyKey); err != nil || ms <= 0 {
+		t.Errorf("PTTL %q got %d, error %v, want positive", copyKey, ms, err)
+	}
+
+	if err := testClient.RESTORE(copyKey, 0, dump, false); !errors.Is(err, ErrBusyKey) {
+		t.Errorf("RESTORE %q on existing key got error %v, want ErrBusyKey", copyKey, err)
+	}
+	if err := testClient.RESTORE(copyKey, 0, dump, true); err != nil {
+		t.Errorf("RESTORE %q REPLACE error: %s", copyKey, err)
+	}
+}
@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"io"
+	"time"
+)
+
+// Commander has the command methods of a Client. Code which depends on
+// Commander instead of Client can run on a test double too. Methods may be
+// added to the interface in minor releases, as new commands are supported.
+type Commander[Key, Value String] interface {
+	APPEND(k Key, v Value) (newLen int64, err error)
+	BFADD(k Key, item Value) (bool, error)
+	BFEXISTS(k Key, item Value) (bool, error)
+	BFMADD(k Key, items ...Value) ([]bool, error)
+	BFMEXISTS(k Key, items ...Value) ([]bool, error)
+	BFRESERVE(k Key, errorRate float64, capacity, expansion int64) error
+	BLPOP(timeout time.Duration, keys ...Key) (Key, Value, error)
+	BRPOP(timeout time.Duration, keys ...Key) (Key, Value, error)
+	CFADD(k Key, item Value) error
+	CFADDNX(k Key, item Value) (bool, error)
+	CFCOUNT(k Key, item Value) (int64, error)
+	CFDEL(k Key, item Value) (bool, error)
+	CFEXISTS(k Key, item Value) (bool, error)
+	CFMEXISTS(k Key, items ...Value) ([]bool, error)
+	CFRESERVE(k Key, capacity int64) error
+	CLIENTPAUSE(timeout time.Duration, writeOnly bool) error
+	CLIENTUNPAUSE() error
+	Close() error
+	CLUSTERCOUNTKEYSINSLOT(slot int64) (int64, error)
+	CLUSTERINFO() (*ClusterInfo, error)
+	CLUSTERMYID() (string, error)
+	CLUSTERNODES() ([]ClusterNode, error)
+	CLUSTERSHARDS() ([]ClusterShard, error)
+	CLUSTERSLOTS() ([]ClusterSlots, error)
+	COMMANDCOUNT() (int64, error)
+	COMMANDGETKEYS(args ...string) ([]Key, error)
+	COMMANDINFO(names ...string) ([]CommandInfo, error)
+	CONFIGGET(pattern string) (map[string]string, error)
+	CONFIGRESETSTAT() error
+	CONFIGREWRITE() error
+	CONFIGSET(names, values []string) error
+	DELArgs(m ...Key) (int64, error)
+	DEL(k Key) (bool, error)
+	DoArray(r *Request) ([]Value, error)
+	DoArrayPooled(r *Request) (*PooledArray, error)
+	DoBulk(r *Request) (Value, error)
+	DoBulkInto(r *Request, buf []byte) (int, error)
+	DoInteger(r *Request) (int64, error)
+	DoMap(r *Request) ([]Key, []Value, error)
+	DoOK(r *Request) error
+	DUMP(k Key) (serialized Value, err error)
+	ECHO(message Value) (Value, error)
+	EXPIRE(k Key, seconds int64, flags uint) (bool, error)
+	EXPIREIf(k Key, d time.Duration, condition ExpireCondition) (bool, error)
+	EXPIREWithDuration(k Key, d time.Duration, flags uint) (bool, error)
+	FLUSHALL(async bool) error
+	FLUSHDB(async bool) error
+	FTAGGREGATE(index, query string, o *AggregateOptions) (*AggregateResult, error)
+	FTCREATE(index string, def SearchIndex) error
+	FTDROPINDEX(index string, deleteDocs bool) error
+	FTSEARCH(index, query string, o *SearchOptions) (*SearchResult, error)
+	GET(k Key) (Value, error)
+	GETInto(k Key, buf []byte) (n int, err error)
+	GETOk(k Key) (v Value, ok bool, err error)
+	GETRANGE(k Key, start, end int64) (Value, error)
+	GETReader(k Key) (*BulkReader, error)
+	GETVisit(k Key, f VisitFunc) (ok bool, err error)
+	HDELArgs(k Key, mf ...Key) (int64, error)
+	HDEL(k, f Key) (bool, error)
+	HGETALL(k Key) (fields []Key, values []Value, err error)
+	HGETALLStruct(k Key, dst any) error
+	HGET(k, f Key) (Value, error)
+	HGETInto(k, f Key, buf []byte) (n int, err error)
+	HGETOk(k, f Key) (v Value, ok bool, err error)
+	HGETVisit(k, field Key, f VisitFunc) (ok bool, err error)
+	HMGET(k Key, mf ...Key) ([]Value, error)
+	HMGETOk(k Key, mf ...Key) (values []Value, ok []bool, err error)
+	HMGETPooled(k Key, mf ...Key) (*PooledArray, error)
+	HMGETStruct(k Key, dst any) error
+	HMSET(k Key, mf []Key, mv []Value) error
+	HSET(k, f Key, v Value) (newField bool, err error)
+	HSETStruct(k Key, v any) (newFields int64, err error)
+	INCRBY(k Key, increment int64) (newValue int64, err error)
+	INCR(k Key) (newValue int64, err error)
+	INFO(sections ...string) (*Info, error)
+	JSONDEL(k Key, path string) (int64, error)
+	JSONGET(k Key, paths ...string) (Value, error)
+	JSONMGET(path string, keys ...Key) ([]Value, error)
+	JSONNUMINCRBY(k Key, path string, increment float64) ([]float64, error)
+	JSONSETIf(k Key, path string, doc Value, condition Exist) (bool, error)
+	JSONSET(k Key, path string, doc Value, flags uint) (bool, error)
+	LINDEX(k Key, index int64) (Value, error)
+	LINDEXOk(k Key, index int64) (v Value, ok bool, err error)
+	LLEN(k Key) (int64, error)
+	LPOPInto(k Key, buf []byte) (n int, err error)
+	LPOP(k Key) (Value, error)
+	LPOPOk(k Key) (v Value, ok bool, err error)
+	LPOPVisit(k Key, f VisitFunc) (ok bool, err error)
+	LPUSH(k Key, v Value) (newLen int64, err error)
+	LRANGEEach(k Key, start, stop int64, f ElementFunc) error
+	LRANGE(k Key, start, stop int64) ([]Value, error)
+	LRANGEPooled(k Key, start, stop int64) (*PooledArray, error)
+	LSET(k Key, index int64, value Value) error
+	LTRIM(k Key, start, stop int64) error
+	MEMORYDOCTOR() (string, error)
+	MEMORYSTATS() (*MemoryStats, error)
+	MEMORYUSAGE(k Key, samples int64) (int64, error)
+	MGETEach(f ElementFunc, m ...Key) error
+	MGET(m ...Key) ([]Value, error)
+	MGETOk(m ...Key) (values []Value, ok []bool, err error)
+	MGETPooled(m ...Key) (*PooledArray, error)
+	MOVE(k Key, db int64) (bool, error)
+	MSET(mk []Key, mv []Value) error
+	NotifyKeyspaceEvents(classes string) error
+	PEXPIRE(k Key, milliseconds int64, flags uint) (bool, error)
+	PING() error
+	PINGWithMessage(message Value) (Value, error)
+	PTTL(k Key) (milliseconds int64, err error)
+	PUBLISH(channel Key, message Value) (clientCount int64, err error)
+	REPLICAOFNOONE() error
+	REPLICAOF(host string, port int64) error
+	RESET() error
+	RESTORE(k Key, milliseconds int64, serialized Value, replace bool) error
+	RPOPOk(k Key) (v Value, ok bool, err error)
+	RPOP(k Key) (Value, error)
+	RPUSH(k Key, v Value) (newLen int64, err error)
+	SADDArgs(k Key, m ...Key) (int64, error)
+	SADD(k, m Key) (bool, error)
+	SCAN(cursor uint64, match Key, count int64) (next uint64, keys []Key, err error)
+	SCARD(k Key) (int64, error)
+	SELECT(db int64) error
+	SETGET(k Key, v Value, o SETOptions) (previous Value, ok bool, err error)
+	SETReader(k Key, r io.Reader, size int64) error
+	SET(k Key, v Value) error
+	SETWithOptions(k Key, v Value, o SETOptions) (bool, error)
+	SINTER(k ...Key) ([]Value, error)
+	SLOWLOGGET(count int64) ([]SlowLogEntry, error)
+	SLOWLOGLEN() (int64, error)
+	SLOWLOGRESET() error
+	SMEMBERS(k Key) ([]Value, error)
+	SREMArgs(k Key, m ...Key) (int64, error)
+	SREM(k, m Key) (bool, error)
+	STRLEN(k Key) (int64, error)
+	SUNION(k ...Key) ([]Value, error)
+	SWAPDB(db1, db2 int64) error
+	TIME() (time.Time, error)
+	TYPE(k Key) (string, error)
+	UNLINKArgs(m ...Key) (int64, error)
+	UNLINKMatch(pattern Key, o UNLINKMatchOptions) (removed int64, err error)
+	UNLINK(k Key) (bool, error)
+	WAIT(numReplicas int64, timeout time.Duration) (int64, error)
+	ZADD(k Key, score float64, m Value) (bool, error)
+	ZRANGEWithScores(k Key, start, stop int64) (members []Value, scores []float64, err error)
+	ZRANGE(k Key, start, stop int64) ([]Value, error)
+}
+
+var _ Commander[string, []byte] = (*Client[string, []byte])(nil)
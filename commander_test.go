@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCommanderComplete verifies that each command method of Client is part of
+// the Commander interface.
+func TestCommanderComplete(t *testing.T) {
+	notCommands := map[string]bool{
+		"Connect":        true,
+		"ForceReconnect": true,
+		"SetAddr":        true,
+		"SetPassword":    true,
+		"Stats":          true,
+		"WithContext":    true,
+		"WithPrefix":     true,
+		"Validate":       true, // from ClientConfig
+	}
+
+	commander := reflect.TypeOf((*Commander[string, string])(nil)).Elem()
+	client := reflect.TypeOf((*Client[string, string])(nil))
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		if _, ok := commander.MethodByName(name); !ok && !notCommands[name] {
+			t.Errorf("Client method %s missing in Commander", name)
+		}
+	}
+}
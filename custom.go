@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"math"
+	"strconv"
+)
+
+// Request is a custom command, for those not covered by the Client methods.
+// Arguments are encoded as they are added. Reset makes a Request reusable,
+// such that construction is free of memory allocation once the buffer has
+// grown to size. Multiple goroutines must not use a Request simultaneously.
+//
+// Views from WithPrefix reject custom commands unless their Key positions are
+// known to this package.
+type Request struct {
+	buf      []byte // arguments in RESP
+	argCount int
+
+	// Idempotent marks commands without side effects, which are submitted
+	// once more on connection loss with ClientConfig RetryIdempotent.
+	Idempotent bool
+}
+
+// NewRequest returns a Request with the command name as its first argument.
+func NewRequest(name string) *Request {
+	r := new(Request)
+	r.AddString(name)
+	return r
+}
+
+// Reset clears both the arguments and the Idempotent mark. The buffer is
+// retained for reuse.
+func (r *Request) Reset() {
+	r.buf = r.buf[:0]
+	r.argCount = 0
+	r.Idempotent = false
+}
+
+// ArgCount returns the number of arguments, including the command name.
+func (r *Request) ArgCount() int { return r.argCount }
+
+// AddString appends an argument.
+func (r *Request) AddString(s string) {
+	r.buf = append(r.buf, '$')
+	r.buf = appendStringToDollar(r.buf, s)
+	r.argCount++
+}
+
+// AddBytes appends an argument.
+func (r *Request) AddBytes(b []byte) {
+	r.buf = append(r.buf, '$')
+	r.buf = appendStringToDollar(r.buf, b)
+	r.argCount++
+}
+
+// AddInt appends an argument in decimal notation.
+func (r *Request) AddInt(n int64) {
+	var digits [20]byte
+	r.AddBytes(strconv.AppendInt(digits[:0], n, 10))
+}
+
+// AddFloat appends an argument in the shortest notation which parses back to
+// the exact same value, with "inf" and "-inf" for infinity.
+func (r *Request) AddFloat(f float64) {
+	switch {
+	case math.IsInf(f, 1):
+		r.AddString("inf")
+	case math.IsInf(f, -1):
+		r.AddString("-inf")
+	default:
+		var text [32]byte
+		r.AddBytes(strconv.AppendFloat(text[:0], f, 'g', -1, 64))
+	}
+}
+
+// Request returns the package-internal equivalent, or an error when empty.
+func (r *Request) request() (*request, error) {
+	if r.argCount == 0 {
+		return nil, errNoCommand
+	}
+	req := requestSize("\r\n", r.argCount)
+	req.buf = append(req.buf, r.buf...)
+	req.retry = r.Idempotent
+	return req, nil
+}
+
+// DoOK executes a custom command with an "OK" reply.
+func (c *Client[Key, Value]) DoOK(r *Request) error {
+	req, err := r.request()
+	if err != nil {
+		return err
+	}
+	return c.commandOK(req)
+}
+
+// DoInteger executes a custom command with an integer reply.
+func (c *Client[Key, Value]) DoInteger(r *Request) (int64, error) {
+	req, err := r.request()
+	if err != nil {
+		return 0, err
+	}
+	return c.commandInteger(req)
+}
+
+// DoBulk executes a custom command with a bulk string reply. Simple strings,
+// integers, doubles and booleans are accepted as such too. The return is zero
+// for null.
+func (c *Client[Key, Value]) DoBulk(r *Request) (Value, error) {
+	req, err := r.request()
+	if err != nil {
+		var zero Value
+		return zero, err
+	}
+	return c.commandBulk(req)
+}
+
+// DoBulkInto is like DoBulk, yet with the reply copied into buf, conform
+// GETInto.
+func (c *Client[Key, Value]) DoBulkInto(r *Request, buf []byte) (int, error) {
+	req, err := r.request()
+	if err != nil {
+		return 0, err
+	}
+	return c.commandBulkInto(req, buf)
+}
+
+// DoArray executes a custom command with an array reply of bulk strings. Null
+// elements are zero.
+func (c *Client[Key, Value]) DoArray(r *Request) ([]Value, error) {
+	req, err := r.request()
+	if err != nil {
+		return nil, err
+	}
+	return c.commandArray(req)
+}
+
+// DoMap executes a custom command with a map reply, or an array reply with
+// key–value pairs.
+func (c *Client[Key, Value]) DoMap(r *Request) ([]Key, []Value, error) {
+	req, err := r.request()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.commandMap(req)
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRequestEncoding(t *testing.T) {
+	r := NewRequest("ZADD")
+	r.AddString("k")
+	r.AddFloat(math.Inf(-1))
+	r.AddBytes([]byte("m"))
+	r.AddInt(-42)
+	r.AddFloat(0.1)
+
+	req, err := r.request()
+	if err != nil {
+		t.Fatal("request error:", err)
+	}
+	defer req.free()
+	const want = "*6\r\n$4\r\nZADD\r\n$1\r\nk\r\n$4\r\n-inf\r\n$1\r\nm\r\n$3\r\n-42\r\n$3\r\n0.1\r\n"
+	if got := string(req.buf); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if r.ArgCount() != 6 {
+		t.Errorf("got argument count %d, want 6", r.ArgCount())
+	}
+
+	r.Reset()
+	if _, err := r.request(); err != errNoCommand {
+		t.Errorf("empty request got error %v, want errNoCommand", err)
+	}
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test-hash")
+
+	var r Request
+	r.AddString("HSET")
+	r.AddString(key)
+	r.AddString("a")
+	r.AddInt(1)
+	r.AddString("b")
+	r.AddFloat(2.5)
+	if n, err := testClient.DoInteger(&r); err != nil {
+		t.Fatalf("HSET %q error: %s", key, err)
+	} else if n != 2 {
+		t.Errorf("HSET %q got %d, want 2 new fields", key, n)
+	}
+
+	r.Reset()
+	r.AddString("HINCRBYFLOAT")
+	r.AddString(key)
+	r.AddString("b")
+	r.AddFloat(0.5)
+	if v, err := testClient.DoBulk(&r); err != nil {
+		t.Errorf("HINCRBYFLOAT %q error: %s", key, err)
+	} else if v != "3" {
+		t.Errorf("HINCRBYFLOAT %q got %q, want 3", key, v)
+	}
+
+	r.Reset()
+	r.AddString("HMGET")
+	r.AddString(key)
+	r.AddString("a")
+	r.AddString("absent")
+	r.Idempotent = true
+	if values, err := testClient.DoArray(&r); err != nil {
+		t.Errorf("HMGET %q error: %s", key, err)
+	} else if want := []string{"1", ""}; !reflect.DeepEqual(values, want) {
+		t.Errorf("HMGET %q got %q, want %q", key, values, want)
+	}
+
+	r.Reset()
+	r.AddString("HGETALL")
+	r.AddString(key)
+	if fields, values, err := testClient.DoMap(&r); err != nil {
+		t.Errorf("HGETALL %q error: %s", key, err)
+	} else if len(fields) != 2 || len(values) != 2 {
+		t.Errorf("HGETALL %q got fields %q with values %q, want 2 each", key, fields, values)
+	}
+
+	r.Reset()
+	if err := testClient.DoOK(&r); err != errNoCommand {
+		t.Errorf("empty request got error %v, want errNoCommand", err)
+	}
+}
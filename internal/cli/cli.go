@@ -0,0 +1,32 @@
+// Package cli has the common functionality of the command-line tools.
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// IsTerminal returns whether f is a character device.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ReadRecord returns the next record up to delim, exclusive. A trailing record
+// without delim counts too. Read errors exit the program with status 255.
+func ReadRecord(r *bufio.Reader, delim byte) (string, bool) {
+	s, err := r.ReadString(delim)
+	switch {
+	case err == nil:
+		return s[:len(s)-1], true
+	case err == io.EOF:
+		return s, s != ""
+	default:
+		fmt.Fprintln(os.Stderr, filepath.Base(os.Args[0])+": standard input with", err)
+		os.Exit(255)
+		return "", false
+	}
+}
@@ -0,0 +1,98 @@
+//go:build go1.23
+
+package redis
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// Message is a Listener reception.
+type Message struct {
+	Channel string
+	Pattern string // PSUBSCRIBE only
+	Payload []byte
+}
+
+// NewListenerSeq launches a Listener like NewListener does, yet with the
+// messages as a sequence, for use in a range statement. The ListenerConfig
+// Func, PatternFunc and OwnedFunc are replaced. Errors come without Payload. The
+// sequence ends once the Listener is closed. Breaking out of the loop drops
+// any messages which follow, and so does Close, including when called from
+// within the loop. The sequence can be consumed only once. Any range after the
+// first one ends immediately.
+func NewListenerSeq(config ListenerConfig) (*Listener, iter.Seq2[Message, error]) {
+	type delivery struct {
+		m   Message
+		err error
+	}
+	deliveries := make(chan delivery)
+	closed := make(chan struct{})  // by Listener
+	stopped := make(chan struct{}) // by range loop
+	var ranged int32               // atomic flag
+
+	// callbacks run after the connection management launch
+	var l *Listener
+	deliver := func(m Message, err error) {
+		select {
+		case deliveries <- delivery{m, err}:
+			break
+		case <-stopped:
+			break // dropped
+		case <-l.closing:
+			break // dropped
+		}
+	}
+	config.Func = func(channel string, message []byte, err error) {
+		if err == ErrClosed {
+			close(closed)
+			return
+		}
+		if err != nil {
+			deliver(Message{Channel: channel}, err)
+			return
+		}
+		deliver(Message{
+			Channel: channel,
+			Payload: append([]byte(nil), message...),
+		}, nil)
+	}
+	config.OwnedFunc = nil
+	config.PatternFunc = func(pattern, channel string, message []byte) {
+		deliver(Message{
+			Channel: channel,
+			Pattern: pattern,
+			Payload: append([]byte(nil), message...),
+		}, nil)
+	}
+
+	seq := func(yield func(Message, error) bool) {
+		if !atomic.CompareAndSwapInt32(&ranged, 0, 1) {
+			return // consumed already
+		}
+		defer close(stopped)
+		for {
+			select {
+			case <-l.closing:
+				return // drops pending
+			default:
+				break
+			}
+
+			select {
+			case <-l.closing:
+				return
+			case d := <-deliveries:
+				if !yield(d.m, d.err) {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}
+
+	l = newListener(config)
+	go l.connectLoop()
+	return l, seq
+}
@@ -0,0 +1,177 @@
+//go:build go1.23
+
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListenerSeq(t *testing.T) {
+	t.Parallel()
+
+	l, seq := NewListenerSeq(ListenerConfig{Addr: testClient.Addr})
+	channel := randomKey("channel")
+	l.SUBSCRIBE(channel)
+	awaitExecution()
+
+	go func() {
+		for _, message := range []string{"first", "second"} {
+			if _, err := testClient.PUBLISH(channel, message); err != nil {
+				t.Error("PUBLISH error:", err)
+			}
+		}
+		awaitExecution()
+		l.Close()
+	}()
+
+	timeout := time.AfterFunc(time.Second, func() {
+		t.Error("test timeout; closing Listener")
+		l.Close()
+	})
+	defer timeout.Stop()
+
+	var got []string
+	for m, err := range seq {
+		if err != nil {
+			t.Error("sequence error:", err)
+			continue
+		}
+		if m.Channel != channel {
+			t.Errorf("got channel %q, want %q", m.Channel, channel)
+		}
+		got = append(got, string(m.Payload))
+	}
+	if len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("got messages %q, want first and second", got)
+	}
+}
+
+func TestListenerSeqBreak(t *testing.T) {
+	t.Parallel()
+
+	l, seq := NewListenerSeq(ListenerConfig{Addr: testClient.Addr})
+	channel := randomKey("channel")
+	l.SUBSCRIBE(channel)
+	awaitExecution()
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			if _, err := testClient.PUBLISH(channel, "ping"); err != nil {
+				t.Error("PUBLISH error:", err)
+			}
+		}
+	}()
+	for _, err := range seq {
+		if err != nil {
+			t.Error("sequence error:", err)
+		}
+		break
+	}
+
+	// undelivered messages must not block Close
+	done := make(chan error)
+	go func() { done <- l.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error("Close error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Close timeout")
+	}
+}
+
+func TestListenerSeqCloseInLoop(t *testing.T) {
+	t.Parallel()
+
+	l, seq := NewListenerSeq(ListenerConfig{Addr: testClient.Addr})
+	channel := randomKey("channel")
+	l.SUBSCRIBE(channel)
+	awaitExecution()
+
+	for i := 0; i < 3; i++ {
+		if _, err := testClient.PUBLISH(channel, "ping"); err != nil {
+			t.Fatal("PUBLISH error:", err)
+		}
+	}
+
+	timeout := time.AfterFunc(time.Second, func() {
+		panic("Close from within range loop blocked")
+	})
+	defer timeout.Stop()
+
+	var n int
+	for _, err := range seq {
+		if err != nil {
+			t.Error("sequence error:", err)
+		}
+		n++
+		// pending messages must not block Close
+		l.Close()
+	}
+	if n != 1 {
+		t.Errorf("got %d messages after Close, want 1", n)
+	}
+}
+
+func TestListenerSeqCloseUnused(t *testing.T) {
+	t.Parallel()
+
+	l, seq := NewListenerSeq(ListenerConfig{Addr: testClient.Addr})
+	channel := randomKey("channel")
+	l.SUBSCRIBE(channel)
+	awaitExecution()
+
+	if _, err := testClient.PUBLISH(channel, "ping"); err != nil {
+		t.Fatal("PUBLISH error:", err)
+	}
+	awaitExecution()
+
+	// pending message must not block Close
+	done := make(chan error)
+	go func() { done <- l.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error("Close error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close timeout")
+	}
+
+	for range seq {
+		t.Error("got message after Close")
+	}
+}
+
+func TestListenerSeqTwice(t *testing.T) {
+	t.Parallel()
+
+	l, seq := NewListenerSeq(ListenerConfig{Addr: testClient.Addr})
+	defer l.Close()
+	channel := randomKey("channel")
+	l.SUBSCRIBE(channel)
+	awaitExecution()
+
+	if _, err := testClient.PUBLISH(channel, "ping"); err != nil {
+		t.Fatal("PUBLISH error:", err)
+	}
+	for range seq {
+		break
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range seq {
+			t.Error("got message from second range")
+		}
+	}()
+	select {
+	case <-done:
+		break
+	case <-time.After(time.Second):
+		t.Error("second range did not end")
+	}
+}
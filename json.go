@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The JSON commands require the RedisJSON module, as included with Redis Stack.
+// Documents pass as JSON text in Values. Paths either use JSONPath syntax, as
+// in "$.address.city", or the legacy syntax, as in ".address.city".
+
+// JSONSET executes <https://redis.io/commands/json.set>. Flags can be either
+// NX or XX. The return is false if the operation was not performed due to an
+// NX or XX condition.
+func (c *Client[Key, Value]) JSONSET(k Key, path string, doc Value, flags uint) (bool, error) {
+	var r *request
+	switch flags {
+	case 0:
+		r = requestWith3Strings("*4\r\n$8\r\nJSON.SET\r\n$", k, path, doc)
+	case NX:
+		r = requestWith3Strings("*5\r\n$8\r\nJSON.SET\r\n$", k, path, doc)
+		r.buf = append(r.buf, "$2\r\nNX\r\n"...)
+	case XX:
+		r = requestWith3Strings("*5\r\n$8\r\nJSON.SET\r\n$", k, path, doc)
+		r.buf = append(r.buf, "$2\r\nXX\r\n"...)
+	default:
+		if flags&^(NX|XX) != 0 {
+			return false, errors.New("redis: unknown JSON.SET flags")
+		}
+		return false, errors.New("redis: combination of NX and XX not allowed")
+	}
+
+	err := c.commandOK(r)
+	if err == errNull {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// JSONSETIf is like JSONSET, yet with a typed condition. Condition zero applies
+// regardless.
+func (c *Client[Key, Value]) JSONSETIf(k Key, path string, doc Value, condition Exist) (bool, error) {
+	return c.JSONSET(k, path, doc, uint(condition))
+}
+
+// JSONGET executes <https://redis.io/commands/json.get>. The return is the
+// JSON text of each path, combined in an object when more than one path is
+// requested. It is zero if the Key does not exist. The root applies when no
+// paths are specified.
+func (c *Client[Key, Value]) JSONGET(k Key, paths ...string) (Value, error) {
+	return c.commandBulk(requestWithStringAndList("\r\n$8\r\nJSON.GET\r\n$", k, paths).idempotent())
+}
+
+// JSONMGET executes <https://redis.io/commands/json.mget>. The return has the
+// JSON text of the path for each Key, in order of appearance. The Values for
+// non-existing Keys stay zero.
+func (c *Client[Key, Value]) JSONMGET(path string, keys ...Key) ([]Value, error) {
+	r := requestSize("\r\n$9\r\nJSON.MGET", len(keys)+2)
+	r.buf = appendCRLFAndList(r.buf, keys)
+	r.buf = append(r.buf, '$')
+	r.buf = appendStringToDollar(r.buf, path)
+	return c.commandArray(r.idempotent())
+}
+
+// JSONDEL executes <https://redis.io/commands/json.del>. The return is the
+// number of paths deleted. The whole document goes for the root path "$".
+func (c *Client[Key, Value]) JSONDEL(k Key, path string) (int64, error) {
+	return c.commandInteger(requestWith2Strings("*3\r\n$8\r\nJSON.DEL\r\n$", k, path))
+}
+
+// JSONNUMINCRBY executes <https://redis.io/commands/json.numincrby>. The return
+// has the new value for each match of path. Matches which are not a number get
+// NaN. Legacy paths match exactly once.
+func (c *Client[Key, Value]) JSONNUMINCRBY(k Key, path string, increment float64) ([]float64, error) {
+	s := strconv.FormatFloat(increment, 'f', -1, 64)
+	reply, err := c.commandBulk(requestWith3Strings("*4\r\n$14\r\nJSON.NUMINCRBY\r\n$", k, path, s))
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONNumbers([]byte(reply))
+}
+
+// ParseJSONNumbers reads either an array with numbers and nulls, or a single
+// number.
+func parseJSONNumbers(text []byte) ([]float64, error) {
+	if len(text) == 0 || text[0] != '[' {
+		f, err := strconv.ParseFloat(string(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: JSON number reply: %w", err)
+		}
+		return []float64{f}, nil
+	}
+
+	var matches []*float64
+	if err := json.Unmarshal(text, &matches); err != nil {
+		return nil, fmt.Errorf("redis: JSON number reply: %w", err)
+	}
+	numbers := make([]float64, len(matches))
+	for i, p := range matches {
+		if p == nil {
+			numbers[i] = math.NaN()
+		} else {
+			numbers[i] = *p
+		}
+	}
+	return numbers, nil
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseJSONNumbers(t *testing.T) {
+	golden := []struct {
+		text string
+		want []float64
+	}{
+		{"3", []float64{3}},
+		{"-1.5", []float64{-1.5}},
+		{"[2]", []float64{2}},
+		{"[4,null,0.25]", []float64{4, math.NaN(), 0.25}},
+		{"[]", []float64{}},
+	}
+	for _, gold := range golden {
+		got, err := parseJSONNumbers([]byte(gold.text))
+		if err != nil {
+			t.Errorf("%q got error: %s", gold.text, err)
+			continue
+		}
+		if len(got) != len(gold.want) {
+			t.Errorf("%q got %v, want %v", gold.text, got, gold.want)
+			continue
+		}
+		for i, f := range got {
+			if f != gold.want[i] && !(math.IsNaN(f) && math.IsNaN(gold.want[i])) {
+				t.Errorf("%q got %v, want %v", gold.text, got, gold.want)
+				break
+			}
+		}
+	}
+
+	if _, err := parseJSONNumbers([]byte(`"x"`)); err == nil {
+		t.Error("string got no error")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	key := randomKey("doc")
+
+	ok, err := testClient.JSONSET(key, "$", `{"name":"x","n":1}`, 0)
+	if err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("JSON.SET error:", err)
+	}
+	if !ok {
+		t.Error("JSON.SET got false")
+	}
+	if ok, err := testClient.JSONSET(key, "$", `{}`, NX); err != nil {
+		t.Error("JSON.SET NX error:", err)
+	} else if ok {
+		t.Error("JSON.SET NX on existing document got true")
+	}
+
+	if v, err := testClient.JSONGET(key, "$.name"); err != nil {
+		t.Error("JSON.GET error:", err)
+	} else if v != `["x"]` {
+		t.Errorf(`JSON.GET "$.name" got %q, want ["x"]`, v)
+	}
+
+	if numbers, err := testClient.JSONNUMINCRBY(key, "$.n", 2.5); err != nil {
+		t.Error("JSON.NUMINCRBY error:", err)
+	} else if len(numbers) != 1 || numbers[0] != 3.5 {
+		t.Errorf("JSON.NUMINCRBY got %v, want [3.5]", numbers)
+	}
+
+	if values, err := testClient.JSONMGET("$.n", key, randomKey("absent")); err != nil {
+		t.Error("JSON.MGET error:", err)
+	} else if len(values) != 2 || values[0] != "[3.5]" || values[1] != "" {
+		t.Errorf("JSON.MGET got %q, want [3.5] and empty", values)
+	}
+
+	if n, err := testClient.JSONDEL(key, "$.name"); err != nil {
+		t.Error("JSON.DEL error:", err)
+	} else if n != 1 {
+		t.Errorf("JSON.DEL got %d, want 1", n)
+	}
+}
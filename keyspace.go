@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeyspaceEvent is a notification from <https://redis.io/docs/manual/keyspace-events/>.
+type KeyspaceEvent struct {
+	DB  int64  // database number
+	Key string // subject
+	Op  string // operation, e.g., "set", "del" or "expired"
+}
+
+// ParseKeyspaceEvent interprets a message from either a keyspace channel, as in
+// "__keyspace@0__:mykey" with the operation as message, or a keyevent channel,
+// as in "__keyevent@0__:del" with the Key as message. The return is false for
+// any other channel.
+func ParseKeyspaceEvent(channel string, message []byte) (KeyspaceEvent, bool) {
+	var keyspace bool
+	switch {
+	case strings.HasPrefix(channel, "__keyspace@"):
+		keyspace = true
+	case strings.HasPrefix(channel, "__keyevent@"):
+		break
+	default:
+		return KeyspaceEvent{}, false
+	}
+
+	db, subject, ok := strings.Cut(channel[len("__keyspace@"):], "__:")
+	if !ok {
+		return KeyspaceEvent{}, false
+	}
+	n, err := strconv.ParseInt(db, 10, 64)
+	if err != nil {
+		return KeyspaceEvent{}, false
+	}
+
+	if keyspace {
+		return KeyspaceEvent{DB: n, Key: subject, Op: string(message)}, true
+	}
+	return KeyspaceEvent{DB: n, Key: string(message), Op: subject}, true
+}
+
+// KeyspaceFunc returns a ListenerConfig Func which passes each notification to
+// f as an event. Errors pass with a zero event. Messages on channels other than
+// keyspace or keyevent channels are passed as an error.
+func KeyspaceFunc(f func(KeyspaceEvent, error)) func(channel string, message []byte, err error) {
+	return func(channel string, message []byte, err error) {
+		if err != nil {
+			f(KeyspaceEvent{}, err)
+			return
+		}
+		event, ok := ParseKeyspaceEvent(channel, message)
+		if !ok {
+			f(KeyspaceEvent{}, fmt.Errorf("redis: message on channel %q is not a keyspace notification", channel))
+			return
+		}
+		f(event, nil)
+	}
+}
+
+// SUBSCRIBEKeyspace executes SUBSCRIBE on the keyspace channel of each Key in
+// database db. The events have the operation for the respective Key.
+func (l *Listener) SUBSCRIBEKeyspace(db int64, keys ...string) {
+	l.SUBSCRIBE(keyspaceChannels("__keyspace@", db, keys)...)
+}
+
+// SUBSCRIBEKeyevent executes SUBSCRIBE on the keyevent channel of each
+// operation in database db, e.g., "expired" or "evicted". The events have the
+// Key for the respective operation.
+func (l *Listener) SUBSCRIBEKeyevent(db int64, ops ...string) {
+	l.SUBSCRIBE(keyspaceChannels("__keyevent@", db, ops)...)
+}
+
+// UNSUBSCRIBEKeyspace executes UNSUBSCRIBE for SUBSCRIBEKeyspace.
+func (l *Listener) UNSUBSCRIBEKeyspace(db int64, keys ...string) {
+	l.UNSUBSCRIBE(keyspaceChannels("__keyspace@", db, keys)...)
+}
+
+// UNSUBSCRIBEKeyevent executes UNSUBSCRIBE for SUBSCRIBEKeyevent.
+func (l *Listener) UNSUBSCRIBEKeyevent(db int64, ops ...string) {
+	l.UNSUBSCRIBE(keyspaceChannels("__keyevent@", db, ops)...)
+}
+
+func keyspaceChannels(prefix string, db int64, subjects []string) []string {
+	prefix += strconv.FormatInt(db, 10) + "__:"
+	channels := make([]string, len(subjects))
+	for i, s := range subjects {
+		channels[i] = prefix + s
+	}
+	return channels
+}
+
+// NotifyKeyspaceEvents executes <https://redis.io/commands/config-set> on the
+// notify-keyspace-events parameter, as notifications are disabled by default.
+// Classes "KEA" enable all of them, and the empty string disables them again.
+func (c *Client[Key, Value]) NotifyKeyspaceEvents(classes string) error {
+	return c.commandOK(requestWithString("*4\r\n$6\r\nCONFIG\r\n$3\r\nSET\r\n$22\r\nnotify-keyspace-events\r\n$", classes))
+}
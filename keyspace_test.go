@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseKeyspaceEvent(t *testing.T) {
+	golden := []struct {
+		channel, message string
+		want             KeyspaceEvent
+		ok               bool
+	}{
+		{"__keyspace@0__:mykey", "del", KeyspaceEvent{DB: 0, Key: "mykey", Op: "del"}, true},
+		{"__keyevent@12__:expired", "a:b", KeyspaceEvent{DB: 12, Key: "a:b", Op: "expired"}, true},
+		{"__keyspace@3__:__:x", "set", KeyspaceEvent{DB: 3, Key: "__:x", Op: "set"}, true},
+		{"__keyspace@x__:mykey", "del", KeyspaceEvent{}, false},
+		{"__keyspace@0", "del", KeyspaceEvent{}, false},
+		{"news", "del", KeyspaceEvent{}, false},
+	}
+	for _, gold := range golden {
+		got, ok := ParseKeyspaceEvent(gold.channel, []byte(gold.message))
+		if got != gold.want || ok != gold.ok {
+			t.Errorf("%q with %q got %+v %t, want %+v %t", gold.channel, gold.message, got, ok, gold.want, gold.ok)
+		}
+	}
+}
+
+func TestSubscribeKeyevent(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan KeyspaceEvent, 1)
+	l := NewListener(ListenerConfig{
+		Func: KeyspaceFunc(func(event KeyspaceEvent, err error) {
+			if err != nil {
+				if err != ErrClosed {
+					t.Error("Listener error:", err)
+				}
+				return
+			}
+			events <- event
+		}),
+		Addr: testClient.Addr,
+	})
+	defer l.Close()
+
+	op := randomKey("op")
+	l.SUBSCRIBEKeyevent(7, op)
+	awaitExecution()
+
+	// simulate the server notification
+	if n, err := testClient.PUBLISH("__keyevent@7__:"+op, "k"); err != nil {
+		t.Fatal("PUBLISH error:", err)
+	} else if n != 1 {
+		t.Fatalf("PUBLISH got %d clients, want 1", n)
+	}
+	select {
+	case got := <-events:
+		want := KeyspaceEvent{DB: 7, Key: "k", Op: op}
+		if got != want {
+			t.Errorf("got event %+v, want %+v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout awaiting event")
+	}
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// Option modifies a ClientConfig, as applied by NewDefaultClient. Custom
+// options may set any of the ClientConfig fields.
+type Option func(*ClientConfig)
+
+// WithTimeout sets ClientConfig CommandTimeout. Zero disables the limit.
+func WithTimeout(d time.Duration) Option {
+	return func(c *ClientConfig) { c.CommandTimeout = d }
+}
+
+// WithDialTimeout sets ClientConfig DialTimeout.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *ClientConfig) { c.DialTimeout = d }
+}
+
+// WithTLS sets ClientConfig TLS.
+func WithTLS(config *tls.Config) Option {
+	return func(c *ClientConfig) { c.TLS = config }
+}
+
+// WithAuth sets ClientConfig Username and Password. The username may be empty
+// for servers without ACL.
+func WithAuth(username string, password []byte) Option {
+	return func(c *ClientConfig) {
+		c.Username = username
+		c.Password = password
+	}
+}
+
+// WithDB sets ClientConfig DB.
+func WithDB(db int64) Option {
+	return func(c *ClientConfig) { c.DB = db }
+}
+
+// WithPool sets ClientConfig PoolSize.
+func WithPool(size int) Option {
+	return func(c *ClientConfig) { c.PoolSize = size }
+}
+
+// WithName sets ClientConfig Name.
+func WithName(name string) Option {
+	return func(c *ClientConfig) { c.Name = name }
+}
+
+// WithRESP3 sets ClientConfig RESP3, with PushFunc for any push messages.
+func WithRESP3(pushFunc func(kind string, args [][]byte)) Option {
+	return func(c *ClientConfig) {
+		c.RESP3 = true
+		c.PushFunc = pushFunc
+	}
+}
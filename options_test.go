@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultClientOptions(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultClient[string, string](testClient.Addr,
+		WithAuth(testClient.Username, testClient.Password),
+		WithTimeout(2*time.Second),
+		WithDB(2),
+		WithPool(2),
+		WithName("options-test"),
+	)
+	defer c.Close()
+
+	if c.CommandTimeout != 2*time.Second {
+		t.Errorf("got CommandTimeout %s, want 2s", c.CommandTimeout)
+	}
+	if c.DialTimeout != time.Second {
+		t.Errorf("got DialTimeout %s, want the 1s default", c.DialTimeout)
+	}
+	if c.DB != 2 || c.PoolSize != 2 || c.Name != "options-test" {
+		t.Errorf("got DB %d, PoolSize %d and Name %q, want 2, 2 and %q", c.DB, c.PoolSize, c.Name, "options-test")
+	}
+
+	key := randomKey("options")
+	if err := c.SET(key, "v"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	// database 2 only
+	if _, ok, err := testClient.GETOk(key); err != nil {
+		t.Error("GET error:", err)
+	} else if ok {
+		t.Errorf("key %q present in default database", key)
+	}
+}
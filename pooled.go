@@ -0,0 +1,176 @@
+package redis
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// PooledArray is an array reply with all of its elements in a single buffer.
+// Buffers are recycled on Release, which saves an allocation per element, and
+// thus a lot of garbage on large replies in high-throughput readers. Elements
+// must be copied to retain them after Release.
+type PooledArray struct {
+	// Elements are valid until Release. Null elements are nil.
+	Elements [][]byte
+
+	buf  []byte // element content
+	ends []int  // element end offsets in buf, with -1 for null
+}
+
+var pooledArrayPool = sync.Pool{
+	New: func() any { return new(PooledArray) },
+}
+
+// Release hands the buffers back for reuse. The PooledArray must not be used
+// after Release, including Elements.
+func (a *PooledArray) Release() {
+	if cap(a.buf) > 1024*1024 {
+		a.buf = nil // oversized for reuse
+	}
+	if cap(a.Elements) > 64*1024 {
+		a.Elements, a.ends = nil, nil // oversized for reuse
+	}
+	a.Elements = a.Elements[:0]
+	a.buf = a.buf[:0]
+	a.ends = a.ends[:0]
+	pooledArrayPool.Put(a)
+}
+
+// MGETPooled is like MGET, yet with the Values in a PooledArray.
+func (c *Client[Key, Value]) MGETPooled(m ...Key) (*PooledArray, error) {
+	return c.commandPooledArray(requestWithList("\r\n$4\r\nMGET", m).idempotent())
+}
+
+// HMGETPooled is like HMGET, yet with the Values in a PooledArray.
+func (c *Client[Key, Value]) HMGETPooled(k Key, mf ...Key) (*PooledArray, error) {
+	return c.commandPooledArray(requestWithStringAndList("\r\n$5\r\nHMGET\r\n$", k, mf).idempotent())
+}
+
+// LRANGEPooled is like LRANGE, yet with the elements in a PooledArray.
+func (c *Client[Key, Value]) LRANGEPooled(k Key, start, stop int64) (*PooledArray, error) {
+	return c.commandPooledArray(requestWithStringAnd2Decimals("*4\r\n$6\r\nLRANGE\r\n$", k, start, stop).idempotent())
+}
+
+// DoArrayPooled is like DoArray, yet with the elements in a PooledArray.
+func (c *Client[Key, Value]) DoArrayPooled(r *Request) (*PooledArray, error) {
+	req, err := r.request()
+	if err != nil {
+		return nil, err
+	}
+	return c.commandPooledArray(req)
+}
+
+func (c *Client[Key, Value]) commandPooledArray(req *request) (*PooledArray, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	a := pooledArrayPool.Get().(*PooledArray)
+	err = readPooledArray(r, a)
+	c.passRead(err)
+	switch err {
+	case nil, errNull:
+		return a, nil
+	}
+	a.Release()
+	return nil, err
+}
+
+// ReadPooledArray decodes an array reply into a, which must be empty. A null
+// array leaves a empty with errNull.
+func readPooledArray(r *connReader, a *PooledArray) error {
+	l, err := readArrayLen(r)
+	if l == 0 {
+		return err
+	}
+	for ; l > 0; l-- {
+		a.buf, err = appendBulk(a.buf, r)
+		switch err {
+		case nil:
+			a.ends = append(a.ends, len(a.buf))
+		case errNull:
+			a.ends = append(a.ends, -1)
+		default:
+			return err
+		}
+	}
+
+	// slice after the buffer is complete
+	var offset int
+	for _, end := range a.ends {
+		if end < 0 {
+			a.Elements = append(a.Elements, nil)
+			continue
+		}
+		a.Elements = append(a.Elements, a.buf[offset:end:end])
+		offset = end
+	}
+	return nil
+}
+
+// AppendBulk is like readBulk, yet it appends the string to dst.
+func appendBulk(dst []byte, r *connReader) ([]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return dst, err
+	}
+
+	var size int64
+	switch {
+	case len(line) > 3 && line[0] == '$':
+		size = ParseInt(line[1 : len(line)-2])
+		if size < 0 || size > r.sizeMax {
+			if size == -1 {
+				// "null bulk string"
+				return dst, errNull
+			}
+			return dst, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+		}
+
+	case len(line) > 3 && line[0] == '=':
+		// RESP3 verbatim string has a 3-byte format prefix plus colon
+		size = ParseInt(line[1:len(line)-2]) - 4
+		if size < 0 || size > r.sizeMax {
+			return dst, fmt.Errorf("%w; received %.40q for verbatim string", errProtocol, line)
+		}
+		if _, err := r.Discard(4); err != nil {
+			return dst, err
+		}
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return dst, errNull
+
+	case len(line) > 3 && line[0] == '-':
+		return dst, ServerError(line[1 : len(line)-2])
+
+	case len(line) > 3 && line[0] == '#':
+		// RESP3 boolean as the RESP2 integer
+		if line[1] == 't' {
+			return append(dst, '1'), nil
+		}
+		return append(dst, '0'), nil
+
+	case len(line) > 3 && (line[0] == '+' || line[0] == ':' || line[0] == ',' || line[0] == '('):
+		// simple string, integer, RESP3 double or RESP3 big number
+		return append(dst, line[1:len(line)-2]...), nil
+
+	default:
+		return dst, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+	}
+
+	offset := len(dst)
+	if int64(cap(dst)-offset) < size {
+		grown := make([]byte, offset, 2*cap(dst)+int(size))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:offset+int(size)]
+	_, err = io.ReadFull(r, dst[offset:])
+	if err == nil {
+		_, err = r.Discard(2) // skip CRLF
+	}
+	return dst, err
+}
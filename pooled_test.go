@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReadPooledArray(t *testing.T) {
+	r := testReader("*5\r\n$1\r\na\r\n$-1\r\n$0\r\n\r\n:42\r\n$3\r\nbcd\r\n*-1\r\n*1\r\n-ERR x\r\n")
+
+	a := new(PooledArray)
+	if err := readPooledArray(r, a); err != nil {
+		t.Fatal("read error:", err)
+	}
+	const want = `["a" "" "" "42" "bcd"]`
+	if got := fmt.Sprintf("%q", a.Elements); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if a.Elements[1] != nil || a.Elements[2] == nil {
+		t.Errorf("got null %t and empty %t, want null only for the second element", a.Elements[1] == nil, a.Elements[2] == nil)
+	}
+	a.Release()
+
+	a = new(PooledArray)
+	if err := readPooledArray(r, a); err != errNull {
+		t.Errorf("null array got error %v, want errNull", err)
+	}
+	if len(a.Elements) != 0 {
+		t.Errorf("null array got elements %q", a.Elements)
+	}
+	var e ServerError
+	if err := readPooledArray(r, a); !errors.As(err, &e) {
+		t.Errorf("error element got error %v, want a ServerError", err)
+	}
+}
+
+func TestPooledArray(t *testing.T) {
+	t.Parallel()
+	key1, key2 := randomKey("test"), randomKey("test")
+
+	if err := testClient.MSET([]string{key1, key2}, []string{"v1", "v2"}); err != nil {
+		t.Fatal("MSET error:", err)
+	}
+	a, err := testClient.MGETPooled(key1, randomKey("absent"), key2)
+	if err != nil {
+		t.Fatal("MGETPooled error:", err)
+	}
+	if got, want := fmt.Sprintf("%q", a.Elements), `["v1" "" "v2"]`; got != want {
+		t.Errorf("MGETPooled got %s, want %s", got, want)
+	} else if a.Elements[1] != nil {
+		t.Error("MGETPooled got non-nil element for absent key")
+	}
+	a.Release()
+
+	list := randomKey("test-list")
+	for _, v := range []string{"a", "b", "c"} {
+		if _, err := testClient.RPUSH(list, v); err != nil {
+			t.Fatal("RPUSH error:", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		a, err := testClient.LRANGEPooled(list, 0, -1)
+		if err != nil {
+			t.Fatal("LRANGEPooled error:", err)
+		}
+		if got, want := fmt.Sprintf("%q", a.Elements), `["a" "b" "c"]`; got != want {
+			t.Errorf("LRANGEPooled got %s, want %s", got, want)
+		}
+		a.Release()
+	}
+
+	a, err = testClient.LRANGEPooled(randomKey("absent"), 0, -1)
+	if err != nil {
+		t.Fatal("LRANGEPooled on absent key error:", err)
+	}
+	if len(a.Elements) != 0 {
+		t.Errorf("LRANGEPooled on absent key got %q", a.Elements)
+	}
+	a.Release()
+}
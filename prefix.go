@@ -0,0 +1,245 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// KeySpec locates the Key arguments of a command, with argument index zero
+// for the command name. Negative values for last count from the end, i.e.,
+// -1 is the last argument. A zero step means no Keys.
+type keySpec struct {
+	first, last, step int
+}
+
+// KeySpecs has each command with known Key positions, as supported by
+// WithPrefix and CrossSlotCheck.
+var keySpecs = map[string]keySpec{
+	// no Keys
+	"AUTH":      {},
+	"CLIENT":    {},
+	"CLUSTER":   {},
+	"COMMAND":   {},
+	"CONFIG":    {},
+	"ECHO":      {},
+	"FLUSHALL":  {},
+	"FLUSHDB":   {},
+	"HELLO":     {},
+	"INFO":      {},
+	"PING":      {},
+	"PUBLISH":   {},
+	"QUIT":      {},
+	"REPLICAOF": {},
+	"RESET":     {},
+	"SELECT":    {},
+	"SLOWLOG":   {},
+	"SWAPDB":    {},
+	"TIME":      {},
+	"WAIT":      {},
+
+	// first argument
+	"APPEND":         {1, 1, 1},
+	"BF.ADD":         {1, 1, 1},
+	"BF.EXISTS":      {1, 1, 1},
+	"BF.MADD":        {1, 1, 1},
+	"BF.MEXISTS":     {1, 1, 1},
+	"BF.RESERVE":     {1, 1, 1},
+	"CF.ADD":         {1, 1, 1},
+	"CF.ADDNX":       {1, 1, 1},
+	"CF.COUNT":       {1, 1, 1},
+	"CF.DEL":         {1, 1, 1},
+	"CF.EXISTS":      {1, 1, 1},
+	"CF.MEXISTS":     {1, 1, 1},
+	"CF.RESERVE":     {1, 1, 1},
+	"DUMP":           {1, 1, 1},
+	"EXPIRE":         {1, 1, 1},
+	"GET":            {1, 1, 1},
+	"GETRANGE":       {1, 1, 1},
+	"HDEL":           {1, 1, 1},
+	"HGET":           {1, 1, 1},
+	"HGETALL":        {1, 1, 1},
+	"HMGET":          {1, 1, 1},
+	"HMSET":          {1, 1, 1},
+	"HSET":           {1, 1, 1},
+	"INCR":           {1, 1, 1},
+	"INCRBY":         {1, 1, 1},
+	"JSON.DEL":       {1, 1, 1},
+	"JSON.GET":       {1, 1, 1},
+	"JSON.NUMINCRBY": {1, 1, 1},
+	"JSON.SET":       {1, 1, 1},
+	"LINDEX":         {1, 1, 1},
+	"LLEN":           {1, 1, 1},
+	"LPOP":           {1, 1, 1},
+	"LPUSH":          {1, 1, 1},
+	"LRANGE":         {1, 1, 1},
+	"LSET":           {1, 1, 1},
+	"LTRIM":          {1, 1, 1},
+	"MOVE":           {1, 1, 1},
+	"PEXPIRE":        {1, 1, 1},
+	"PTTL":           {1, 1, 1},
+	"RESTORE":        {1, 1, 1},
+	"RPOP":           {1, 1, 1},
+	"RPUSH":          {1, 1, 1},
+	"SADD":           {1, 1, 1},
+	"SCARD":          {1, 1, 1},
+	"SET":            {1, 1, 1},
+	"SMEMBERS":       {1, 1, 1},
+	"SREM":           {1, 1, 1},
+	"STRLEN":         {1, 1, 1},
+	"TYPE":           {1, 1, 1},
+	"ZADD":           {1, 1, 1},
+	"ZRANGE":         {1, 1, 1},
+
+	// second argument
+	"MEMORY": {2, 2, 1},
+
+	// all arguments
+	"DEL":    {1, -1, 1},
+	"MGET":   {1, -1, 1},
+	"SINTER": {1, -1, 1},
+	"SUNION": {1, -1, 1},
+	"UNLINK": {1, -1, 1},
+
+	// all but the path
+	"JSON.MGET": {1, -2, 1},
+
+	// Key–value pairs
+	"MSET": {1, -1, 2},
+
+	// all but the timeout
+	"BLPOP": {1, -2, 1},
+	"BRPOP": {1, -2, 1},
+}
+
+// WithPrefix returns a view of c which prepends prefix to each Key, e.g.,
+// "tenant:42:" for a namespace per tenant. Keys returned by the server, as
+// with BLPOP and BRPOP, have the prefix removed. Commands without Keys, such
+// as FLUSHDB and PUBLISH, apply as is. Commands for which the Key positions
+// are unknown to this package are rejected with an error, rather than leaking
+// outside of the namespace. Prefixes of nested views accumulate. Views share
+// the connection with c, including Close.
+func (c *Client[Key, Value]) WithPrefix(prefix string) *Client[Key, Value] {
+	return c.view(c.ctx, c.keyPrefix+prefix)
+}
+
+// View returns a Client which shares the connection(s) of c.
+func (c *Client[Key, Value]) view(ctx context.Context, keyPrefix string) *Client[Key, Value] {
+	return viewAs[Key, Value](c, ctx, keyPrefix)
+}
+
+// ViewAs returns a view of c with other Key and Value types, e.g., for []byte
+// Values next to string Values, without another connection. The context and
+// the Key prefix of c, if any, apply to the view too. Views share the
+// connection with c, including Close.
+func ViewAs[K, V, Key, Value String](c *Client[Key, Value]) *Client[K, V] {
+	return viewAs[K, V](c, c.ctx, c.keyPrefix)
+}
+
+func viewAs[K, V, Key, Value String](c *Client[Key, Value], ctx context.Context, keyPrefix string) *Client[K, V] {
+	view := &Client[K, V]{
+		ClientConfig: c.ClientConfig,
+		pipeline:     c.pipeline,
+		ctx:          ctx,
+		keyPrefix:    keyPrefix,
+		blocking:     c.blocking,
+	}
+	if c.members != nil {
+		view.members = make([]*Client[K, V], len(c.members))
+		for i := range c.members {
+			view.members[i] = &Client[K, V]{
+				ClientConfig: c.ClientConfig,
+				pipeline:     c.members[i].pipeline,
+				ctx:          ctx,
+				keyPrefix:    keyPrefix,
+			}
+		}
+	}
+	if c.replicas != nil {
+		view.replicas = c.replicas
+		view.readers = make([]*Client[K, V], len(c.readers))
+		for i, r := range c.readers {
+			view.readers[i] = viewAs[K, V](r, ctx, keyPrefix)
+		}
+	}
+	return view
+}
+
+// TrimKeyPrefix removes the prefix of WithPrefix from a Key in a reply.
+func (c *Client[Key, Value]) trimKeyPrefix(k Key) Key {
+	if c.keyPrefix == "" || len(k) < len(c.keyPrefix) || string(k[:len(c.keyPrefix)]) != c.keyPrefix {
+		return k
+	}
+	return k[len(c.keyPrefix):]
+}
+
+// AppendGlobEscape appends s to dst with a backslash before each character
+// which has a special meaning in glob-style patterns.
+func appendGlobEscape(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, s[i])
+	}
+	return dst
+}
+
+// AppendPrefixKeys copies the commands from buf into dst, with prefix inserted
+// in each Key argument. A streamed payload at the end of buf is copied as is.
+func appendPrefixKeys(dst, buf []byte, prefix string) ([]byte, error) {
+	for len(buf) != 0 {
+		// array header
+		i := bytes.IndexByte(buf, '\n')
+		argCount := int(ParseInt(buf[1 : i-1]))
+		dst = append(dst, buf[:i+1]...)
+		buf = buf[i+1:]
+
+		var spec keySpec
+		for arg := 0; arg < argCount; arg++ {
+			i := bytes.IndexByte(buf, '\n')
+			size := int(ParseInt(buf[1 : i-1]))
+			if len(buf) < i+1+size+2 {
+				// streamed payload
+				return append(dst, buf...), nil
+			}
+			value := buf[i+1 : i+1+size]
+
+			if arg == 0 {
+				var ok bool
+				spec, ok = keySpecs[string(bytes.ToUpper(value))]
+				if !ok {
+					return dst, fmt.Errorf("redis: command %q unsupported with key prefix", value)
+				}
+			}
+
+			if spec.isKey(arg, argCount) {
+				dst = append(dst, '$')
+				dst = strconv.AppendUint(dst, uint64(len(prefix)+size), 10)
+				dst = append(dst, '\r', '\n')
+				dst = append(dst, prefix...)
+				dst = append(dst, value...)
+				dst = append(dst, '\r', '\n')
+			} else {
+				dst = append(dst, buf[:i+1+size+2]...)
+			}
+			buf = buf[i+1+size+2:]
+		}
+	}
+	return dst, nil
+}
+
+// IsKey returns whether argument index arg is a Key, with argCount including
+// the command name.
+func (s keySpec) isKey(arg, argCount int) bool {
+	if s.step == 0 || arg < s.first {
+		return false
+	}
+	last := s.last
+	if last < 0 {
+		last += argCount
+	}
+	return arg <= last && (arg-s.first)%s.step == 0
+}
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	prefix := randomKey("tenant") + ":"
+	view := testClient.WithPrefix(prefix)
+
+	if err := view.SET("k", "v"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if v, err := testClient.GET(prefix + "k"); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "v" {
+		t.Errorf("GET without prefix got %q, want %q", v, "v")
+	}
+	if v, err := view.GET("k"); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "v" {
+		t.Errorf("GET with prefix got %q, want %q", v, "v")
+	}
+
+	if err := view.MSET([]string{"a", "b"}, []string{"1", "2"}); err != nil {
+		t.Fatal("MSET error:", err)
+	}
+	if values, err := testClient.MGET(prefix+"a", prefix+"b", "a"); err != nil {
+		t.Error("MGET error:", err)
+	} else if len(values) != 3 || values[0] != "1" || values[1] != "2" {
+		t.Errorf("MGET without prefix got %q, want 1 and 2", values)
+	}
+	if n, err := view.UNLINKArgs("a", "b"); err != nil {
+		t.Error("UNLINK error:", err)
+	} else if n != 2 {
+		t.Errorf("UNLINK with prefix got %d, want 2", n)
+	}
+
+	nested := view.WithPrefix("x:")
+	if _, err := nested.RPUSH("list", "e"); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if k, v, err := nested.BLPOP(time.Second, "none", "list"); err != nil {
+		t.Error("BLPOP error:", err)
+	} else if k != "list" || v != "e" {
+		t.Errorf("BLPOP got %q and %q, want %q and %q", k, v, "list", "e")
+	}
+}
+
+func TestAppendPrefixKeys(t *testing.T) {
+	golden := []struct {
+		req, want string
+	}{
+		{"*2\r\n$3\r\nGET\r\n$1\r\nk\r\n", "*2\r\n$3\r\nGET\r\n$3\r\np:k\r\n"},
+		{"*5\r\n$4\r\nMSET\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n", "*5\r\n$4\r\nMSET\r\n$3\r\np:a\r\n$1\r\n1\r\n$3\r\np:b\r\n$1\r\n2\r\n"},
+		{"*3\r\n$5\r\nBLPOP\r\n$1\r\nk\r\n$1\r\n0\r\n", "*3\r\n$5\r\nBLPOP\r\n$3\r\np:k\r\n$1\r\n0\r\n"},
+		{"*3\r\n$6\r\nUNLINK\r\n$1\r\na\r\n$1\r\nb\r\n", "*3\r\n$6\r\nUNLINK\r\n$3\r\np:a\r\n$3\r\np:b\r\n"},
+		{"*1\r\n$4\r\nPING\r\n*2\r\n$4\r\nINCR\r\n$1\r\nn\r\n", "*1\r\n$4\r\nPING\r\n*2\r\n$4\r\nINCR\r\n$3\r\np:n\r\n"},
+		// streamed payload
+		{"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$9\r\n", "*3\r\n$3\r\nSET\r\n$3\r\np:k\r\n$9\r\n"},
+	}
+	for _, gold := range golden {
+		got, err := appendPrefixKeys(nil, []byte(gold.req), "p:")
+		if err != nil {
+			t.Errorf("%q got error: %s", gold.req, err)
+		} else if string(got) != gold.want {
+			t.Errorf("%q got %q, want %q", gold.req, got, gold.want)
+		}
+	}
+
+	if _, err := appendPrefixKeys(nil, []byte("*2\r\n$4\r\nKEYS\r\n$1\r\n*\r\n"), "p:"); err == nil {
+		t.Error("KEYS got no error")
+	}
+}
+
+func TestAppendGlobEscape(t *testing.T) {
+	const prefix, want = `a*b?c[d]e\`, `a\*b\?c\[d\]e\\`
+	if got := appendGlobEscape(nil, prefix); string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -1,13 +1,16 @@
 package redis
 
 import (
-	"bufio"
+	"context"
+	"crypto/tls"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,25 +19,78 @@ func (c *Client[Key, Value]) PUBLISH(channel Key, message Value) (clientCount in
 	return c.commandInteger(requestWith2Strings("*3\r\n$7\r\nPUBLISH\r\n$", channel, message))
 }
 
+// Payload is a message from ListenerConfig OwnedFunc.
+type Payload struct {
+	// Pattern has the PSUBSCRIBE match, if any.
+	Pattern string
+
+	// Bytes has the content, which is valid until Release.
+	Bytes []byte
+}
+
+var payloadPool = sync.Pool{
+	New: func() any { return new(Payload) },
+}
+
+// Release hands the buffer back for reuse. The Payload must not be used after
+// Release, including Bytes.
+func (p *Payload) Release() {
+	if cap(p.Bytes) > 64*1024 {
+		p.Bytes = nil // oversized for reuse
+	}
+	p.Pattern = ""
+	payloadPool.Put(p)
+}
+
+// WorkerQueueSize is the number of pending messages per worker routine. See
+// ListenerConfig Workers for details.
+const WorkerQueueSize = 64
+
 // ListenerConfig defines a Listener setup.
 type ListenerConfig struct {
 	// Func is the callback interface for both push messages and error
 	// events. Implementations must not retain message—make a copy if the
 	// bytes are used after return. Message invocation is guaranteed to
-	// match the Redis submission order. Slow or blocking receivers should
-	// spawn of in a separate routine.
+	// match the Redis submission order, yet per channel only with Workers.
+	// Slow or blocking receivers should spawn of in a separate routine.
 	Func func(channel string, message []byte, err error)
 
+	// PatternFunc receives the messages from PSUBSCRIBE when not nil, with
+	// the pattern matched. Messages go to Func otherwise. The same rules
+	// apply as for Func, while errors always go to Func.
+	PatternFunc func(pattern, channel string, message []byte)
+
+	// OwnedFunc receives all messages when not nil, instead of Func and
+	// PatternFunc, with a copy of the payload which the receiver owns until
+	// Release. Buffers are recycled, which saves allocation in receivers
+	// that pass messages on to other goroutines. Errors still go to Func.
+	OwnedFunc func(channel string, message *Payload)
+
 	// Upper boundary for the number of bytes in a message payload.
-	// Larger messages are skipped with an io.ErrShortBuffer to Func.
-	// Zero defaults to 32 KiB. Values larger than SizeMax are capped
-	// to SizeMax.
+	// Larger messages are skipped with an io.ErrShortBuffer to Func,
+	// unless LargeMessageMax permits. Zero defaults to 32 KiB. Values
+	// larger than SizeMax are capped to SizeMax.
 	BufferSize int
 
+	// LargeMessageMax is the upper boundary for messages which exceed the
+	// BufferSize. Such messages are read into a separate allocation, which
+	// is recycled when possible. Larger messages are skipped with an
+	// io.ErrShortBuffer to Func. Zero disables large messages. Values
+	// larger than SizeMax are capped to SizeMax.
+	LargeMessageMax int
+
 	// The host defaults to localhost, and the port defaults to 6379.
 	// Thus, the empty string defaults to "localhost:6379". Use an
 	// absolute file path (e.g. "/var/run/redis.sock") for Unix
 	// domain sockets.
+	//
+	// Multiple addresses are separated by comma, as in "rds1.example.com,
+	// rds2.example.com:6380", for simple failover. Connection establishment
+	// tries each address in line, starting with the one last connected.
+	//
+	// Addresses in URL notation, as in "wss://gw.example.com/redis", pass
+	// to DialFunc as is, with the scheme as the network. Such transports,
+	// e.g., to a RESP-over-WebSocket gateway, enable js/wasm builds.
 	Addr string
 
 	// Limit execution duration of AUTH, QUIT, SUBSCRIBE & UNSUBSCRIBE.
@@ -47,8 +103,62 @@ type ListenerConfig struct {
 	// Zero defaults to one second.
 	DialTimeout time.Duration
 
+	// Reconnect defines the delay schedule for connection establishment.
+	// The Listener closes once any Attempts limit is reached.
+	Reconnect Backoff
+
+	// DialFunc establishes the network connections when not nil, e.g., for
+	// SSH tunnels, custom socket options, or network namespaces. Use the
+	// DialContext method for a custom net.Dialer. Network is "unix" for
+	// absolute file paths in Addr, the scheme for URLs, and "tcp" otherwise.
+	// The context expires with DialTimeout. Connection tuning is left to the
+	// function. Connections without deadline support, such as some
+	// WebSocket bridges, lose the timeouts.
+	DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// TLS when not nil. Client certificates, as required for mutual TLS,
+	// apply from the configuration, just like the RootCAs, and any custom
+	// verification with VerifyPeerCertificate or VerifyConnection, e.g.,
+	// for certificate pinning. ServerName, as used for both verification
+	// and SNI, defaults to the host from Addr.
+	TLS *tls.Config
+
 	// AUTH when not nil.
 	Password []byte
+
+	// AUTH with an ACL user when not empty. Password must be set too.
+	// ACL requires Redis version 6 or later.
+	Username string
+
+	// CLIENT SETNAME when not empty. The name shows in CLIENT LIST and
+	// in the SLOWLOG, which helps to identify connections. Spaces are not
+	// permitted.
+	Name string
+
+	// Trace receives the protocol exchange when not nil, for debugging
+	// purposes. See ClientConfig Trace for the details.
+	Trace io.Writer
+
+	// Workers sets the number of goroutines for message callbacks when
+	// positive, such that slow receivers don't stall the connection. Each
+	// message is copied, and then queued on the worker of its channel,
+	// which preserves the order per channel. Messages on distinct channels
+	// may be delivered in any order, and concurrently. Func, PatternFunc
+	// and OwnedFunc must be safe for concurrent use in such case. A full
+	// queue, of WorkerQueueSize messages, stalls the connection
+	// nevertheless. Errors go to Func directly.
+	Workers int
+
+	// OnConnect is called on each connection establishment when not nil,
+	// with the channels and patterns in resubscription, which are empty on
+	// the first connect. Any messages published while offline are lost.
+	// The slices must not be modified. Calls are sequential, and slow or
+	// blocking receivers delay message reception.
+	OnConnect func(channels, patterns []string)
+
+	// OnDisconnect is called when a connection is lost, after OnConnect,
+	// when not nil. Err has the cause, which is ErrClosed on Close.
+	OnDisconnect func(err error)
 }
 
 func (c *ListenerConfig) normalize() {
@@ -61,6 +171,9 @@ func (c *ListenerConfig) normalize() {
 	if c.BufferSize > SizeMax {
 		c.BufferSize = SizeMax
 	}
+	if c.LargeMessageMax > SizeMax {
+		c.LargeMessageMax = SizeMax
+	}
 	c.Addr = normalizeAddr(c.Addr)
 	if c.CommandTimeout == 0 {
 		c.CommandTimeout = time.Second
@@ -75,12 +188,29 @@ func (c *ListenerConfig) normalize() {
 //
 // Multiple goroutines may invoke methods on a Listener simultaneously.
 type Listener struct {
+	// Statistics come first for 64-bit alignment of atomic access.
+	messageCount int64
+	connectCount int64
+	ioCounters
+
+	// Last connect failure, if any, as a *connectFailure.
+	lastConnectFailure atomic.Value
+
 	mutex sync.Mutex
 
-	ListenerConfig // read-only attributes
+	// Read-only attributes
+	ListenerConfig
+
+	// Addr and Password in use, as replaced by SetAddr and SetPassword.
+	target dialTarget
 
 	// current connection, which may be nil when offline
 	conn net.Conn
+	// address of conn, from the failover sequence in target
+	connAddr string
+
+	// connection in replacement due ForceReconnect, if any
+	forcedConn net.Conn
 
 	// Subs maps SUBSCRIBE patterns to their request timestamp.
 	// The timestamp is zeroed once the server confirmed subscription.
@@ -90,27 +220,74 @@ type Listener struct {
 	// Entries are removed once confirmed.
 	unsubs map[string]time.Time
 
+	// Psubs and punsubs are like subs and unsubs, yet for PSUBSCRIBE and
+	// PUNSUBSCRIBE respectively.
+	psubs, punsubs map[string]time.Time
+
+	// SubAcks and psubAcks have the confirmation awaits per SUBSCRIBE
+	// channel and PSUBSCRIBE pattern respectively.
+	subAcks, psubAcks map[string][]*subscribeAck
+
 	// Interval for command expiry check.
 	expireTimer *time.Timer
 
+	// Message queue per worker routine, if any.
+	workers     []chan workerJob
+	workersDone sync.WaitGroup
+
 	// shutdown signaling
-	quited time.Time
-	closed chan struct{}
+	quited  time.Time
+	closing chan struct{} // closed once quited is set
+	closed  chan struct{}
 }
 
+// Subscriber is the subscription interface of a Listener. Code which depends on
+// Subscriber instead of Listener can run on a test double too.
+type Subscriber interface {
+	SUBSCRIBE(channels ...string)
+	UNSUBSCRIBE(channels ...string)
+	PSUBSCRIBE(patterns ...string)
+	PUNSUBSCRIBE(patterns ...string)
+	SUBSCRIBEAck(channels ...string) <-chan error
+	PSUBSCRIBEAck(patterns ...string) <-chan error
+	Close() error
+}
+
+var _ Subscriber = (*Listener)(nil)
+
 // NewListener launches a managed connection.
 func NewListener(config ListenerConfig) *Listener {
+	l := newListener(config)
+	// launch connection management
+	go l.connectLoop()
+	return l
+}
+
+// NewListener returns a Listener without connection management.
+func newListener(config ListenerConfig) *Listener {
 	config.normalize()
 
 	l := &Listener{
 		ListenerConfig: config,
+		target:         dialTarget{addr: config.Addr, password: config.Password},
 		subs:           make(map[string]time.Time),
 		unsubs:         make(map[string]time.Time),
+		psubs:          make(map[string]time.Time),
+		punsubs:        make(map[string]time.Time),
+		subAcks:        make(map[string][]*subscribeAck),
+		psubAcks:       make(map[string][]*subscribeAck),
+		closing:        make(chan struct{}),
 		closed:         make(chan struct{}),
 	}
 
-	// launch connection management
-	go l.connectLoop()
+	if config.Workers > 0 {
+		l.workers = make([]chan workerJob, config.Workers)
+		l.workersDone.Add(config.Workers)
+		for i := range l.workers {
+			l.workers[i] = make(chan workerJob, WorkerQueueSize)
+			go l.work(l.workers[i])
+		}
+	}
 
 	return l
 }
@@ -141,14 +318,11 @@ func (l *Listener) expire(timer *time.Timer) {
 		// continue in lock
 
 		oldest := l.quited
-		for _, reqTime := range l.subs {
-			if !reqTime.IsZero() && (oldest.IsZero() || reqTime.Before(oldest)) {
-				oldest = reqTime
-			}
-		}
-		for _, reqTime := range l.unsubs {
-			if !reqTime.IsZero() && (oldest.IsZero() || reqTime.Before(oldest)) {
-				oldest = reqTime
+		for _, pending := range [...]map[string]time.Time{l.subs, l.unsubs, l.psubs, l.punsubs} {
+			for _, reqTime := range pending {
+				if !reqTime.IsZero() && (oldest.IsZero() || reqTime.Before(oldest)) {
+					oldest = reqTime
+				}
 			}
 		}
 		// continue in lock
@@ -183,6 +357,7 @@ func (l *Listener) Close() error {
 	l.mutex.Lock()
 	if l.quited.IsZero() {
 		l.quited = time.Now()
+		close(l.closing)
 		if l.expireTimer == nil {
 			l.expireTimer = time.NewTimer(l.CommandTimeout)
 			go l.expire(l.expireTimer)
@@ -209,31 +384,59 @@ func (l *Listener) closeConn(conn net.Conn) {
 
 func (l *Listener) connectLoop() {
 	defer func() {
+		l.mutex.Lock()
+		for name := range l.subAcks {
+			resolveAcks(l.subAcks, name, ErrClosed)
+		}
+		for name := range l.psubAcks {
+			resolveAcks(l.psubAcks, name, ErrClosed)
+		}
+		l.mutex.Unlock()
+
+		// flush workers
+		for _, jobs := range l.workers {
+			close(jobs)
+		}
+		l.workersDone.Wait()
+
 		// confirmed shutdown
 		l.Func("", nil, ErrClosed)
 		// Close awaits complition
 		close(l.closed)
 	}()
 
+	// Addr and Password apply per connect.
+	config := ClientConfig{
+		CommandTimeout: l.CommandTimeout,
+		DialTimeout:    l.DialTimeout,
+		DialFunc:       l.DialFunc,
+		TLS:            l.TLS,
+		Username:       l.Username,
+		Name:           l.Name,
+		Trace:          l.Trace,
+		SizeMax:        SizeMax,
+		ElementMax:     ElementMax,
+	}
+	var addrIndex int
+
 	var retryDelay time.Duration
+	var failures int
 	for {
-		config := ClientConfig{
-			Addr:           l.Addr,
-			CommandTimeout: l.CommandTimeout,
-			DialTimeout:    l.DialTimeout,
-			Password:       l.Password,
-		}
-		conn, reader, err := config.connect(l.BufferSize)
+		conn, reader, err := config.connect(&l.target, l.BufferSize, &addrIndex, &l.ioCounters)
 		if err != nil {
-			retry := time.NewTimer(retryDelay)
+			failures++
+			retry := time.NewTimer(l.Reconnect.randomize(retryDelay))
+			l.lastConnectFailure.Store(&connectFailure{err, time.Now()})
 
 			// propagate error
 			l.Func("", nil, fmt.Errorf("redis: listener offline: %w", err))
 
-			retryDelay = 2*retryDelay + time.Millisecond
-			if retryDelay > DialDelayMax {
-				retryDelay = DialDelayMax
+			if l.Reconnect.exhausted(failures) {
+				retry.Stop()
+				return
 			}
+
+			retryDelay = l.Reconnect.next(retryDelay)
 			<-retry.C
 
 			l.mutex.Lock()
@@ -247,9 +450,13 @@ func (l *Listener) connectLoop() {
 		}
 		// connect success
 		retryDelay = 0
+		failures = 0
+		atomic.AddInt64(&l.connectCount, 1)
 
 		// install
-		subs, ok := l.releaseConn(conn)
+		addrs, _ := l.target.load()
+		addrList := strings.Split(addrs, ",")
+		subs, psubs, ok := l.releaseConn(conn, addrList[addrIndex%len(addrList)])
 		if !ok {
 			return // accept exit
 		}
@@ -258,14 +465,22 @@ func (l *Listener) connectLoop() {
 			go func(conn net.Conn) {
 				l.submit(conn, requestWithList("\r\n$9\r\nSUBSCRIBE", subs))
 			}(conn)
-
+		}
+		if len(psubs) != 0 {
+			go func(conn net.Conn) {
+				l.submit(conn, requestWithList("\r\n$10\r\nPSUBSCRIBE", psubs))
+			}(conn)
+		}
+		if l.OnConnect != nil {
+			l.OnConnect(subs, psubs)
 		}
 
 		// operate
 		err = l.readLoop(reader)
-		if err != nil {
-			l.Func("", nil, err)
-		} else {
+		if err == nil {
+			if l.OnDisconnect != nil {
+				l.OnDisconnect(ErrClosed)
+			}
 			return
 		}
 		l.closeConn(conn)
@@ -273,28 +488,48 @@ func (l *Listener) connectLoop() {
 		// retract
 		l.mutex.Lock()
 		l.conn = nil
+		forced := l.forcedConn == conn
+		l.forcedConn = nil
 		quited := l.quited
 		l.mutex.Unlock()
+		if forced {
+			err = errReconnect // cause
+		} else {
+			l.Func("", nil, err)
+		}
+		if l.OnDisconnect != nil {
+			if !quited.IsZero() {
+				err = ErrClosed // cause
+			}
+			l.OnDisconnect(err)
+		}
 		if !quited.IsZero() {
 			return
 		}
 	}
 }
 
-func (l *Listener) releaseConn(conn net.Conn) (subs []string, ok bool) {
+func (l *Listener) releaseConn(conn net.Conn, addr string) (subs, psubs []string, ok bool) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
 	if !l.quited.IsZero() {
-		return nil, false
+		return nil, nil, false
 	}
 
 	l.conn = conn
+	l.connAddr = addr
 
 	// clear pendig unsubscribes
 	for name := range l.unsubs {
 		delete(l.unsubs, name)
 		delete(l.subs, name)
+		resolveAcks(l.subAcks, name, errUnsubscribed)
+	}
+	for name := range l.punsubs {
+		delete(l.punsubs, name)
+		delete(l.psubs, name)
+		resolveAcks(l.psubAcks, name, errUnsubscribed)
 	}
 
 	// init subscription requests
@@ -303,16 +538,20 @@ func (l *Listener) releaseConn(conn net.Conn) (subs []string, ok bool) {
 		l.subs[name] = reqTime
 		subs = append(subs, name)
 	}
+	for name := range l.psubs {
+		l.psubs[name] = reqTime
+		psubs = append(psubs, name)
+	}
 
-	if len(subs) != 0 {
+	if len(subs) != 0 || len(psubs) != 0 {
 		l.expireTimer = time.NewTimer(l.CommandTimeout)
 		go l.expire(l.expireTimer)
 	}
 
-	return subs, true
+	return subs, psubs, true
 }
 
-func (l *Listener) readLoop(reader *bufio.Reader) error {
+func (l *Listener) readLoop(reader *connReader) error {
 	// confirmed state as message channel mapping
 	confirmedSubs := make(map[string]string)
 
@@ -354,6 +593,7 @@ func (l *Listener) readLoop(reader *bufio.Reader) error {
 
 			l.mutex.Lock()
 			l.subs[channel] = time.Time{}
+			resolveAcks(l.subAcks, channel, nil)
 			l.mutex.Unlock()
 			confirmedSubs[channel] = channel
 
@@ -378,6 +618,53 @@ func (l *Listener) readLoop(reader *bufio.Reader) error {
 			l.mutex.Unlock()
 			delete(confirmedSubs, channel)
 
+		case head1 == '*'|'4'<<8|'\r'<<16|'\n'<<24|'$'<<32|'8'<<40|'\r'<<48|'\n'<<56 &&
+			head2 == 'p'|'m'<<8|'e'<<16|'s'<<24|'s'<<32|'a'<<40|'g'<<48|'e'<<56:
+			err = l.onPMessage(reader)
+			if err != nil {
+				return err
+			}
+
+		case head1 == '*'|'3'<<8|'\r'<<16|'\n'<<24|'$'<<32|'1'<<40|'0'<<48|'\r'<<56 &&
+			head2 == '\n'|'p'<<8|'s'<<16|'u'<<24|'b'<<32|'s'<<40|'c'<<48|'r'<<56:
+			if _, err := reader.Discard(21); err != nil {
+				return fmt.Errorf("redis: psubscribe array-reply: %w", err)
+			}
+
+			pattern, err := readBulk[string](reader)
+			if err != nil {
+				return fmt.Errorf("redis: psubscribe array-reply pattern: %w", err)
+			}
+			// subscription count is useless with concurrency
+			if _, err := readInteger(reader); err != nil {
+				return fmt.Errorf("redis: psubscribe array-reply count: %w", err)
+			}
+
+			l.mutex.Lock()
+			l.psubs[pattern] = time.Time{}
+			resolveAcks(l.psubAcks, pattern, nil)
+			l.mutex.Unlock()
+
+		case head1 == '*'|'3'<<8|'\r'<<16|'\n'<<24|'$'<<32|'1'<<40|'2'<<48|'\r'<<56 &&
+			head2 == '\n'|'p'<<8|'u'<<16|'n'<<24|'s'<<32|'u'<<40|'b'<<48|'s'<<56:
+			if _, err := reader.Discard(23); err != nil {
+				return fmt.Errorf("redis: punsubscribe array-reply: %w", err)
+			}
+
+			pattern, err := readBulk[string](reader)
+			if err != nil {
+				return fmt.Errorf("redis: punsubscribe array-reply pattern: %w", err)
+			}
+			// subscription count is useless with concurrency
+			if _, err := readInteger(reader); err != nil {
+				return fmt.Errorf("redis: punsubscribe array-reply count: %w", err)
+			}
+
+			l.mutex.Lock()
+			delete(l.psubs, pattern)
+			delete(l.punsubs, pattern)
+			l.mutex.Unlock()
+
 		case head[0] == '-':
 			line, err := reader.ReadString('\n')
 			if err != nil {
@@ -394,7 +681,9 @@ func (l *Listener) readLoop(reader *bufio.Reader) error {
 	}
 }
 
-func (l *Listener) onMessage(r *bufio.Reader, confirmedSubs map[string]string) error {
+func (l *Listener) onMessage(r *connReader, confirmedSubs map[string]string) error {
+	atomic.AddInt64(&l.messageCount, 1)
+
 	_, err := r.Discard(17)
 	if err != nil {
 		return fmt.Errorf("redis: message array-reply: %w", err)
@@ -409,7 +698,7 @@ func (l *Listener) onMessage(r *bufio.Reader, confirmedSubs map[string]string) e
 		return fmt.Errorf("redis: message array-reply channel-size %.40q", line)
 	}
 	channelSize := ParseInt(line[1 : len(line)-2])
-	if channelSize < 0 || channelSize > SizeMax {
+	if channelSize < 0 || channelSize > r.sizeMax {
 		return fmt.Errorf("redis: message array-reply channel-size %.40q", line)
 	}
 	channelSlice, err := r.Peek(int(channelSize))
@@ -427,34 +716,150 @@ func (l *Listener) onMessage(r *bufio.Reader, confirmedSubs map[string]string) e
 	}
 
 	// parse payload
-	line, err = readLine(r)
+	payloadSize, err := readPayloadSize(r)
 	if err != nil {
-		return fmt.Errorf("redis: message array-reply payload-size: %w", err)
+		return err
 	}
-	if len(line) < 4 || line[0] != '$' {
-		return fmt.Errorf("redis: message array-reply payload-size %.40q", line)
+	return l.deliver(r, "", channel, payloadSize)
+}
+
+func (l *Listener) onPMessage(r *connReader) error {
+	atomic.AddInt64(&l.messageCount, 1)
+
+	_, err := r.Discard(18)
+	if err != nil {
+		return fmt.Errorf("redis: pmessage array-reply: %w", err)
 	}
-	payloadSize := ParseInt(line[1 : len(line)-2])
-	if payloadSize < 0 || payloadSize > SizeMax {
-		return fmt.Errorf("redis: message array-reply payload-size %.40q", line)
+	pattern, err := readBulk[string](r)
+	if err != nil {
+		return fmt.Errorf("redis: pmessage array-reply pattern: %w", err)
 	}
+	channel, err := readBulk[string](r)
+	if err != nil {
+		return fmt.Errorf("redis: pmessage array-reply channel: %w", err)
+	}
+
+	// parse payload
+	payloadSize, err := readPayloadSize(r)
+	if err != nil {
+		return err
+	}
+	return l.deliver(r, pattern, channel, payloadSize)
+}
+
+// Deliver passes a payload of payloadSize bytes, plus CRLF, from r to the
+// callback. The pattern is empty for SUBSCRIBE messages.
+func (l *Listener) deliver(r *connReader, pattern, channel string, payloadSize int64) error {
 	if payloadSize > int64(l.BufferSize) {
-		l.Func(channel, nil, io.ErrShortBuffer)
-	} else {
-		payloadSlice, err := r.Peek(int(payloadSize))
-		if err != nil {
+		if payloadSize > int64(l.LargeMessageMax) {
+			l.Func(channel, nil, io.ErrShortBuffer)
+			if _, err := r.Discard(int(payloadSize) + 2); err != nil {
+				return fmt.Errorf("redis: message array-reply payload: %w", err)
+			}
+			return nil
+		}
+
+		// read beyond the buffer
+		p := payloadPool.Get().(*Payload)
+		p.Pattern = pattern
+		if int64(cap(p.Bytes)) < payloadSize+2 {
+			p.Bytes = make([]byte, payloadSize+2)
+		}
+		p.Bytes = p.Bytes[:payloadSize+2]
+		if _, err := io.ReadFull(r, p.Bytes); err != nil {
+			p.Release()
 			return fmt.Errorf("redis: message array-reply payload: %w", err)
 		}
+		p.Bytes = p.Bytes[:payloadSize] // strip CRLF
+		l.dispatch(channel, p)
+		return nil
+	}
+
+	payloadSlice, err := r.Peek(int(payloadSize))
+	if err != nil {
+		return fmt.Errorf("redis: message array-reply payload: %w", err)
+	}
+	switch {
+	case l.workers != nil || l.OwnedFunc != nil:
+		p := payloadPool.Get().(*Payload)
+		p.Pattern = pattern
+		p.Bytes = append(p.Bytes[:0], payloadSlice...)
+		l.dispatch(channel, p)
+	case pattern != "" && l.PatternFunc != nil:
+		l.PatternFunc(pattern, channel, payloadSlice)
+	default:
 		l.Func(channel, payloadSlice, nil)
 	}
 	_, err = r.Discard(int(payloadSize) + 2) // skip CRLF
 	if err != nil {
 		return fmt.Errorf("redis: message array-reply payload-CRLF: %w", err)
 	}
-
 	return nil
 }
 
+// Dispatch passes a message to the workers, if any, or to the callback.
+func (l *Listener) dispatch(channel string, p *Payload) {
+	if l.workers != nil {
+		l.workers[workerIndex(channel, len(l.workers))] <- workerJob{channel, p}
+		return
+	}
+	l.run(channel, p)
+}
+
+// Run invokes the callback with p, which is released afterwards, unless the
+// callback owns p.
+func (l *Listener) run(channel string, p *Payload) {
+	switch {
+	case l.OwnedFunc != nil:
+		l.OwnedFunc(channel, p)
+		return // receiver releases
+	case p.Pattern != "" && l.PatternFunc != nil:
+		l.PatternFunc(p.Pattern, channel, p.Bytes)
+	default:
+		l.Func(channel, p.Bytes, nil)
+	}
+	p.Release()
+}
+
+// WorkerJob is a message for a worker routine.
+type workerJob struct {
+	channel string
+	payload *Payload
+}
+
+// WorkerIndex maps channels to a worker with FNV-1a.
+func workerIndex(channel string, n int) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(channel); i++ {
+		h ^= uint32(channel[i])
+		h *= 16777619
+	}
+	return int(h % uint32(n))
+}
+
+// Work runs the callbacks for each message in jobs, until closed.
+func (l *Listener) work(jobs <-chan workerJob) {
+	defer l.workersDone.Done()
+	for job := range jobs {
+		l.run(job.channel, job.payload)
+	}
+}
+
+func readPayloadSize(r *connReader) (int64, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return 0, fmt.Errorf("redis: message array-reply payload-size: %w", err)
+	}
+	if len(line) < 4 || line[0] != '$' {
+		return 0, fmt.Errorf("redis: message array-reply payload-size %.40q", line)
+	}
+	payloadSize := ParseInt(line[1 : len(line)-2])
+	if payloadSize < 0 || payloadSize > r.sizeMax {
+		return 0, fmt.Errorf("redis: message array-reply payload-size %.40q", line)
+	}
+	return payloadSize, nil
+}
+
 // submit either sends a request or it closes the connection.
 func (l *Listener) submit(conn net.Conn, req *request) {
 	defer req.free()
@@ -467,65 +872,173 @@ func (l *Listener) submit(conn net.Conn, req *request) {
 // SUBSCRIBE executes <https://redis.io/commands/subscribe> in a persistent
 // manner. New connections automatically re-subscribe (until UNSUBSCRIBE).
 func (l *Listener) SUBSCRIBE(channels ...string) {
-	var channelN int
+	l.enqueue(l.subs, "\r\n$9\r\nSUBSCRIBE", "subscribe channel", channels)
+}
+
+// UNSUBSCRIBE executes <https://redis.io/commands/unsubscribe>, yet never with
+// zero arguments.
+func (l *Listener) UNSUBSCRIBE(channels ...string) {
+	l.enqueue(l.unsubs, "\r\n$11\r\nUNSUBSCRIBE", "unsubscribe channel", channels)
+}
+
+// PSUBSCRIBE executes <https://redis.io/commands/psubscribe> in a persistent
+// manner. New connections automatically re-subscribe (until PUNSUBSCRIBE).
+// Messages go to the ListenerConfig PatternFunc, if any.
+func (l *Listener) PSUBSCRIBE(patterns ...string) {
+	l.enqueue(l.psubs, "\r\n$10\r\nPSUBSCRIBE", "psubscribe pattern", patterns)
+}
+
+// PUNSUBSCRIBE executes <https://redis.io/commands/punsubscribe>, yet never
+// with zero arguments.
+func (l *Listener) PUNSUBSCRIBE(patterns ...string) {
+	l.enqueue(l.punsubs, "\r\n$12\r\nPUNSUBSCRIBE", "punsubscribe pattern", patterns)
+}
+
+// SetAddr replaces the address(es) from ListenerConfig Addr for any connection
+// establishment from then on, e.g., for DNS cutovers. The ListenerConfig itself
+// remains as is. The connection in use remains until it is lost, or until
+// ForceReconnect.
+func (l *Listener) SetAddr(addr string) {
+	addr = normalizeAddr(addr)
+	l.target.mutex.Lock()
+	l.target.addr = addr
+	l.target.mutex.Unlock()
+}
+
+// SetPassword replaces the password from ListenerConfig Password for any
+// connection establishment from then on, e.g., for credential rotation. The
+// ListenerConfig itself remains as is, and so does the Username. The connection
+// in use remains until it is lost, or until ForceReconnect.
+func (l *Listener) SetPassword(password []byte) {
+	l.target.mutex.Lock()
+	l.target.password = password
+	l.target.mutex.Unlock()
+}
 
+// ForceReconnect replaces the connection with a new one, if any, such that
+// updates from SetAddr and SetPassword apply immediately. Subscriptions
+// continue on the new connection, with OnDisconnect and OnConnect called as
+// usual. Any messages published in between are lost.
+func (l *Listener) ForceReconnect() {
 	l.mutex.Lock()
-	reqTime := time.Now()
-	for _, s := range channels {
-		if len(s) > SizeMax {
-			go l.Func(s, nil, fmt.Errorf("%d-byte subscribe channel dropped", len(s)))
+	conn := l.conn
+	if conn != nil && l.quited.IsZero() {
+		l.forcedConn = conn
+	} else {
+		conn = nil // offline or closing
+	}
+	l.mutex.Unlock()
+
+	if conn != nil {
+		l.closeConn(conn)
+	}
+}
+
+// SUBSCRIBEAck is like SUBSCRIBE, yet the return receives nil once the server
+// confirmed each of the channels, or an error when the subscription can't
+// complete, which includes ErrClosed. Unconfirmed subscriptions await any
+// reconnects.
+func (l *Listener) SUBSCRIBEAck(channels ...string) <-chan error {
+	return l.ack(l.subs, l.subAcks, "subscribe channel", channels, l.SUBSCRIBE)
+}
+
+// PSUBSCRIBEAck is like PSUBSCRIBE, yet with a confirmation as described by
+// SUBSCRIBEAck.
+func (l *Listener) PSUBSCRIBEAck(patterns ...string) <-chan error {
+	return l.ack(l.psubs, l.psubAcks, "psubscribe pattern", patterns, l.PSUBSCRIBE)
+}
+
+// ErrUnsubscribed rejects confirmation of a subscription due to an UNSUBSCRIBE
+// or PUNSUBSCRIBE.
+var errUnsubscribed = errors.New("redis: unsubscribed before confirmation")
+
+// SubscribeAck is a confirmation await of one or more subscriptions.
+type subscribeAck struct {
+	pending int        // number of confirmations to go
+	done    chan error // buffered
+}
+
+// ResolveAcks either confirms name on each await, or it fails each await with
+// err. The mutex must be held.
+func resolveAcks(acks map[string][]*subscribeAck, name string, err error) {
+	for _, a := range acks[name] {
+		if a.pending == 0 {
+			continue // failed already
+		}
+		if err != nil {
+			a.pending = 0
+			a.done <- err
 			continue
 		}
-		if _, ok := l.subs[s]; ok {
-			continue // redundant
+		a.pending--
+		if a.pending == 0 {
+			a.done <- nil
 		}
-		l.subs[s] = reqTime
-		// rewrite & count
-		channels[channelN] = s
-		channelN++
 	}
+	delete(acks, name)
+}
 
-	conn := l.conn
-	if conn != nil && channelN != 0 && l.expireTimer == nil {
-		l.expireTimer = time.NewTimer(l.CommandTimeout)
-		go l.expire(l.expireTimer)
+func (l *Listener) ack(subs map[string]time.Time, acks map[string][]*subscribeAck, desc string, names []string, submit func(...string)) <-chan error {
+	a := &subscribeAck{done: make(chan error, 1)}
+
+	l.mutex.Lock()
+	if !l.quited.IsZero() {
+		l.mutex.Unlock()
+		a.done <- ErrClosed
+		return a.done
+	}
+	for _, s := range names {
+		if len(s) > SizeMax {
+			l.mutex.Unlock()
+			a.done <- fmt.Errorf("%d-byte %s dropped", len(s), desc)
+			return a.done
+		}
+	}
+	for _, s := range names {
+		if reqTime, ok := subs[s]; ok && reqTime.IsZero() {
+			continue // confirmed already
+		}
+		acks[s] = append(acks[s], a)
+		a.pending++
+	}
+	if a.pending == 0 {
+		a.done <- nil
 	}
 	l.mutex.Unlock()
 
-	if conn != nil && channelN != 0 {
-		l.submit(conn, requestWithList("\r\n$9\r\nSUBSCRIBE", channels[:channelN]))
-	}
+	submit(names...)
+	return a.done
 }
 
-// UNSUBSCRIBE executes <https://redis.io/commands/unsubscribe>, yet never with
-// zero arguments.
-func (l *Listener) UNSUBSCRIBE(channels ...string) {
-	var channelN int
+// Enqueue registers each name as pending in a subscription map, and it submits
+// the names which were not pending yet.
+func (l *Listener) enqueue(pending map[string]time.Time, prefix, desc string, names []string) {
+	var nameN int
 
 	l.mutex.Lock()
 	reqTime := time.Now()
-	for _, s := range channels {
+	for _, s := range names {
 		if len(s) > SizeMax {
-			go l.Func(s, nil, fmt.Errorf("%d-byte unsubscribe channel dropped", len(s)))
+			go l.Func(s, nil, fmt.Errorf("%d-byte %s dropped", len(s), desc))
 			continue
 		}
-		if _, ok := l.unsubs[s]; ok {
+		if _, ok := pending[s]; ok {
 			continue // redundant
 		}
-		l.unsubs[s] = reqTime
+		pending[s] = reqTime
 		// rewrite & count
-		channels[channelN] = s
-		channelN++
+		names[nameN] = s
+		nameN++
 	}
 
 	conn := l.conn
-	if conn != nil && channelN != 0 && l.expireTimer == nil {
+	if conn != nil && nameN != 0 && l.expireTimer == nil {
 		l.expireTimer = time.NewTimer(l.CommandTimeout)
 		go l.expire(l.expireTimer)
 	}
 	l.mutex.Unlock()
 
-	if conn != nil && channelN != 0 {
-		l.submit(conn, requestWithList("\r\n$11\r\nUNSUBSCRIBE", channels[:channelN]))
+	if conn != nil && nameN != 0 {
+		l.submit(conn, requestWithList(prefix, names[:nameN]))
 	}
 }
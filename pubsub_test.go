@@ -51,6 +51,7 @@ func newTestListener(t *testing.T) (*Listener, <-chan *listenerCall) {
 		CommandTimeout: testClient.CommandTimeout,
 		DialTimeout:    testClient.DialTimeout,
 		Password:       testClient.Password,
+		Username:       testClient.Username,
 	})
 
 	t.Cleanup(func() {
@@ -153,6 +154,11 @@ func TestSubscribe(t *testing.T) {
 	case <-timeout.C:
 		t.Fatal("test timeout while awaiting second call")
 	}
+
+	stats := l.Stats()
+	if stats.MessagesReceived != 2 || stats.Subscriptions != 1 || stats.BytesRead == 0 || stats.BytesWritten == 0 {
+		t.Errorf("got stats %+v, want 2 messages on 1 subscription", stats)
+	}
 }
 
 func TestUnsubscribe(t *testing.T) {
@@ -333,6 +339,7 @@ func benchmarkPubSub(b *testing.B, size, routineN int) {
 		CommandTimeout: testClient.CommandTimeout,
 		DialTimeout:    testClient.DialTimeout,
 		Password:       testClient.Password,
+		Username:       testClient.Username,
 	})
 	defer l.Close()
 
@@ -363,3 +370,350 @@ func benchmarkPubSub(b *testing.B, size, routineN int) {
 func awaitExecution() {
 	time.Sleep(100 * time.Millisecond)
 }
+
+func TestPSubscribe(t *testing.T) {
+	t.Parallel()
+
+	type patternCall struct{ pattern, channel, message string }
+	calls := make(chan patternCall, 9)
+	l := NewListener(ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			if err != nil && err != ErrClosed {
+				t.Error("Listener error:", err)
+			}
+		},
+		PatternFunc: func(pattern, channel string, message []byte) {
+			calls <- patternCall{pattern, channel, string(message)}
+		},
+		Addr: testClient.Addr,
+	})
+	defer l.Close()
+
+	prefix := randomKey("channel")
+	if err := <-l.PSUBSCRIBEAck(prefix + ".*"); err != nil {
+		t.Fatal("PSUBSCRIBE error:", err)
+	}
+
+	if n, err := testClient.PUBLISH(prefix+".a", "ping"); err != nil {
+		t.Fatal("PUBLISH error:", err)
+	} else if n != 1 {
+		t.Fatalf("PUBLISH got %d clients, want 1", n)
+	}
+	select {
+	case got := <-calls:
+		want := patternCall{prefix + ".*", prefix + ".a", "ping"}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout awaiting pattern message")
+	}
+	if n := l.Stats().PatternSubscriptions; n != 1 {
+		t.Errorf("got %d pattern subscriptions, want 1", n)
+	}
+
+	l.PUNSUBSCRIBE(prefix + ".*")
+	awaitExecution()
+	if n, err := testClient.PUBLISH(prefix+".b", "ping"); err != nil {
+		t.Error("PUBLISH error:", err)
+	} else if n != 0 {
+		t.Errorf("PUBLISH after PUNSUBSCRIBE got %d clients, want 0", n)
+	}
+	if n := l.Stats().PatternSubscriptions; n != 0 {
+		t.Errorf("got %d pattern subscriptions after PUNSUBSCRIBE, want 0", n)
+	}
+}
+
+func TestSubscribeAck(t *testing.T) {
+	t.Parallel()
+	l, calls := newTestListener(t)
+
+	channel1, channel2 := randomKey("channel"), randomKey("channel")
+	select {
+	case err := <-l.SUBSCRIBEAck(channel1, channel2, channel1):
+		if err != nil {
+			t.Fatal("SUBSCRIBE error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SUBSCRIBE confirmation timeout")
+	}
+	for _, channel := range []string{channel1, channel2} {
+		if n, err := testClient.PUBLISH(channel, "ping"); err != nil {
+			t.Error("PUBLISH error:", err)
+		} else if n != 1 {
+			t.Errorf("PUBLISH on %q got %d clients, want 1", channel, n)
+		}
+		select {
+		case c := <-calls:
+			if c.err != nil || c.channel != channel {
+				t.Errorf("got call %+v, want message on %q", *c, channel)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout awaiting message")
+		}
+	}
+
+	// confirmed already
+	select {
+	case err := <-l.SUBSCRIBEAck(channel1):
+		if err != nil {
+			t.Error("redundant SUBSCRIBE error:", err)
+		}
+	default:
+		t.Error("redundant SUBSCRIBE not confirmed immediately")
+	}
+
+	l.Close()
+	if err := <-l.SUBSCRIBEAck(randomKey("channel")); err != ErrClosed {
+		t.Errorf("SUBSCRIBE after Close got error %v, want ErrClosed", err)
+	}
+}
+
+func TestOwnedFunc(t *testing.T) {
+	t.Parallel()
+
+	payloads := make(chan *Payload, 9)
+	l := NewListener(ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			if err != nil && err != ErrClosed {
+				t.Error("Listener error:", err)
+			} else if err == nil {
+				t.Errorf("Func got message on %q; want OwnedFunc only", channel)
+			}
+		},
+		OwnedFunc: func(channel string, message *Payload) {
+			payloads <- message
+		},
+		Addr: testClient.Addr,
+	})
+	defer l.Close()
+
+	channel := randomKey("channel")
+	if err := <-l.SUBSCRIBEAck(channel); err != nil {
+		t.Fatal("SUBSCRIBE error:", err)
+	}
+	if err := <-l.PSUBSCRIBEAck(channel + "*"); err != nil {
+		t.Fatal("PSUBSCRIBE error:", err)
+	}
+	if n, err := testClient.PUBLISH(channel, "ping"); err != nil {
+		t.Fatal("PUBLISH error:", err)
+	} else if n != 2 {
+		t.Fatalf("PUBLISH got %d clients, want 2", n)
+	}
+
+	var patterns []string
+	for i := 0; i < 2; i++ {
+		select {
+		case p := <-payloads:
+			if string(p.Bytes) != "ping" {
+				t.Errorf("got payload %q, want %q", p.Bytes, "ping")
+			}
+			patterns = append(patterns, p.Pattern)
+			p.Release()
+		case <-time.After(time.Second):
+			t.Fatal("timeout awaiting payload")
+		}
+	}
+	if patterns[0]+patterns[1] != channel+"*" {
+		t.Errorf("got patterns %q, want one empty and one %q", patterns, channel+"*")
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	t.Parallel()
+
+	slow, fast := randomKey("channel"), randomKey("channel")
+	for workerIndex(slow, 4) == workerIndex(fast, 4) {
+		fast = randomKey("channel")
+	}
+
+	var mutex sync.Mutex
+	received := make(map[string][]string)
+	fastDone := make(chan struct{})
+	release := make(chan struct{})
+	l := NewListener(ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			if err != nil {
+				if err != ErrClosed {
+					t.Error("Listener error:", err)
+				}
+				return
+			}
+			if channel == slow {
+				<-release
+			}
+			mutex.Lock()
+			received[channel] = append(received[channel], string(message))
+			if channel == fast && len(received[fast]) == 3 {
+				close(fastDone)
+			}
+			mutex.Unlock()
+		},
+		Addr:    testClient.Addr,
+		Workers: 4,
+	})
+
+	if err := <-l.SUBSCRIBEAck(slow, fast); err != nil {
+		t.Fatal("SUBSCRIBE error:", err)
+	}
+	for _, message := range []string{"1", "2", "3"} {
+		for _, channel := range []string{slow, fast} {
+			if _, err := testClient.PUBLISH(channel, message); err != nil {
+				t.Fatal("PUBLISH error:", err)
+			}
+		}
+	}
+
+	select {
+	case <-fastDone:
+		break
+	case <-time.After(time.Second):
+		t.Error("fast channel stalled by slow receiver")
+	}
+	close(release)
+	l.Close() // flushes workers
+
+	for _, channel := range []string{slow, fast} {
+		if got := strings.Join(received[channel], ","); got != "1,2,3" {
+			t.Errorf("channel %q got messages %q, want 1,2,3", channel, got)
+		}
+	}
+}
+
+func TestListenerLifecycle(t *testing.T) {
+	t.Parallel()
+
+	connects := make(chan []string, 9)
+	disconnects := make(chan error, 9)
+	l := NewListener(ListenerConfig{
+		Func:      func(channel string, message []byte, err error) {},
+		Addr:      testClient.Addr,
+		Reconnect: Backoff{Initial: time.Millisecond},
+		OnConnect: func(channels, patterns []string) {
+			connects <- append(channels[:len(channels):len(channels)], patterns...)
+		},
+		OnDisconnect: func(err error) { disconnects <- err },
+	})
+
+	timeout := time.NewTimer(time.Second)
+	defer timeout.Stop()
+	select {
+	case got := <-connects:
+		if len(got) != 0 {
+			t.Errorf("first connect got resubscribes %q", got)
+		}
+	case <-timeout.C:
+		t.Fatal("timeout awaiting first connect")
+	}
+
+	channel := randomKey("channel")
+	if err := <-l.SUBSCRIBEAck(channel); err != nil {
+		t.Fatal("SUBSCRIBE error:", err)
+	}
+	if err := <-l.PSUBSCRIBEAck(channel + "*"); err != nil {
+		t.Fatal("PSUBSCRIBE error:", err)
+	}
+
+	// break connection
+	l.mutex.Lock()
+	l.conn.Close()
+	l.mutex.Unlock()
+
+	select {
+	case err := <-disconnects:
+		if err == nil || err == ErrClosed {
+			t.Errorf("disconnect got error %v, want a connection error", err)
+		}
+	case <-timeout.C:
+		t.Fatal("timeout awaiting disconnect")
+	}
+	select {
+	case got := <-connects:
+		if len(got) != 2 || got[0] != channel || got[1] != channel+"*" {
+			t.Errorf("reconnect got resubscribes %q, want %q and %q", got, channel, channel+"*")
+		}
+	case <-timeout.C:
+		t.Fatal("timeout awaiting reconnect")
+	}
+
+	l.Close()
+	select {
+	case err := <-disconnects:
+		if err != ErrClosed {
+			t.Errorf("disconnect on Close got error %v, want ErrClosed", err)
+		}
+	default:
+		t.Error("no disconnect on Close")
+	}
+}
+
+func TestListenerForceReconnect(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan error, 9)
+	connects := make(chan []string, 9)
+	disconnects := make(chan error, 9)
+	l := NewListener(ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			if err != nil && err != ErrClosed {
+				errs <- err
+			}
+		},
+		Addr:      "doesnotexist.example.com",
+		Password:  testClient.Password,
+		Username:  testClient.Username,
+		Reconnect: Backoff{Initial: time.Millisecond},
+		OnConnect: func(channels, patterns []string) {
+			connects <- channels
+		},
+		OnDisconnect: func(err error) { disconnects <- err },
+	})
+	defer l.Close()
+
+	timeout := time.NewTimer(time.Second)
+	defer timeout.Stop()
+	select {
+	case <-errs:
+		break // offline
+	case <-timeout.C:
+		t.Fatal("timeout awaiting connect error")
+	}
+	l.SetAddr(testClient.Addr)
+	select {
+	case <-connects:
+		break
+	case <-timeout.C:
+		t.Fatal("timeout awaiting connect after SetAddr")
+	}
+	for len(errs) != 0 {
+		<-errs // connect errors before SetAddr
+	}
+
+	channel := randomKey("channel")
+	if err := <-l.SUBSCRIBEAck(channel); err != nil {
+		t.Fatal("SUBSCRIBE error:", err)
+	}
+	l.ForceReconnect()
+	select {
+	case err := <-disconnects:
+		if err != errReconnect {
+			t.Errorf("disconnect got error %v, want errReconnect", err)
+		}
+	case <-timeout.C:
+		t.Fatal("timeout awaiting disconnect")
+	}
+	select {
+	case got := <-connects:
+		if len(got) != 1 || got[0] != channel {
+			t.Errorf("reconnect got resubscribes %q, want %q", got, channel)
+		}
+	case <-timeout.C:
+		t.Fatal("timeout awaiting reconnect")
+	}
+	select {
+	case err := <-errs:
+		t.Error("Listener called with error:", err)
+	default:
+		break
+	}
+}
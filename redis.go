@@ -4,6 +4,8 @@ package redis
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -11,7 +13,9 @@ import (
 	"net"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -26,6 +30,22 @@ const (
 	ElementMax = 1<<32 - 1
 )
 
+// ConnReader is the buffered reader of a connection, with the limits that
+// apply to the replies.
+type connReader struct {
+	*bufio.Reader
+	sizeMax    int64 // upper boundary for byte sizes
+	elementMax int64 // upper boundary for element counts
+}
+
+func newConnReader(rd io.Reader, bufSize int, sizeMax, elementMax int64) *connReader {
+	return &connReader{
+		Reader:     bufio.NewReaderSize(rd, bufSize),
+		sizeMax:    sizeMax,
+		elementMax: elementMax,
+	}
+}
+
 // String is a key and/or value abstraction.
 type String interface {
 	~string | ~[]byte
@@ -38,6 +58,38 @@ const conservativeMSS = 1208
 // ErrClosed signals end-of-life due a call to Close.
 var ErrClosed = errors.New("redis: connection establishment closed")
 
+// ErrTimeout signals expiry of a command deadline, either from CommandTimeout
+// or from a context. Errors match ErrTimeout with errors.Is, and so they do
+// for context.DeadlineExceeded. They implement net.Error too.
+var ErrTimeout net.Error = &timeoutError{}
+
+// TimeoutError wraps the network error of a deadline.
+type timeoutError struct {
+	cause error // optional
+}
+
+// Error honors the error interface.
+func (e *timeoutError) Error() string {
+	if e.cause == nil {
+		return "redis: command timeout"
+	}
+	return "redis: command timeout: " + e.cause.Error()
+}
+
+// Timeout implements net.Error.
+func (e *timeoutError) Timeout() bool { return true }
+
+// Temporary implements net.Error.
+func (e *timeoutError) Temporary() bool { return true }
+
+// Is honors errors.Is for ErrTimeout and context.DeadlineExceeded.
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
+}
+
+// Unwrap honors errors.Unwrap.
+func (e *timeoutError) Unwrap() error { return e.cause }
+
 // errProtocol signals invalid RESP reception.
 var errProtocol = errors.New("redis: protocol violation")
 
@@ -64,11 +116,122 @@ func (e ServerError) Prefix() string {
 	return s
 }
 
+// Transient returns whether the error kind is temporary, in which case the
+// command did not execute. These are LOADING (during startup), BUSY (during
+// script execution), TRYAGAIN (during resharding), CLUSTERDOWN and
+// MASTERDOWN.
+func (e ServerError) Transient() bool {
+	switch e.Prefix() {
+	case "LOADING", "BUSY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN":
+		return true
+	}
+	return false
+}
+
+// Message returns the text after the Prefix, if any.
+func (e ServerError) Message() string {
+	s := string(e)
+	for i, r := range s {
+		if r == ' ' {
+			return s[i+1:]
+		}
+	}
+	return ""
+}
+
+// Redirect returns the hash slot and the node address of a MOVED or an ASK
+// error. The host is empty when the cluster has no endpoint announced, which
+// means the same host as the one which replied. The return is false for any
+// other kind of error.
+func (e ServerError) Redirect() (slot int64, addr string, ok bool) {
+	switch e.Prefix() {
+	case "MOVED", "ASK":
+		break
+	default:
+		return 0, "", false
+	}
+
+	// format: MOVED 3999 127.0.0.1:6381
+	fields := strings.Fields(e.Message())
+	if len(fields) != 2 {
+		return 0, "", false
+	}
+	slot, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || slot < 0 || slot >= SlotCount {
+		return 0, "", false
+	}
+	return slot, fields[1], true
+}
+
+// Kind returns the Prefix as an ErrorKind.
+func (e ServerError) Kind() ErrorKind {
+	return ErrorKind(e.Prefix())
+}
+
+// Is honors errors.Is for ErrorKind targets.
+func (e ServerError) Is(target error) bool {
+	kind, ok := target.(ErrorKind)
+	return ok && e.Prefix() == string(kind)
+}
+
+// ErrorKind is the first word of a ServerError. Use errors.Is to match, as in
+// errors.Is(err, redis.ErrWrongType).
+type ErrorKind string
+
+// Error honors the error interface.
+func (k ErrorKind) Error() string {
+	return "redis: " + string(k) + " error kind"
+}
+
+// Common error kinds.
+const (
+	// ErrWrongType rejects an operation on a Key of another data type.
+	ErrWrongType ErrorKind = "WRONGTYPE"
+	// ErrOOM rejects a write as the maxmemory limit is reached.
+	ErrOOM ErrorKind = "OOM"
+	// ErrNoScript signals an unknown script hash with EVALSHA.
+	ErrNoScript ErrorKind = "NOSCRIPT"
+	// ErrReadOnly rejects a write on a read-only replica.
+	ErrReadOnly ErrorKind = "READONLY"
+	// ErrMoved redirects a command to another cluster node, permanently.
+	ErrMoved ErrorKind = "MOVED"
+	// ErrAsk redirects a command to another cluster node, once.
+	ErrAsk ErrorKind = "ASK"
+	// ErrLoading signals a server which is loading its dataset in memory.
+	ErrLoading ErrorKind = "LOADING"
+	// ErrBusyGroup rejects the creation of an existing consumer group.
+	ErrBusyGroup ErrorKind = "BUSYGROUP"
+	// ErrBusyKey rejects a RESTORE on an existing Key without replace.
+	ErrBusyKey ErrorKind = "BUSYKEY"
+)
+
 func isUnixAddr(s string) bool {
 	return len(s) != 0 && s[0] == '/'
 }
 
+// AddrScheme returns the scheme of an address in URL notation, or the empty
+// string otherwise.
+func addrScheme(s string) string {
+	i := strings.Index(s, "://")
+	if i <= 0 {
+		return ""
+	}
+	return s[:i]
+}
+
 func normalizeAddr(s string) string {
+	if strings.IndexByte(s, ',') >= 0 {
+		addrs := strings.Split(s, ",")
+		for i := range addrs {
+			addrs[i] = normalizeAddr(strings.TrimSpace(addrs[i]))
+		}
+		return strings.Join(addrs, ",")
+	}
+
+	if addrScheme(s) != "" {
+		return s // URL as is
+	}
+
 	if isUnixAddr(s) {
 		return filepath.Clean(s)
 	}
@@ -113,7 +276,7 @@ func ParseInt(bytes []byte) int64 {
 	return v
 }
 
-func readOK(r *bufio.Reader) error {
+func readOK(r *connReader) error {
 	line, err := readLine(r)
 	if err != nil {
 		return err
@@ -127,13 +290,60 @@ func readOK(r *bufio.Reader) error {
 			return errNull
 		}
 	}
+	if len(line) == 3 && line[0] == '_' {
+		// RESP3 null
+		return errNull
+	}
 	if len(line) > 3 && line[0] == '-' {
 		return ServerError(line[1 : len(line)-2])
 	}
 	return fmt.Errorf("%w; received %.40q for OK", errProtocol, line)
 }
 
-func readInteger(r *bufio.Reader) (int64, error) {
+// ReadTransient consumes an error reply when it is transient. Anything else
+// remains unread, including I/O errors.
+func readTransient(r *connReader) (ServerError, bool) {
+	head, err := r.Peek(1)
+	if err != nil || head[0] != '-' {
+		return "", false
+	}
+	for n := r.Buffered(); ; n++ {
+		line, err := r.Peek(n)
+		if err != nil {
+			return "", false
+		}
+		i := bytes.IndexByte(line, '\n')
+		if i < 0 {
+			continue
+		}
+		if i < 3 || line[i-1] != '\r' {
+			return "", false
+		}
+		e := ServerError(line[1 : i-1])
+		if !e.Transient() {
+			return "", false
+		}
+		r.Discard(i + 1)
+		return e, true
+	}
+}
+
+// ReadStatus reads a simple string reply, which must match want.
+func readStatus(r *connReader, want string) error {
+	line, err := readLine(r)
+	switch {
+	case err != nil:
+		return err
+	case len(line) == len(want)+3 && line[0] == '+' && string(line[1:len(line)-2]) == want:
+		return nil
+	case len(line) > 3 && line[0] == '-':
+		return ServerError(line[1 : len(line)-2])
+	default:
+		return fmt.Errorf("%w; received %.40q for %s", errProtocol, line, want)
+	}
+}
+
+func readInteger(r *connReader) (int64, error) {
 	line, err := readLine(r)
 	switch {
 	case err != nil:
@@ -142,16 +352,83 @@ func readInteger(r *bufio.Reader) (int64, error) {
 		return ParseInt(line[1 : len(line)-2]), nil
 	case len(line) > 3 && line[0] == '-':
 		return 0, ServerError(line[1 : len(line)-2])
+	case len(line) == 4 && line[0] == '#':
+		// RESP3 boolean as the RESP2 integer
+		if line[1] == 't' {
+			return 1, nil
+		}
+		return 0, nil
 	default:
 		return 0, fmt.Errorf("%w; received %.40q for integer", errProtocol, line)
 	}
 }
 
-func readBulk[T String](r *bufio.Reader) (bulk T, err error) {
-	size, err := readBulkSize(r)
+// ReadIntegerOrNull is like readInteger, yet it returns errNull on a null.
+func readIntegerOrNull(r *connReader) (int64, error) {
+	line, err := r.Peek(3)
+	if err == nil && (string(line) == "$-1" || string(line) == "_\r\n") {
+		_, err = readLine(r)
+		if err != nil {
+			return 0, err
+		}
+		return 0, errNull
+	}
+	return readInteger(r)
+}
+
+func readBulk[T String](r *connReader) (bulk T, err error) {
+	line, err := readLine(r)
 	if err != nil {
 		return bulk, err
 	}
+
+	var size int64
+	switch {
+	case len(line) > 3 && line[0] == '$':
+		size = ParseInt(line[1 : len(line)-2])
+		if size < 0 || size > r.sizeMax {
+			if size == -1 {
+				// "null bulk string"
+				return bulk, errNull
+			}
+			return bulk, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+		}
+
+	case len(line) > 3 && line[0] == '=':
+		// RESP3 verbatim string has a 3-byte format prefix plus colon
+		size = ParseInt(line[1:len(line)-2]) - 4
+		if size < 0 || size > r.sizeMax {
+			return bulk, fmt.Errorf("%w; received %.40q for verbatim string", errProtocol, line)
+		}
+		if _, err := r.Discard(4); err != nil {
+			return bulk, err
+		}
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return bulk, errNull
+
+	case len(line) > 3 && line[0] == '-':
+		return bulk, ServerError(line[1 : len(line)-2])
+
+	case len(line) > 3 && line[0] == '#':
+		// RESP3 boolean as the RESP2 integer
+		bytes := []byte{'0'}
+		if line[1] == 't' {
+			bytes[0] = '1'
+		}
+		return *(*T)(unsafe.Pointer(&bytes)), nil
+
+	case len(line) > 3 && (line[0] == '+' || line[0] == ':' || line[0] == ',' || line[0] == '('):
+		// simple string, integer, RESP3 double or RESP3 big number
+		bytes := make([]byte, len(line)-3)
+		copy(bytes, line[1:])
+		return *(*T)(unsafe.Pointer(&bytes)), nil
+
+	default:
+		return bulk, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+	}
+
 	bytes := make([]byte, size)
 	_, err = io.ReadFull(r, bytes)
 	if err == nil {
@@ -160,7 +437,69 @@ func readBulk[T String](r *bufio.Reader) (bulk T, err error) {
 	return *(*T)(unsafe.Pointer(&bytes)), err
 }
 
-func readArray[T String](r *bufio.Reader) ([]T, error) {
+// ReadBulkInto is like readBulk, yet it copies the string into buf. Any string
+// larger than buf is discarded with io.ErrShortBuffer and the size required.
+func readBulkInto(r *connReader, buf []byte) (int, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	switch {
+	case len(line) > 3 && line[0] == '$':
+		size = ParseInt(line[1 : len(line)-2])
+		if size < 0 || size > r.sizeMax {
+			if size == -1 {
+				// "null bulk string"
+				return 0, errNull
+			}
+			return 0, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+		}
+
+	case len(line) > 3 && line[0] == '=':
+		// RESP3 verbatim string has a 3-byte format prefix plus colon
+		size = ParseInt(line[1:len(line)-2]) - 4
+		if size < 0 || size > r.sizeMax {
+			return 0, fmt.Errorf("%w; received %.40q for verbatim string", errProtocol, line)
+		}
+		if _, err := r.Discard(4); err != nil {
+			return 0, err
+		}
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return 0, errNull
+
+	case len(line) > 3 && line[0] == '-':
+		return 0, ServerError(line[1 : len(line)-2])
+
+	case len(line) > 3 && (line[0] == '+' || line[0] == ':' || line[0] == ',' || line[0] == '('):
+		// simple string, integer, RESP3 double or RESP3 big number
+		if len(line)-3 > len(buf) {
+			return len(line) - 3, io.ErrShortBuffer
+		}
+		return copy(buf, line[1:len(line)-2]), nil
+
+	default:
+		return 0, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+	}
+
+	if size > int64(len(buf)) {
+		_, err = r.Discard(int(size) + 2) // including CRLF
+		if err != nil {
+			return 0, err
+		}
+		return int(size), io.ErrShortBuffer
+	}
+	_, err = io.ReadFull(r, buf[:size])
+	if err == nil {
+		_, err = r.Discard(2) // skip CRLF
+	}
+	return int(size), err
+}
+
+func readArray[T String](r *connReader) ([]T, error) {
 	l, err := readArrayLen(r)
 	if l == 0 {
 		return nil, err
@@ -178,38 +517,137 @@ func readArray[T String](r *bufio.Reader) ([]T, error) {
 	return array, nil
 }
 
-func readBulkSize(r *bufio.Reader) (int64, error) {
-	line, err := readLine(r)
-	switch {
-	case err != nil:
-		return 0, err
+// ReadArrayOk is like readArray, yet with presence for each element.
+func readArrayOk[T String](r *connReader) ([]T, []bool, error) {
+	l, err := readArrayLen(r)
+	if l == 0 {
+		return nil, nil, err
+	}
+	array := make([]T, l)
+	present := make([]bool, l)
+	for i := range array {
+		array[i], err = readBulk[T](r)
+		switch err {
+		case nil:
+			present[i] = true
+		case errNull:
+			break // absent
+		default:
+			return nil, nil, err
+		}
+	}
+	return array, present, nil
+}
 
-	case len(line) > 3 && line[0] == '$':
-		size := ParseInt(line[1 : len(line)-2])
-		if size >= 0 && size <= SizeMax {
-			return size, nil
+// ReadScored reads members with their score, either as a RESP2 array with
+// member–score pairs, or as a RESP3 array with a 2-element array per member.
+func readScored[T String](r *connReader) ([]T, []float64, error) {
+	l, err := readArrayLen(r)
+	if l == 0 {
+		return nil, nil, err
+	}
+	head, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	nested := head[0] == '*'
+	if !nested {
+		if l&1 != 0 {
+			return nil, nil, fmt.Errorf("%w; odd number of elements (%d) for member–score pairs", errProtocol, l)
 		}
-		if size == -1 {
-			// "null bulk string"
-			return 0, errNull
+		l /= 2
+	}
+
+	members := make([]T, l)
+	scores := make([]float64, l)
+	for i := range members {
+		if nested {
+			n, err := readArrayLen(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			if n != 2 {
+				return nil, nil, fmt.Errorf("%w; %d elements for member–score pair", errProtocol, n)
+			}
+		}
+		members[i], err = readBulk[T](r)
+		if err != nil {
+			return nil, nil, err
+		}
+		score, err := readBulk[string](r)
+		if err != nil {
+			return nil, nil, err
+		}
+		scores[i], err = strconv.ParseFloat(score, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w; score %q", errProtocol, score)
 		}
+	}
+	return members, scores, nil
+}
 
-	case len(line) > 3 && line[0] == '-':
-		return 0, ServerError(line[1 : len(line)-2])
+// ReadScan reads the cursor with the elements of a SCAN reply.
+func readScan[T String](r *connReader) (cursor uint64, elements []T, err error) {
+	l, err := readArrayLen(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if l != 2 {
+		return 0, nil, fmt.Errorf("%w; %d elements for cursor and keys", errProtocol, l)
+	}
+	s, err := readBulk[string](r)
+	if err != nil {
+		return 0, nil, err
+	}
+	cursor, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w; cursor %q", errProtocol, s)
 	}
+	elements, err = readArray[T](r)
+	return cursor, elements, err
+}
 
-	return 0, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+// ReadMap reads both the RESP3 map and the RESP2 array with key–value pairs.
+func readMap[Key, Value String](r *connReader) ([]Key, []Value, error) {
+	l, err := readArrayLen(r)
+	if l == 0 {
+		return nil, nil, err
+	}
+	if l&1 != 0 {
+		return nil, nil, fmt.Errorf("%w; odd number of elements (%d) for map", errProtocol, l)
+	}
+	keys := make([]Key, l/2)
+	values := make([]Value, l/2)
+	for i := range keys {
+		keys[i], err = readBulk[Key](r)
+		switch err {
+		case nil, errNull:
+			break // OK
+		default:
+			return nil, nil, err
+		}
+		values[i], err = readBulk[Value](r)
+		switch err {
+		case nil, errNull:
+			break // OK
+		default:
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
 }
 
-func readArrayLen(r *bufio.Reader) (int64, error) {
+// ReadArrayLen accepts RESP3 sets and maps too. The length of maps is the
+// number of elements, i.e., twice the number of entries.
+func readArrayLen(r *connReader) (int64, error) {
 	line, err := readLine(r)
 	switch {
 	case err != nil:
 		return 0, err
 
-	case len(line) > 3 && line[0] == '*':
+	case len(line) > 3 && (line[0] == '*' || line[0] == '~'):
 		l := ParseInt(line[1 : len(line)-2])
-		if l >= 0 && l <= ElementMax {
+		if l >= 0 && l <= r.elementMax {
 			return l, nil
 		}
 		if l == -1 {
@@ -217,6 +655,16 @@ func readArrayLen(r *bufio.Reader) (int64, error) {
 			return 0, errNull
 		}
 
+	case len(line) > 3 && line[0] == '%':
+		l := ParseInt(line[1 : len(line)-2])
+		if l >= 0 && l <= r.elementMax {
+			return 2 * l, nil
+		}
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return 0, errNull
+
 	case len(line) > 3 && line[0] == '-':
 		return 0, ServerError(line[1 : len(line)-2])
 	}
@@ -224,7 +672,121 @@ func readArrayLen(r *bufio.Reader) (int64, error) {
 	return 0, fmt.Errorf("%w; received %.40q for array", errProtocol, line)
 }
 
-func readLine(r *bufio.Reader) (line []byte, err error) {
+// ReadPush reads a RESP3 push message. Nested aggregates are flattened into
+// args, with nil for null.
+func readPush(r *connReader) (kind string, args [][]byte, err error) {
+	line, err := readLine(r)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(line) < 4 || line[0] != '>' {
+		return "", nil, fmt.Errorf("%w; received %.40q for push", errProtocol, line)
+	}
+	n := ParseInt(line[1 : len(line)-2])
+	if n < 1 || n > r.elementMax {
+		return "", nil, fmt.Errorf("%w; received %.40q for push", errProtocol, line)
+	}
+
+	kind, err = readBulk[string](r)
+	if err != nil {
+		return "", nil, err
+	}
+	for ; n > 1; n-- {
+		args, err = appendFlatReply(args, r)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return kind, args, nil
+}
+
+// AppendFlatReply follows dst up with the elements of a reply. Aggregates are
+// walked recursively.
+func appendFlatReply(dst [][]byte, r *connReader) ([][]byte, error) {
+	head, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	switch head[0] {
+	case '*', '~', '%':
+		n, err := readArrayLen(r)
+		if err != nil && err != errNull {
+			return nil, err
+		}
+		for ; n > 0; n-- {
+			dst, err = appendFlatReply(dst, r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+
+	default:
+		bytes, err := readBulk[[]byte](r)
+		if err != nil && err != errNull {
+			return nil, err
+		}
+		return append(dst, bytes), nil
+	}
+}
+
+// DiscardReply consumes a reply of any type, including all of its nested
+// elements. Error replies only return as such on the top level.
+func discardReply(r *connReader) error {
+	line, err := readLine(r)
+	if err != nil {
+		return err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return fmt.Errorf("%w; received %.40q", errProtocol, line)
+	}
+
+	switch line[0] {
+	case '+', ':', ',', '(', '#', '_':
+		return nil // single line
+
+	case '-':
+		return ServerError(line[1 : len(line)-2])
+
+	case '$', '=', '!':
+		size := ParseInt(line[1 : len(line)-2])
+		if size == -1 {
+			return nil // "null bulk string"
+		}
+		if size < 0 || size > r.sizeMax {
+			break // invalid
+		}
+		_, err := r.Discard(int(size) + 2) // including CRLF
+		return err
+
+	case '*', '~', '>', '%', '|':
+		n := ParseInt(line[1 : len(line)-2])
+		if n == -1 && line[0] == '*' {
+			return nil // "null array"
+		}
+		if n < 0 || n > r.elementMax {
+			break // invalid
+		}
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2 // key–value pairs
+		}
+		for ; n > 0; n-- {
+			err := discardReply(r)
+			if _, ok := err.(ServerError); err != nil && !ok {
+				return err
+			}
+		}
+		if line[0] == '|' {
+			// attributes precede the actual reply
+			return discardReply(r)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w; received %.40q", errProtocol, line)
+}
+
+func readLine(r *connReader) (line []byte, err error) {
 	line, err = r.ReadSlice('\n')
 	if err == bufio.ErrBufferFull {
 		err = fmt.Errorf("%w; line %.40q… exceeds %d bytes", errProtocol, line, r.Size())
@@ -232,20 +794,101 @@ func readLine(r *bufio.Reader) (line []byte, err error) {
 	return
 }
 
+// CountCommands returns the number of commands in a request buffer.
+func countCommands(buf []byte) int {
+	var n int
+	for ; len(buf) != 0; n++ {
+		i := bytes.IndexByte(buf, '\n')
+		argN := ParseInt(buf[1 : i-1])
+		buf = buf[i+1:]
+		for ; argN > 0; argN-- {
+			i = bytes.IndexByte(buf, '\n')
+			size := int(ParseInt(buf[1 : i-1]))
+			if i+1+size+2 > len(buf) {
+				return n + 1 // streamed payload
+			}
+			buf = buf[i+1+size+2:]
+		}
+	}
+	return n
+}
+
+// CheckArgSizes returns an error when any argument in a request buffer exceeds
+// max bytes.
+func checkArgSizes(buf []byte, max int64) error {
+	for len(buf) != 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := buf[:i+1]
+		buf = buf[i+1:]
+		if line[0] != '$' || len(line) < 4 {
+			continue // array header
+		}
+		size := ParseInt(line[1 : len(line)-2])
+		if size > max {
+			return fmt.Errorf("redis: %d-byte argument exceeds SizeMax", size)
+		}
+		if size+2 > int64(len(buf)) {
+			break // streamed body
+		}
+		buf = buf[size+2:]
+	}
+	return nil
+}
+
+// CommandName returns the first word of the (first) command in a request
+// buffer, with the number of arguments that follow.
+func commandName(buf []byte) (name string, argCount int) {
+	i := bytes.IndexByte(buf, '\n')
+	argCount = int(ParseInt(buf[1:i-1])) - 1
+	buf = buf[i+1:]
+	i = bytes.IndexByte(buf, '\n')
+	size := int(ParseInt(buf[1 : i-1]))
+	return string(buf[i+1 : i+1+size]), argCount
+}
+
 type request struct {
 	buf     []byte
-	receive chan *bufio.Reader
+	receive chan *connReader
+
+	// Retry marks idempotent commands without side effects.
+	retry bool
+
+	// Block extends the command timeout for blocking commands. Negative
+	// values block indefinitely.
+	block time.Duration
+
+	// Prefixed marks requests with the Key prefix of WithPrefix applied
+	// already.
+	prefixed bool
+
+	// Body streams the payload of the last argument, as announced at the
+	// end of buf, when not nil. The CRLF is written after bodySize bytes.
+	body     io.Reader
+	bodySize int64
 }
 
 func (r *request) free() {
+	r.retry = false
+	r.block = 0
+	r.prefixed = false
+	r.body = nil
 	requestPool.Put(r)
 }
 
+// Idempotent marks the request for retry on connection loss.
+func (r *request) idempotent() *request {
+	r.retry = true
+	return r
+}
+
 var requestPool = sync.Pool{
 	New: func() interface{} {
 		return &request{
 			buf:     make([]byte, 256),
-			receive: make(chan *bufio.Reader),
+			receive: make(chan *connReader),
 		}
 	},
 }
@@ -291,6 +934,14 @@ func requestWithDecimal(prefix string, n int64) *request {
 	return r
 }
 
+func requestWith2Decimals(prefix string, n1, n2 int64) *request {
+	r := requestFix(prefix)
+	r.addSizeCRLFDecimal(n1)
+	r.buf = append(r.buf, '\r', '\n', '$')
+	r.addDecimalToDollar(n2)
+	return r
+}
+
 func requestWithStringAndDecimal[T String](prefix string, s T, n int64) *request {
 	r := requestFix(prefix)
 	r.buf = appendStringAndDollarToDollar(r.buf, s)
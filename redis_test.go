@@ -1,11 +1,20 @@
 package redis
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
+// TestReader returns a reader on s with the package limits.
+func testReader(s string) *connReader {
+	return newConnReader(strings.NewReader(s), 4096, SizeMax, ElementMax)
+}
+
 func TestParseInt(t *testing.T) {
 	for _, v := range []int64{0, -1, 1, math.MinInt64, math.MaxInt64} {
 		got := ParseInt([]byte(strconv.FormatInt(v, 10)))
@@ -18,6 +27,41 @@ func TestParseInt(t *testing.T) {
 	}
 }
 
+func TestReaderLimits(t *testing.T) {
+	for _, reply := range []string{"$4\r\nabcd\r\n", "=8\r\ntxt:abcd\r\n", "*3\r\n:1\r\n:2\r\n:3\r\n", "%3\r\n"} {
+		r := newConnReader(strings.NewReader(reply), 4096, 3, 2)
+		if err := discardReply(r); !errors.Is(err, errProtocol) {
+			t.Errorf("discard %q got error %v, want %v", reply, err, errProtocol)
+		}
+		r = newConnReader(strings.NewReader(reply), 4096, 3, 2)
+		if reply[0] == '*' || reply[0] == '%' {
+			_, err := readArray[string](r)
+			if !errors.Is(err, errProtocol) {
+				t.Errorf("read array %q got error %v, want %v", reply, err, errProtocol)
+			}
+		} else {
+			_, err := readBulk[string](r)
+			if !errors.Is(err, errProtocol) {
+				t.Errorf("read bulk %q got error %v, want %v", reply, err, errProtocol)
+			}
+		}
+	}
+}
+
+func TestCheckArgSizes(t *testing.T) {
+	const req = "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$5\r\nvalue\r\n"
+	if err := checkArgSizes([]byte(req), 5); err != nil {
+		t.Errorf("got error %v for 5-byte maximum", err)
+	}
+	if err := checkArgSizes([]byte(req), 4); err == nil {
+		t.Error("got no error for 4-byte maximum")
+	}
+	// streamed body announced at the end
+	if err := checkArgSizes([]byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$9\r\n"), 4); err == nil {
+		t.Error("got no error on body size for 4-byte maximum")
+	}
+}
+
 func TestNormalizeAddr(t *testing.T) {
 	golden := []struct{ Addr, Normal string }{
 		{"", "localhost:6379"},
@@ -26,6 +70,9 @@ func TestNormalizeAddr(t *testing.T) {
 		{"test.host:", "test.host:6379"},
 		{":99", "localhost:99"},
 		{"/var/redis/../run/redis.sock", "/var/run/redis.sock"},
+		{"test.host,:99, /var/run/redis.sock", "test.host:6379,localhost:99,/var/run/redis.sock"},
+		{"wss://gw.example.com/redis", "wss://gw.example.com/redis"},
+		{"ws://gw1/redis, wss://gw2:8443/redis", "ws://gw1/redis,wss://gw2:8443/redis"},
 	}
 	for _, gold := range golden {
 		if got := normalizeAddr(gold.Addr); got != gold.Normal {
@@ -33,3 +80,206 @@ func TestNormalizeAddr(t *testing.T) {
 		}
 	}
 }
+
+func TestCountCommands(t *testing.T) {
+	req := requestWith2Decimals("*3\r\n$6\r\nSWAPDB\r\n$", 1, 2)
+	defer req.free()
+	req.buf = append(req.buf, "*1\r\n$5\r\nRESET\r\n*2\r\n$3\r\nGET\r\n$4\r\n\n\r\n\r\r\n"...)
+	if got := countCommands(req.buf); got != 3 {
+		t.Errorf("got %d commands in %q, want 3", got, req.buf)
+	}
+	if name, argCount := commandName(req.buf); name != "SWAPDB" || argCount != 2 {
+		t.Errorf("got command name %q with %d arguments, want SWAPDB with 2", name, argCount)
+	}
+}
+
+func TestReadTransient(t *testing.T) {
+	r := testReader("-BUSY Redis is busy running a script\r\n-ERR unknown command\r\n:1\r\n")
+	if e, ok := readTransient(r); !ok {
+		t.Error("BUSY not transient")
+	} else if e.Prefix() != "BUSY" {
+		t.Errorf("got error %q, want BUSY", e)
+	}
+	if _, ok := readTransient(r); ok {
+		t.Error("ERR transient")
+	}
+	if err := readOK(r); err != ServerError("ERR unknown command") {
+		t.Errorf("got error %v after non-transient, want ERR unknown command", err)
+	}
+	if _, ok := readTransient(r); ok {
+		t.Error("integer transient")
+	}
+	if n, err := readInteger(r); err != nil || n != 1 {
+		t.Errorf("got integer %d, error %v, want 1", n, err)
+	}
+}
+
+func TestServerErrorParts(t *testing.T) {
+	tests := []struct {
+		err             ServerError
+		prefix, message string
+		slot            int64
+		addr            string
+		redirect        bool
+	}{
+		{"ERR", "ERR", "", 0, "", false},
+		{"ERR unknown command", "ERR", "unknown command", 0, "", false},
+		{"MOVED 3999 127.0.0.1:6381", "MOVED", "3999 127.0.0.1:6381", 3999, "127.0.0.1:6381", true},
+		{"ASK 0 [::1]:7000", "ASK", "0 [::1]:7000", 0, "[::1]:7000", true},
+		{"MOVED 16383 :6380", "MOVED", "16383 :6380", 16383, ":6380", true},
+		{"MOVED 16384 127.0.0.1:6381", "MOVED", "16384 127.0.0.1:6381", 0, "", false},
+		{"MOVED 3999", "MOVED", "3999", 0, "", false},
+		{"ERR 3999 127.0.0.1:6381", "ERR", "3999 127.0.0.1:6381", 0, "", false},
+	}
+	for _, test := range tests {
+		if got := test.err.Prefix(); got != test.prefix {
+			t.Errorf("%q got prefix %q, want %q", test.err, got, test.prefix)
+		}
+		if got := test.err.Message(); got != test.message {
+			t.Errorf("%q got message %q, want %q", test.err, got, test.message)
+		}
+		slot, addr, ok := test.err.Redirect()
+		if slot != test.slot || addr != test.addr || ok != test.redirect {
+			t.Errorf("%q got redirect %d, %q, %t, want %d, %q, %t", test.err, slot, addr, ok, test.slot, test.addr, test.redirect)
+		}
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err  ServerError
+		kind ErrorKind
+	}{
+		{"WRONGTYPE Operation against a key holding the wrong kind of value", ErrWrongType},
+		{"OOM command not allowed when used memory > 'maxmemory'.", ErrOOM},
+		{"NOSCRIPT No matching script. Please use EVAL.", ErrNoScript},
+		{"READONLY You can't write against a read only replica.", ErrReadOnly},
+		{"MOVED 3999 127.0.0.1:6381", ErrMoved},
+		{"ASK 3999 127.0.0.1:6381", ErrAsk},
+		{"LOADING Redis is loading the dataset in memory", ErrLoading},
+		{"BUSYGROUP Consumer Group name already exists", ErrBusyGroup},
+		{"BUSYKEY Target key name already exists.", ErrBusyKey},
+	}
+	for _, test := range tests {
+		var err error = fmt.Errorf("wrapped: %w", test.err)
+		if !errors.Is(err, test.kind) {
+			t.Errorf("%q does not match %q", test.err, test.kind)
+		}
+		if got := test.err.Kind(); got != test.kind {
+			t.Errorf("%q got kind %q, want %q", test.err, got, test.kind)
+		}
+		for _, other := range tests {
+			if other.kind != test.kind && errors.Is(err, other.kind) {
+				t.Errorf("%q matches %q", test.err, other.kind)
+			}
+		}
+	}
+	if errors.Is(ServerError("WRONGTYPEX"), ErrWrongType) {
+		t.Error("WRONGTYPEX matches WRONGTYPE")
+	}
+}
+
+func TestReadRESP3(t *testing.T) {
+	golden := []struct{ Reply, Bulk string }{
+		{"$3\r\nabc\r\n", "abc"},
+		{"=8\r\ntxt:abcd\r\n", "abcd"},
+		{"+OK\r\n", "OK"},
+		{":-42\r\n", "-42"},
+		{",3.14\r\n", "3.14"},
+		{",inf\r\n", "inf"},
+		{"(3492890328409238509324850943850943825024385\r\n", "3492890328409238509324850943850943825024385"},
+		{"#t\r\n", "1"},
+		{"#f\r\n", "0"},
+	}
+	for _, gold := range golden {
+		r := testReader(gold.Reply)
+		got, err := readBulk[string](r)
+		if err != nil {
+			t.Errorf("%q got error: %s", gold.Reply, err)
+		} else if got != gold.Bulk {
+			t.Errorf("%q got %q, want %q", gold.Reply, got, gold.Bulk)
+		}
+		if n := r.Buffered(); n != 0 {
+			t.Errorf("%q left %d bytes unread", gold.Reply, n)
+		}
+	}
+
+	for _, reply := range []string{"_\r\n", "$-1\r\n"} {
+		_, err := readBulk[string](testReader(reply))
+		if err != errNull {
+			t.Errorf("%q got error %v, want errNull", reply, err)
+		}
+	}
+
+	const arrayReply = "*3\r\n$0\r\n\r\n_\r\n$-1\r\n"
+	array, present, err := readArrayOk[string](testReader(arrayReply))
+	if err != nil {
+		t.Errorf("%q got error: %s", arrayReply, err)
+	} else if want := []bool{true, false, false}; !reflect.DeepEqual(present, want) || len(array) != 3 {
+		t.Errorf("%q got %q, %t, want 3 empty strings, %t", arrayReply, array, present, want)
+	}
+
+	for _, reply := range []string{
+		"*4\r\n$1\r\na\r\n$3\r\n1.5\r\n$1\r\nb\r\n$4\r\n-inf\r\n",
+		"*2\r\n*2\r\n$1\r\na\r\n,1.5\r\n*2\r\n$1\r\nb\r\n,-inf\r\n",
+	} {
+		members, scores, err := readScored[string](testReader(reply))
+		if err != nil {
+			t.Errorf("%q got error: %s", reply, err)
+		} else if !reflect.DeepEqual(members, []string{"a", "b"}) || len(scores) != 2 || scores[0] != 1.5 || !math.IsInf(scores[1], -1) {
+			t.Errorf("%q got %q, %g, want [a b], [1.5 -Inf]", reply, members, scores)
+		}
+	}
+
+	const scanReply = "*2\r\n$20\r\n18446744073709551615\r\n*2\r\n$1\r\na\r\n+b\r\n"
+	cursor, elements, err := readScan[string](testReader(scanReply))
+	if err != nil {
+		t.Errorf("%q got error: %s", scanReply, err)
+	} else if cursor != math.MaxUint64 || !reflect.DeepEqual(elements, []string{"a", "b"}) {
+		t.Errorf("%q got cursor %d with %q, want %d with [a b]", scanReply, cursor, elements, uint64(math.MaxUint64))
+	}
+
+	const mapReply = "%2\r\n$1\r\na\r\n:1\r\n+b\r\n_\r\n"
+	keys, values, err := readMap[string, []byte](testReader(mapReply))
+	if err != nil {
+		t.Errorf("%q got error: %s", mapReply, err)
+	} else if want := []string{"a", "b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("%q got keys %q, want %q", mapReply, keys, want)
+	} else if len(values) != 2 || string(values[0]) != "1" || values[1] != nil {
+		t.Errorf("%q got values %q, want [\"1\" nil]", mapReply, values)
+	}
+
+	// HELLO reply with nested types, followed by a simple string
+	const helloReply = "%3\r\n$6\r\nserver\r\n$5\r\nredis\r\n$5\r\nproto\r\n:3\r\n$7\r\nmodules\r\n*1\r\n%1\r\n$4\r\nname\r\n=8\r\ntxt:json\r\n+OK\r\n"
+	r := testReader(helloReply)
+	if err := discardReply(r); err != nil {
+		t.Errorf("discard %q got error: %s", helloReply, err)
+	} else if err := readOK(r); err != nil {
+		t.Errorf("OK after discard of %q got error: %s", helloReply, err)
+	}
+}
+
+func TestReadPush(t *testing.T) {
+	const frames = ">2\r\n$10\r\ninvalidate\r\n*2\r\n$3\r\nfoo\r\n$3\r\nbar\r\n" +
+		">2\r\n$10\r\ninvalidate\r\n_\r\n" +
+		">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$5\r\nhello\r\n"
+	r := testReader(frames)
+
+	golden := []struct {
+		Kind string
+		Args [][]byte
+	}{
+		{"invalidate", [][]byte{[]byte("foo"), []byte("bar")}},
+		{"invalidate", [][]byte{nil}},
+		{"message", [][]byte{[]byte("ch"), []byte("hello")}},
+	}
+	for _, gold := range golden {
+		kind, args, err := readPush(r)
+		if err != nil {
+			t.Fatal("read error:", err)
+		}
+		if kind != gold.Kind || !reflect.DeepEqual(args, gold.Args) {
+			t.Errorf("got %q %q, want %q %q", kind, args, gold.Kind, gold.Args)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package redisprom provides Prometheus metrics for Redis clients and listeners.
+package redisprom
+
+import (
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ClientStats is implemented by each redis.Client, regardless of its type
+// parameters.
+type ClientStats interface {
+	Stats() redis.Stats
+}
+
+type clientCollector struct {
+	client ClientStats
+
+	commands, written, read, reconnects *prometheus.Desc
+	pending, connectErrTime, latency    *prometheus.Desc
+}
+
+// NewClientCollector returns a collector over the Stats of a Client. Labels are
+// applied to each metric, e.g., to distinguish multiple clients.
+func NewClientCollector(c ClientStats, labels prometheus.Labels) prometheus.Collector {
+	return &clientCollector{
+		client: c,
+		commands: prometheus.NewDesc("redis_client_commands_total",
+			"Number of command requests written.", nil, labels),
+		written: prometheus.NewDesc("redis_client_written_bytes_total",
+			"Number of bytes sent to the network.", nil, labels),
+		read: prometheus.NewDesc("redis_client_read_bytes_total",
+			"Number of bytes received from the network.", nil, labels),
+		reconnects: prometheus.NewDesc("redis_client_reconnects_total",
+			"Number of connects after the first one.", nil, labels),
+		pending: prometheus.NewDesc("redis_client_pending_commands",
+			"Number of commands awaiting their turn in the pipeline.", nil, labels),
+		connectErrTime: prometheus.NewDesc("redis_client_last_connect_error_timestamp_seconds",
+			"Time of the most recent connect failure, if any.", nil, labels),
+		latency: prometheus.NewDesc("redis_client_command_duration_seconds",
+			"Response time of commands, including any wait in the pipeline.", nil, labels),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *clientCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.commands
+	ch <- c.written
+	ch <- c.read
+	ch <- c.reconnects
+	ch <- c.pending
+	ch <- c.connectErrTime
+	ch <- c.latency
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *clientCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.Stats()
+	ch <- prometheus.MustNewConstMetric(c.commands, prometheus.CounterValue, float64(stats.CommandsSent))
+	ch <- prometheus.MustNewConstMetric(c.written, prometheus.CounterValue, float64(stats.BytesWritten))
+	ch <- prometheus.MustNewConstMetric(c.read, prometheus.CounterValue, float64(stats.BytesRead))
+	ch <- prometheus.MustNewConstMetric(c.reconnects, prometheus.CounterValue, float64(stats.Reconnects))
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(stats.Pending))
+	if !stats.LastConnectErrTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.connectErrTime, prometheus.GaugeValue,
+			float64(stats.LastConnectErrTime.UnixNano())/1e9)
+	}
+
+	// convert to cumulative counts
+	buckets := make(map[float64]uint64, len(redis.LatencyBuckets))
+	var count uint64
+	for i, bound := range redis.LatencyBuckets {
+		count += uint64(stats.LatencyCounts[i])
+		buckets[bound.Seconds()] = count
+	}
+	count += uint64(stats.LatencyCounts[len(redis.LatencyBuckets)])
+	ch <- prometheus.MustNewConstHistogram(c.latency, count, stats.LatencySum.Seconds(), buckets)
+}
+
+type listenerCollector struct {
+	listener *redis.Listener
+
+	messages, written, read, reconnects *prometheus.Desc
+	subscriptions, connectErrTime       *prometheus.Desc
+}
+
+// NewListenerCollector returns a collector over the Stats of a Listener. Labels
+// are applied to each metric, e.g., to distinguish multiple listeners.
+func NewListenerCollector(l *redis.Listener, labels prometheus.Labels) prometheus.Collector {
+	return &listenerCollector{
+		listener: l,
+		messages: prometheus.NewDesc("redis_listener_messages_total",
+			"Number of messages received.", nil, labels),
+		written: prometheus.NewDesc("redis_listener_written_bytes_total",
+			"Number of bytes sent to the network.", nil, labels),
+		read: prometheus.NewDesc("redis_listener_read_bytes_total",
+			"Number of bytes received from the network.", nil, labels),
+		reconnects: prometheus.NewDesc("redis_listener_reconnects_total",
+			"Number of connects after the first one.", nil, labels),
+		subscriptions: prometheus.NewDesc("redis_listener_subscriptions",
+			"Number of channels subscribed, including any pending confirmation.", nil, labels),
+		connectErrTime: prometheus.NewDesc("redis_listener_last_connect_error_timestamp_seconds",
+			"Time of the most recent connect failure, if any.", nil, labels),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *listenerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.messages
+	ch <- c.written
+	ch <- c.read
+	ch <- c.reconnects
+	ch <- c.subscriptions
+	ch <- c.connectErrTime
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *listenerCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.listener.Stats()
+	ch <- prometheus.MustNewConstMetric(c.messages, prometheus.CounterValue, float64(stats.MessagesReceived))
+	ch <- prometheus.MustNewConstMetric(c.written, prometheus.CounterValue, float64(stats.BytesWritten))
+	ch <- prometheus.MustNewConstMetric(c.read, prometheus.CounterValue, float64(stats.BytesRead))
+	ch <- prometheus.MustNewConstMetric(c.reconnects, prometheus.CounterValue, float64(stats.Reconnects))
+	ch <- prometheus.MustNewConstMetric(c.subscriptions, prometheus.GaugeValue, float64(stats.Subscriptions))
+	if !stats.LastConnectErrTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.connectErrTime, prometheus.GaugeValue,
+			float64(stats.LastConnectErrTime.UnixNano())/1e9)
+	}
+}
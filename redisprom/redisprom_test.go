@@ -0,0 +1,48 @@
+package redisprom
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectors(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	client := redis.NewDefaultClient[string, string](addr)
+	defer client.Close()
+	client.GET("arbitrary")
+
+	listener := redis.NewListener(redis.ListenerConfig{
+		Func: func(channel string, message []byte, err error) {},
+		Addr: addr,
+	})
+	defer listener.Close()
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewClientCollector(client, prometheus.Labels{"name": "test"}))
+	reg.MustRegister(NewListenerCollector(listener, nil))
+
+	const want = `
+# HELP redis_client_commands_total Number of command requests written.
+# TYPE redis_client_commands_total counter
+redis_client_commands_total{name="test"} 1
+# HELP redis_listener_messages_total Number of messages received.
+# TYPE redis_listener_messages_total counter
+redis_listener_messages_total 0
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"redis_client_commands_total", "redis_listener_messages_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n, err := testutil.GatherAndCount(reg, "redis_client_command_duration_seconds"); err != nil {
+		t.Error(err)
+	} else if n != 1 {
+		t.Errorf("got %d latency histograms, want 1", n)
+	}
+}
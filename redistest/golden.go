@@ -0,0 +1,43 @@
+package redistest
+
+import "testing"
+
+// Command returns the wire encoding of a command, as sent by clients, e.g.,
+// Command("GET", "k") gives "*2\r\n$3\r\nGET\r\n$1\r\nk\r\n".
+func Command(args ...string) string {
+	elements := make([]string, len(args))
+	for i, arg := range args {
+		elements[i] = Bulk(arg)
+	}
+	return Array(elements...)
+}
+
+// AssertFrames reports any deviation of got from the golden frames, byte for
+// byte. Errors include the index of the frame, and the offset of the first
+// difference within.
+func AssertFrames(t testing.TB, got []string, golden ...string) {
+	t.Helper()
+
+	for i := 0; i < len(got) || i < len(golden); i++ {
+		switch {
+		case i >= len(golden):
+			t.Errorf("frame %d: got %q, want none", i, got[i])
+		case i >= len(got):
+			t.Errorf("frame %d: got none, want %q", i, golden[i])
+		case got[i] != golden[i]:
+			offset := 0
+			for offset < len(got[i]) && offset < len(golden[i]) && got[i][offset] == golden[i][offset] {
+				offset++
+			}
+			t.Errorf("frame %d: got %q, want %q; first difference at byte %d",
+				i, got[i], golden[i], offset)
+		}
+	}
+}
+
+// AssertCommands reports any deviation of the commands received by s from the
+// golden frames, byte for byte.
+func AssertCommands(t testing.TB, s *Server, golden ...string) {
+	t.Helper()
+	AssertFrames(t, s.ReceivedFrames(), golden...)
+}
@@ -0,0 +1,288 @@
+package redistest
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pascaldekloe/redis/v2"
+)
+
+// Listener is a redis.Subscriber without a network connection. Tests inject
+// messages, errors and reconnects, which are passed to the callbacks of the
+// configuration synchronously, i.e., before return. Callbacks must not inject
+// themselves. Subscriptions confirm immediately.
+//
+// Multiple goroutines may invoke methods on a Listener simultaneously.
+type Listener struct {
+	config redis.ListenerConfig
+
+	mutex          sync.Mutex
+	subs, psubs    map[string]struct{}
+	closed         bool
+	callbackSerial sync.Mutex // sequential callbacks
+}
+
+var _ redis.Subscriber = (*Listener)(nil)
+
+// NewListener returns a Listener with the callbacks from config. The other
+// configuration attributes are ignored. The OnConnect callback, if any, is
+// called before return.
+func NewListener(config redis.ListenerConfig) *Listener {
+	if config.Func == nil {
+		panic("redistest: missing callback function")
+	}
+	l := &Listener{
+		config: config,
+		subs:   make(map[string]struct{}),
+		psubs:  make(map[string]struct{}),
+	}
+	if config.OnConnect != nil {
+		config.OnConnect(nil, nil)
+	}
+	return l
+}
+
+// SUBSCRIBE implements redis.Subscriber.
+func (l *Listener) SUBSCRIBE(channels ...string) { l.set(l.subs, channels, true) }
+
+// UNSUBSCRIBE implements redis.Subscriber.
+func (l *Listener) UNSUBSCRIBE(channels ...string) { l.set(l.subs, channels, false) }
+
+// PSUBSCRIBE implements redis.Subscriber.
+func (l *Listener) PSUBSCRIBE(patterns ...string) { l.set(l.psubs, patterns, true) }
+
+// PUNSUBSCRIBE implements redis.Subscriber.
+func (l *Listener) PUNSUBSCRIBE(patterns ...string) { l.set(l.psubs, patterns, false) }
+
+// SUBSCRIBEAck implements redis.Subscriber. The return has nil, or
+// redis.ErrClosed after Close.
+func (l *Listener) SUBSCRIBEAck(channels ...string) <-chan error {
+	return l.ack(l.set(l.subs, channels, true))
+}
+
+// PSUBSCRIBEAck implements redis.Subscriber. The return has nil, or
+// redis.ErrClosed after Close.
+func (l *Listener) PSUBSCRIBEAck(patterns ...string) <-chan error {
+	return l.ack(l.set(l.psubs, patterns, true))
+}
+
+func (l *Listener) ack(ok bool) <-chan error {
+	done := make(chan error, 1)
+	if ok {
+		done <- nil
+	} else {
+		done <- redis.ErrClosed
+	}
+	return done
+}
+
+// Set either adds or removes names from a subscription set. The return is
+// false when closed.
+func (l *Listener) set(m map[string]struct{}, names []string, add bool) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.closed {
+		return false
+	}
+	for _, s := range names {
+		if add {
+			m[s] = struct{}{}
+		} else {
+			delete(m, s)
+		}
+	}
+	return true
+}
+
+// Close implements redis.Subscriber. The OnDisconnect callback, if any, gets
+// redis.ErrClosed, and so does Func.
+func (l *Listener) Close() error {
+	l.mutex.Lock()
+	closed := l.closed
+	l.closed = true
+	l.mutex.Unlock()
+	if closed {
+		return nil
+	}
+
+	l.callbackSerial.Lock()
+	defer l.callbackSerial.Unlock()
+	if l.config.OnDisconnect != nil {
+		l.config.OnDisconnect(redis.ErrClosed)
+	}
+	l.config.Func("", nil, redis.ErrClosed)
+	return nil
+}
+
+// Subscriptions returns the channels and the patterns in effect, in
+// alphabetical order.
+func (l *Listener) Subscriptions() (channels, patterns []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return sortedKeys(l.subs), sortedKeys(l.psubs)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for s := range m {
+		keys = append(keys, s)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Publish delivers message to the subscription of channel, if any, and to each
+// pattern which matches channel, like PUBLISH would. The return is the number
+// of deliveries.
+func (l *Listener) Publish(channel string, message []byte) int {
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return 0
+	}
+	_, subscribed := l.subs[channel]
+	var patterns []string
+	for _, p := range sortedKeys(l.psubs) {
+		if Match(p, channel) {
+			patterns = append(patterns, p)
+		}
+	}
+	l.mutex.Unlock()
+
+	l.callbackSerial.Lock()
+	defer l.callbackSerial.Unlock()
+	n := 0
+	if subscribed {
+		l.deliver("", channel, message)
+		n++
+	}
+	for _, p := range patterns {
+		l.deliver(p, channel, message)
+		n++
+	}
+	return n
+}
+
+func (l *Listener) deliver(pattern, channel string, message []byte) {
+	switch {
+	case l.config.OwnedFunc != nil:
+		l.config.OwnedFunc(channel, &redis.Payload{
+			Pattern: pattern,
+			Bytes:   append([]byte(nil), message...),
+		})
+	case pattern != "" && l.config.PatternFunc != nil:
+		l.config.PatternFunc(pattern, channel, message)
+	default:
+		l.config.Func(channel, message, nil)
+	}
+}
+
+// Fail passes err to Func, with channel, which may be empty.
+func (l *Listener) Fail(channel string, err error) {
+	l.callbackSerial.Lock()
+	defer l.callbackSerial.Unlock()
+	l.config.Func(channel, nil, err)
+}
+
+// Reconnect simulates a connection loss with cause, followed by a successful
+// connect. Func gets cause, and so does OnDisconnect, if any. OnConnect, if any,
+// gets the subscriptions in effect. Messages published meanwhile are lost.
+func (l *Listener) Reconnect(cause error) {
+	l.callbackSerial.Lock()
+	defer l.callbackSerial.Unlock()
+	l.config.Func("", nil, cause)
+	if l.config.OnDisconnect != nil {
+		l.config.OnDisconnect(cause)
+	}
+	if l.config.OnConnect != nil {
+		l.config.OnConnect(l.Subscriptions())
+	}
+}
+
+// Match returns whether s matches the glob-style pattern of PSUBSCRIBE, with
+// wildcards '*' and '?', character classes as in "[a-z]" or "[^0-9]", and a
+// backslash to escape special characters.
+func Match(pattern, s string) bool {
+	for len(pattern) != 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if Match(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			pattern = pattern[1:]
+			not := len(pattern) != 0 && pattern[0] == '^'
+			if not {
+				pattern = pattern[1:]
+			}
+			match := false
+			for len(pattern) != 0 && pattern[0] != ']' {
+				switch {
+				case pattern[0] == '\\' && len(pattern) > 1:
+					if pattern[1] == s[0] {
+						match = true
+					}
+					pattern = pattern[2:]
+				case len(pattern) > 2 && pattern[1] == '-' && pattern[2] != ']':
+					lo, hi := pattern[0], pattern[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					if s[0] >= lo && s[0] <= hi {
+						match = true
+					}
+					pattern = pattern[3:]
+				default:
+					if pattern[0] == s[0] {
+						match = true
+					}
+					pattern = pattern[1:]
+				}
+			}
+			if len(pattern) != 0 {
+				pattern = pattern[1:] // skip ']'
+			}
+			if match == not {
+				return false
+			}
+			s = s[1:]
+
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		}
+	}
+	return len(s) == 0
+}
@@ -0,0 +1,119 @@
+package redistest_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/pascaldekloe/redis/v2/redistest"
+)
+
+func TestListener(t *testing.T) {
+	var events []string
+	config := redis.ListenerConfig{
+		Func: func(channel string, message []byte, err error) {
+			if err != nil {
+				events = append(events, "error "+err.Error())
+			} else {
+				events = append(events, "message "+channel+" "+string(message))
+			}
+		},
+		PatternFunc: func(pattern, channel string, message []byte) {
+			events = append(events, "pmessage "+pattern+" "+channel+" "+string(message))
+		},
+		OnConnect: func(channels, patterns []string) {
+			events = append(events, fmt.Sprintf("connect %q %q", channels, patterns))
+		},
+		OnDisconnect: func(err error) {
+			events = append(events, "disconnect "+err.Error())
+		},
+	}
+	var l redis.Subscriber = redistest.NewListener(config)
+	fake := l.(*redistest.Listener)
+
+	l.SUBSCRIBE("news", "sport")
+	if err := <-l.PSUBSCRIBEAck("n*"); err != nil {
+		t.Error("PSUBSCRIBEAck error:", err)
+	}
+	l.UNSUBSCRIBE("sport")
+	if n := fake.Publish("news", []byte("hello")); n != 2 {
+		t.Errorf("publish on news got %d deliveries, want 2", n)
+	}
+	if n := fake.Publish("sport", []byte("goal")); n != 0 {
+		t.Errorf("publish on sport got %d deliveries, want 0", n)
+	}
+	fake.Fail("", errors.New("boom"))
+	fake.Reconnect(errors.New("connection reset"))
+	l.Close()
+	if err := <-l.SUBSCRIBEAck("late"); err != redis.ErrClosed {
+		t.Errorf("SUBSCRIBEAck after Close got %v, want redis.ErrClosed", err)
+	}
+
+	want := []string{
+		`connect [] []`,
+		`message news hello`,
+		`pmessage n* news hello`,
+		`error boom`,
+		`error connection reset`,
+		`disconnect connection reset`,
+		`connect ["news"] ["n*"]`,
+		`disconnect redis: connection establishment closed`,
+		`error redis: connection establishment closed`,
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events:\n%q\nwant:\n%q", events, want)
+	}
+}
+
+func TestListenerOwned(t *testing.T) {
+	var got []string
+	l := redistest.NewListener(redis.ListenerConfig{
+		Func: func(channel string, message []byte, err error) {},
+		OwnedFunc: func(channel string, message *redis.Payload) {
+			got = append(got, message.Pattern+"|"+channel+"|"+string(message.Bytes))
+			message.Release()
+		},
+	})
+	defer l.Close()
+	l.SUBSCRIBE("a")
+	l.PSUBSCRIBE("[ab]")
+	l.Publish("a", []byte("1"))
+	l.Publish("b", []byte("2"))
+	want := []string{"|a|1", "[ab]|a|1", "[ab]|b|2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "", true},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "heeeello", true},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h[a-b]llo", "hcllo", false},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{"news.*", "news.art.figurative", true},
+		{"news.*", "new", false},
+		{"a*b*c", "aXbYc", true},
+		{"a*b*c", "aXbY", false},
+	}
+	for _, test := range tests {
+		if got := redistest.Match(test.pattern, test.s); got != test.want {
+			t.Errorf("Match(%q, %q) got %t, want %t", test.pattern, test.s, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Exchange is a command with its reply.
+type Exchange struct {
+	Command []string // including the command name
+	Reply   string   // RESP frame
+}
+
+// Recorder is a proxy on a loopback address, which records the conversation
+// with an upstream server. Replies are matched to commands in order per
+// connection. Out-of-band pushes from RESP3 and Pub/Sub are not supported.
+//
+// Multiple goroutines may invoke methods on a Recorder simultaneously.
+type Recorder struct {
+	// Addr is the network address in host:port notation.
+	Addr string
+
+	upstream string
+	listener net.Listener
+
+	mutex     sync.Mutex
+	exchanges []Exchange
+	conns     map[net.Conn]struct{}
+	closed    bool
+
+	done sync.WaitGroup
+}
+
+// NewRecorder starts a Recorder for the server at upstreamAddr. Close must be
+// called when done.
+func NewRecorder(upstreamAddr string) *Recorder {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		if l, err = net.Listen("tcp6", "[::1]:0"); err != nil {
+			panic(fmt.Sprintf("redistest: failed to listen on a port: %v", err))
+		}
+	}
+
+	rec := &Recorder{
+		Addr:     l.Addr().String(),
+		upstream: upstreamAddr,
+		listener: l,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	rec.done.Add(1)
+	go rec.acceptLoop()
+	return rec
+}
+
+// Close stops the Recorder, including any connections, and it blocks until all
+// goroutines are done.
+func (rec *Recorder) Close() {
+	rec.mutex.Lock()
+	rec.closed = true
+	for conn := range rec.conns {
+		conn.Close()
+	}
+	rec.mutex.Unlock()
+
+	rec.listener.Close()
+	rec.done.Wait()
+}
+
+// Exchanges returns the conversation so far, in order of reply.
+func (rec *Recorder) Exchanges() []Exchange {
+	rec.mutex.Lock()
+	defer rec.mutex.Unlock()
+	return append([]Exchange(nil), rec.exchanges...)
+}
+
+// Save writes the conversation so far to w, for use with Load.
+func (rec *Recorder) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, x := range rec.Exchanges() {
+		bw.WriteString(Command(x.Command...))
+		bw.WriteString(x.Reply)
+	}
+	return bw.Flush()
+}
+
+// Load reads a conversation as written by Save.
+func Load(r io.Reader) ([]Exchange, error) {
+	br := bufio.NewReader(r)
+	var exchanges []Exchange
+	for {
+		args, _, err := readCommand(br)
+		if err != nil {
+			if err == io.EOF {
+				return exchanges, nil
+			}
+			return nil, fmt.Errorf("redistest: load command %d: %w", len(exchanges)+1, err)
+		}
+		reply, err := readFrame(br)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("redistest: load reply %d: %w", len(exchanges)+1, err)
+		}
+		exchanges = append(exchanges, Exchange{Command: args, Reply: reply})
+	}
+}
+
+func (rec *Recorder) acceptLoop() {
+	defer rec.done.Done()
+	for {
+		conn, err := rec.listener.Accept()
+		if err != nil {
+			return
+		}
+		upstream, err := net.Dial("tcp", rec.upstream)
+		if err != nil {
+			conn.Close()
+			continue
+		}
+
+		rec.mutex.Lock()
+		if rec.closed {
+			rec.mutex.Unlock()
+			conn.Close()
+			upstream.Close()
+			return
+		}
+		rec.conns[conn] = struct{}{}
+		rec.conns[upstream] = struct{}{}
+		rec.mutex.Unlock()
+
+		rec.done.Add(1)
+		go rec.proxy(conn, upstream)
+	}
+}
+
+func (rec *Recorder) proxy(conn, upstream net.Conn) {
+	// commands pending a reply, in order of submission
+	pending := make(chan []string, 64)
+
+	defer rec.done.Done()
+	defer func() {
+		conn.Close()
+		upstream.Close()
+		rec.mutex.Lock()
+		delete(rec.conns, conn)
+		delete(rec.conns, upstream)
+		rec.mutex.Unlock()
+
+		// unblock the command reader
+		for range pending {
+		}
+	}()
+
+	rec.done.Add(1)
+	go func() {
+		defer rec.done.Done()
+		r := bufio.NewReader(conn)
+		for {
+			args, frame, err := readCommand(r)
+			if err != nil {
+				close(pending)
+				return
+			}
+			pending <- args
+			if _, err := io.WriteString(upstream, frame); err != nil {
+				close(pending)
+				return
+			}
+		}
+	}()
+
+	r := bufio.NewReader(upstream)
+	for args := range pending {
+		reply, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		rec.mutex.Lock()
+		rec.exchanges = append(rec.exchanges, Exchange{Command: args, Reply: reply})
+		rec.mutex.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// Replay queues each reply like Enqueue does, yet for the recorded command only.
+// Any other command gets an error reply, while the exchange remains pending.
+func (s *Server) Replay(exchanges ...Exchange) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, x := range exchanges {
+		s.queue = append(s.queue, queued{Reply: Reply{Frame: x.Reply}, want: x.Command})
+	}
+}
+
+var errReplyFrame = errors.New("redistest: malformed reply frame")
+
+// ReadFrame reads a reply of any type, including all of its nested elements.
+func readFrame(r *bufio.Reader) (string, error) {
+	var buf strings.Builder
+	if err := appendFrame(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func appendFrame(buf *strings.Builder, r *bufio.Reader) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return errReplyFrame
+	}
+	buf.WriteString(line)
+
+	switch line[0] {
+	case '+', '-', ':', ',', '(', '#', '_':
+		return nil // single line
+
+	case '$', '=', '!':
+		size, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil || size < -1 {
+			return errReplyFrame
+		}
+		if size < 0 {
+			return nil // null
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		buf.Write(payload)
+		return nil
+
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil || n < -1 {
+			return errReplyFrame
+		}
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2 // key–value pairs
+		}
+		for ; n > 0; n-- {
+			if err := appendFrame(buf, r); err != nil {
+				return err
+			}
+		}
+		if line[0] == '|' {
+			// attributes precede the actual reply
+			return appendFrame(buf, r)
+		}
+		return nil
+
+	default:
+		return errReplyFrame
+	}
+}
@@ -0,0 +1,315 @@
+// Package redistest provides an in-process RESP server for unit tests, as an
+// alternative to a Redis node. Replies are scripted per test, or they replay a
+// conversation which was recorded with a real server. Listener substitutes the
+// network for subscriber tests.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reply is a scripted response. Frame is written as is, which allows for broken
+// frames too.
+type Reply struct {
+	Frame  string
+	Delay  time.Duration // wait before write
+	Hangup bool          // close the connection after Frame
+}
+
+// Server is a RESP server on a loopback address. Commands get the first Reply
+// queued with Enqueue or Replay, if any. Otherwise, the handler of the command name
+// applies. Commands without either get PING and QUIT semantics, or an unknown
+// command error.
+//
+// Multiple goroutines may invoke methods on a Server simultaneously.
+type Server struct {
+	// Addr is the network address in host:port notation.
+	Addr string
+
+	listener net.Listener
+
+	mutex    sync.Mutex
+	queue    []queued
+	handlers map[string]func(args []string) Reply
+	received [][]string
+	conns    map[net.Conn]struct{}
+	closed   bool
+
+	receivedFrames []string
+
+	done sync.WaitGroup
+}
+
+// NewServer starts a Server on a loopback address. Close must be called when
+// done.
+func NewServer() *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		if l, err = net.Listen("tcp6", "[::1]:0"); err != nil {
+			panic(fmt.Sprintf("redistest: failed to listen on a port: %v", err))
+		}
+	}
+
+	s := &Server{
+		Addr:     l.Addr().String(),
+		listener: l,
+		handlers: make(map[string]func(args []string) Reply),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.done.Add(1)
+	go s.acceptLoop()
+	return s
+}
+
+// Close stops the Server, including any connections, and it blocks until all
+// goroutines are done.
+func (s *Server) Close() {
+	s.mutex.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.listener.Close()
+	s.done.Wait()
+}
+
+// CloseConns terminates all connections, like a server restart would.
+func (s *Server) CloseConns() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Enqueue adds replies for the commands that follow, in order of appearance,
+// regardless of their name.
+func (s *Server) Enqueue(replies ...Reply) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, reply := range replies {
+		s.queue = append(s.queue, queued{Reply: reply})
+	}
+}
+
+// Queued is a scripted reply, optionally bound to a command.
+type queued struct {
+	Reply
+	want []string // nil matches any
+}
+
+// Handle sets f for each command with name, case-insensitive. The arguments
+// include the command name. A nil f removes the handler.
+func (s *Server) Handle(name string, f func(args []string) Reply) {
+	name = strings.ToUpper(name)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if f == nil {
+		delete(s.handlers, name)
+	} else {
+		s.handlers[name] = f
+	}
+}
+
+// Received returns each command so far, in order of reception, including the
+// command name.
+func (s *Server) Received() [][]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([][]string(nil), s.received...)
+}
+
+// ReceivedFrames returns each command so far, in order of reception, as the
+// exact bytes read from the connection.
+func (s *Server) ReceivedFrames() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.receivedFrames...)
+}
+
+// ReceivedNames returns the command name of each command so far, in order of
+// reception, e.g., "GET" or "SET".
+func (s *Server) ReceivedNames() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	names := make([]string, len(s.received))
+	for i, args := range s.received {
+		if len(args) != 0 {
+			names[i] = strings.ToUpper(args[0])
+		}
+	}
+	return names
+}
+
+func (s *Server) acceptLoop() {
+	defer s.done.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+
+		s.done.Add(1)
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer s.done.Done()
+	defer func() {
+		conn.Close()
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, frame, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		reply := s.reply(args, frame)
+
+		if reply.Delay > 0 {
+			time.Sleep(reply.Delay)
+		}
+		if _, err := io.WriteString(conn, reply.Frame); err != nil {
+			return
+		}
+		if reply.Hangup {
+			return
+		}
+	}
+}
+
+func (s *Server) reply(args []string, frame string) Reply {
+	name := strings.ToUpper(args[0])
+
+	s.mutex.Lock()
+	s.received = append(s.received, args)
+	s.receivedFrames = append(s.receivedFrames, frame)
+	if len(s.queue) != 0 {
+		q := s.queue[0]
+		if q.want != nil && !equalArgs(q.want, args) {
+			s.mutex.Unlock()
+			return Reply{Frame: Error(fmt.Sprintf("ERR redistest: got command %q, want %q", args, q.want))}
+		}
+		s.queue = s.queue[1:]
+		s.mutex.Unlock()
+		return q.Reply
+	}
+	f, ok := s.handlers[name]
+	s.mutex.Unlock()
+	if ok {
+		return f(args)
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 1 {
+			return Reply{Frame: Bulk(args[1])}
+		}
+		return Reply{Frame: Status("PONG")}
+	case "QUIT":
+		return Reply{Frame: OK, Hangup: true}
+	default:
+		return Reply{Frame: Error("ERR unknown command '" + args[0] + "'")}
+	}
+}
+
+func equalArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var errFrame = errors.New("redistest: command not an array of bulk strings")
+
+// ReadCommand reads an array of bulk strings. The frame has the bytes read.
+func readCommand(r *bufio.Reader) (args []string, frame string, err error) {
+	var buf strings.Builder
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, "", err
+	}
+	buf.WriteString(line)
+	if len(line) < 4 || line[0] != '*' {
+		return nil, "", errFrame
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+	if err != nil || n < 1 {
+		return nil, "", errFrame
+	}
+
+	args = make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, "", err
+		}
+		buf.WriteString(line)
+		if len(line) < 4 || line[0] != '$' {
+			return nil, "", errFrame
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+		if err != nil || size < 0 {
+			return nil, "", errFrame
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, "", err
+		}
+		buf.Write(payload)
+		args[i] = string(payload[:size])
+	}
+	return args, buf.String(), nil
+}
+
+// OK is the status reply of success.
+const OK = "+OK\r\n"
+
+// Null is the RESP2 null bulk string.
+const Null = "$-1\r\n"
+
+// Status returns a simple string frame.
+func Status(s string) string { return "+" + s + "\r\n" }
+
+// Error returns an error frame, e.g., Error("ERR syntax error").
+func Error(s string) string { return "-" + s + "\r\n" }
+
+// Integer returns an integer frame.
+func Integer(n int64) string { return ":" + strconv.FormatInt(n, 10) + "\r\n" }
+
+// Bulk returns a bulk string frame.
+func Bulk(s string) string { return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n" }
+
+// Array returns an array frame with each element frame.
+func Array(elements ...string) string {
+	return "*" + strconv.Itoa(len(elements)) + "\r\n" + strings.Join(elements, "")
+}
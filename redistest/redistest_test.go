@@ -0,0 +1,259 @@
+package redistest_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pascaldekloe/redis/v2"
+	"github.com/pascaldekloe/redis/v2/redistest"
+)
+
+func TestEnqueue(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	s.Enqueue(
+		redistest.Reply{Frame: redistest.OK},
+		redistest.Reply{Frame: redistest.Bulk("v")},
+		redistest.Reply{Frame: redistest.Null},
+	)
+	if err := c.SET("k", "v"); err != nil {
+		t.Error("SET error:", err)
+	}
+	if v, err := c.GET("k"); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "v" {
+		t.Errorf("GET got %q, want %q", v, "v")
+	}
+	if v, err := c.GET("absent"); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "" {
+		t.Errorf("GET absent got %q, want empty", v)
+	}
+
+	want := [][]string{{"SET", "k", "v"}, {"GET", "k"}, {"GET", "absent"}}
+	if got := s.Received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %q, want %q", got, want)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	var n int64
+	s.Handle("incr", func(args []string) redistest.Reply {
+		n++
+		return redistest.Reply{Frame: redistest.Integer(n)}
+	})
+	for want := int64(1); want <= 3; want++ {
+		if got, err := c.INCR("counter"); err != nil {
+			t.Fatal("INCR error:", err)
+		} else if got != want {
+			t.Errorf("INCR got %d, want %d", got, want)
+		}
+	}
+
+	_, err := c.DEL("k")
+	var e redis.ServerError
+	if !errors.As(err, &e) || e.Prefix() != "ERR" {
+		t.Errorf("DEL without handler got error %v, want an ERR", err)
+	}
+	if got, want := s.ReceivedNames(), []string{"INCR", "INCR", "INCR", "DEL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("received %q, want %q", got, want)
+	}
+}
+
+func TestBrokenFrame(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	s.Enqueue(redistest.Reply{Frame: "$x\r\n"})
+	if _, err := c.GET("k"); err == nil {
+		t.Error("GET with broken frame got no error")
+	}
+
+	s.Enqueue(redistest.Reply{Frame: redistest.Bulk("v")})
+	if v, err := c.GET("k"); err != nil {
+		t.Error("GET after reconnect error:", err)
+	} else if v != "v" {
+		t.Errorf("GET after reconnect got %q, want %q", v, "v")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewClient[string, string](redis.ClientConfig{
+		Addr:           s.Addr,
+		CommandTimeout: 10 * time.Millisecond,
+	})
+	defer c.Close()
+
+	s.Enqueue(redistest.Reply{Frame: redistest.OK, Delay: 100 * time.Millisecond})
+	if err := c.SET("k", "v"); err == nil {
+		t.Error("SET with delayed reply got no error")
+	}
+}
+
+func TestHangup(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	s.Enqueue(redistest.Reply{Hangup: true})
+	if err := c.PING(); err == nil {
+		t.Error("PING with hangup got no error")
+	}
+	if err := c.PING(); err != nil {
+		t.Error("PING after reconnect error:", err)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	upstream := redistest.NewServer()
+	defer upstream.Close()
+	upstream.Enqueue(
+		redistest.Reply{Frame: redistest.OK},
+		redistest.Reply{Frame: redistest.Bulk("v")},
+		redistest.Reply{Frame: redistest.Array(redistest.Bulk("v"), redistest.Null)},
+	)
+
+	rec := redistest.NewRecorder(upstream.Addr)
+	c := redis.NewDefaultClient[string, string](rec.Addr)
+	conversation := func(t *testing.T, c *redis.Client[string, string]) {
+		if err := c.SET("k", "v"); err != nil {
+			t.Error("SET error:", err)
+		}
+		if v, err := c.GET("k"); err != nil {
+			t.Error("GET error:", err)
+		} else if v != "v" {
+			t.Errorf("GET got %q, want %q", v, "v")
+		}
+		if values, err := c.MGET("k", "absent"); err != nil {
+			t.Error("MGET error:", err)
+		} else if want := []string{"v", ""}; !reflect.DeepEqual(values, want) {
+			t.Errorf("MGET got %q, want %q", values, want)
+		}
+	}
+	conversation(t, c)
+	c.Close()
+	rec.Close()
+
+	var buf bytes.Buffer
+	if err := rec.Save(&buf); err != nil {
+		t.Fatal("save error:", err)
+	}
+	exchanges, err := redistest.Load(&buf)
+	if err != nil {
+		t.Fatal("load error:", err)
+	}
+	if !reflect.DeepEqual(exchanges, rec.Exchanges()) {
+		t.Errorf("loaded %q, want %q", exchanges, rec.Exchanges())
+	}
+
+	replay := redistest.NewServer()
+	defer replay.Close()
+	replay.Replay(exchanges...)
+	c = redis.NewDefaultClient[string, string](replay.Addr)
+	defer c.Close()
+	conversation(t, c)
+}
+
+func TestReplayMismatch(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	s.Replay(redistest.Exchange{Command: []string{"GET", "k"}, Reply: redistest.Bulk("v")})
+	if _, err := c.GET("other"); err == nil {
+		t.Error("GET of another key got no error")
+	} else if !strings.Contains(err.Error(), "redistest") {
+		t.Errorf("GET of another key got error %q, want a redistest mismatch", err)
+	}
+	// exchange remains pending
+	if v, err := c.GET("k"); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "v" {
+		t.Errorf("GET got %q, want %q", v, "v")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	const golden = "*2\r\n$3\r\nGET\r\n$1\r\nk\r\n$1\r\nv\r\n" +
+		"*1\r\n$5\r\nHELLO\r\n%2\r\n+server\r\n+redis\r\n+modules\r\n*0\r\n" +
+		"*2\r\n$4\r\nPING\r\n$1\r\nx\r\n|1\r\n+ttl\r\n:3\r\n$1\r\nx\r\n"
+	got, err := redistest.Load(strings.NewReader(golden))
+	if err != nil {
+		t.Fatal("load error:", err)
+	}
+	want := []redistest.Exchange{
+		{Command: []string{"GET", "k"}, Reply: "$1\r\nv\r\n"},
+		{Command: []string{"HELLO"}, Reply: "%2\r\n+server\r\n+redis\r\n+modules\r\n*0\r\n"},
+		{Command: []string{"PING", "x"}, Reply: "|1\r\n+ttl\r\n:3\r\n$1\r\nx\r\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := redistest.Load(strings.NewReader(golden[:len(golden)-3])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("load of truncated conversation got error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestAssertCommands(t *testing.T) {
+	s := redistest.NewServer()
+	defer s.Close()
+	c := redis.NewDefaultClient[string, string](s.Addr)
+	defer c.Close()
+
+	s.Enqueue(redistest.Reply{Frame: redistest.Integer(2)}, redistest.Reply{Frame: redistest.OK})
+	if _, err := c.DELArgs("a", "b"); err != nil {
+		t.Fatal("DEL error:", err)
+	}
+	if err := c.SET("k", "v"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	redistest.AssertCommands(t, s,
+		"*3\r\n$3\r\nDEL\r\n$1\r\na\r\n$1\r\nb\r\n",
+		redistest.Command("SET", "k", "v"),
+	)
+}
+
+// ErrorRecorder captures test errors.
+type errorRecorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *errorRecorder) Helper() {}
+
+func (r *errorRecorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFramesDeviation(t *testing.T) {
+	r := new(errorRecorder)
+	redistest.AssertFrames(r, []string{"+OK\r\n", ":12\r\n", "$0\r\n\r\n"}, "+OK\r\n", ":13\r\n")
+	want := []string{
+		`frame 1: got ":12\r\n", want ":13\r\n"; first difference at byte 2`,
+		`frame 2: got "$0\r\n\r\n", want none`,
+	}
+	if !reflect.DeepEqual(r.errs, want) {
+		t.Errorf("got errors %q, want %q", r.errs, want)
+	}
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaConfig defines the read distribution of NewReplicaClient.
+type ReplicaConfig struct {
+	// Addrs has the network address of each replica, in the same format
+	// as ClientConfig Addr.
+	Addrs []string
+
+	// MaxLag excludes replicas which were out of contact with their
+	// primary for longer than MaxLag, as reported by the
+	// master_last_io_seconds_ago field from INFO replication. Replicas
+	// without a link to their primary are excluded too. The check has a
+	// resolution of one second. Zero disables the check.
+	MaxLag time.Duration
+
+	// LagCheckInterval is the pace of the MaxLag checks. The default is
+	// one second.
+	LagCheckInterval time.Duration
+
+	// ReadAfterWrite routes read-only commands to the primary for the
+	// duration after each command with side effects, for read-your-writes
+	// consistency. Zero disables the routing.
+	ReadAfterWrite time.Duration
+}
+
+// ReplicaSet is the routing state of NewReplicaClient, as shared with views.
+type replicaSet struct {
+	// Submission time in nanoseconds since the Unix epoch of the most
+	// recent command with side effects, when ReadAfterWrite.
+	lastWrite int64
+
+	// Round-robin position for reads.
+	next uint32
+
+	// Availability per replica, in order of Addrs, with atomic access.
+	offline []int32 // connection absence
+	stale   []int32 // exceeds MaxLag
+
+	readAfterWrite time.Duration
+}
+
+// NewReplicaClient returns a Client which executes commands on a primary, as
+// configured with config, while read-only commands go to the replicas in a
+// round-robin fashion. Commands are read-only when they have no side effects,
+// as marked for ClientConfig RetryIdempotent, including server commands such
+// as PING and INFO. Replicas connect with the same ClientConfig, except for
+// the Addr and the blocking commands, which always go to the primary.
+//
+// Read-only commands fall back to the primary when none of the replicas are
+// available, i.e., when they are offline, or when they exceed MaxLag. Close
+// applies to the replicas as well. So do SELECT, RESET, SetPassword, Connect
+// and ForceReconnect, although replica failures are not returned by the last
+// two. Stats include the replicas in the totals.
+func NewReplicaClient[Key, Value String](config ClientConfig, replicas ReplicaConfig) *Client[Key, Value] {
+	set := &replicaSet{
+		offline:        make([]int32, len(replicas.Addrs)),
+		stale:          make([]int32, len(replicas.Addrs)),
+		readAfterWrite: replicas.ReadAfterWrite,
+	}
+
+	readers := make([]*Client[Key, Value], len(replicas.Addrs))
+	for i, addr := range replicas.Addrs {
+		replicaConfig := config
+		replicaConfig.Addr = addr
+		replicaConfig.BlockingPoolSize = -1
+		replicaConfig.ConnStateFunc = set.connStateFunc(i, config.ConnStateFunc)
+		if !config.LazyConnect {
+			// available once connected
+			set.offline[i] = 1
+		}
+		readers[i] = NewClient[Key, Value](replicaConfig)
+	}
+
+	c := NewClient[Key, Value](config)
+	c.replicas = set
+	c.readers = readers
+
+	if replicas.MaxLag > 0 {
+		interval := replicas.LagCheckInterval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		for i, r := range readers {
+			go set.lagLoop(i, ViewAs[string, string](r), replicas.MaxLag, interval)
+		}
+	}
+	return c
+}
+
+// ConnStateFunc returns a ConnStateFunc which tracks the availability of the
+// replica with index i before it passes the event on to f, if any.
+func (set *replicaSet) connStateFunc(i int, f func(ConnState, string, error)) func(ConnState, string, error) {
+	return func(state ConnState, addr string, err error) {
+		switch state {
+		case Connected:
+			atomic.StoreInt32(&set.offline[i], 0)
+		case Disconnected, Reconnecting:
+			// idle connections reconnect on demand
+			if err != errIdleTimeout {
+				atomic.StoreInt32(&set.offline[i], 1)
+			}
+		}
+		if f != nil {
+			f(state, addr, err)
+		}
+	}
+}
+
+// Pick returns the index of the replica for a request, or -1 for the primary.
+func (set *replicaSet) pick(readOnly bool) int {
+	if !readOnly {
+		if set.readAfterWrite > 0 {
+			atomic.StoreInt64(&set.lastWrite, time.Now().UnixNano())
+		}
+		return -1
+	}
+	if set.readAfterWrite > 0 && time.Now().UnixNano()-atomic.LoadInt64(&set.lastWrite) < int64(set.readAfterWrite) {
+		return -1
+	}
+
+	n := uint32(len(set.offline))
+	start := atomic.AddUint32(&set.next, 1)
+	for i := uint32(0); i < n; i++ {
+		j := (start + i) % n
+		if atomic.LoadInt32(&set.offline[j]) == 0 && atomic.LoadInt32(&set.stale[j]) == 0 {
+			return int(j)
+		}
+	}
+	return -1
+}
+
+// LagLoop updates the stale flag of the replica with index i on each interval
+// until the Client is closed.
+func (set *replicaSet) lagLoop(i int, c *Client[string, string], maxLag, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		info, err := c.WithContext(ctx).INFO("replication")
+		cancel()
+		if err == ErrClosed {
+			return
+		}
+
+		var stale int32
+		if err != nil || !replicaInSync(info, maxLag) {
+			stale = 1
+		}
+		atomic.StoreInt32(&set.stale[i], stale)
+	}
+}
+
+// ReplicaInSync returns whether the INFO replication fields are within maxLag.
+// Primaries, e.g., after a failover, are always in sync.
+func replicaInSync(info *Info, maxLag time.Duration) bool {
+	if info.Role != "slave" {
+		return true
+	}
+	if info.PrimaryLinkStatus != "up" {
+		return false
+	}
+	seconds, err := strconv.ParseInt(info.Fields["master_last_io_seconds_ago"], 10, 64)
+	return err == nil && seconds >= 0 && time.Duration(seconds)*time.Second <= maxLag
+}
+
+// Route returns the Client for a request, which is a replica for read-only
+// requests of NewReplicaClient, if any available, or a pool member otherwise.
+func (c *Client[Key, Value]) route(req *request) *Client[Key, Value] {
+	if c.replicas != nil {
+		if i := c.replicas.pick(req.retry); i >= 0 {
+			return c.readers[i].member()
+		}
+	}
+	return c.member()
+}
@@ -0,0 +1,218 @@
+package redis
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// FakeReplica serves reply for each command until the test ends. The return
+// is the network address.
+func fakeReplica(t *testing.T, reply func(args []string) string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			go func() {
+				r := bufio.NewReader(conn)
+				for {
+					args, err := readFakeCommand(r)
+					if err != nil {
+						return
+					}
+					if _, err := conn.Write([]byte(reply(args))); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func readFakeCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(line[1 : len(line)-2])
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(line[1 : len(line)-2])
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// ReplicaGETReply replies "replica" on GET, and OK otherwise.
+func replicaGETReply(args []string) string {
+	if args[0] == "GET" {
+		return "$7\r\nreplica\r\n"
+	}
+	return "+OK\r\n"
+}
+
+// NewTestReplicaClient returns a Client with testClient as the primary and
+// with a replica on addr. The return awaits the replica connection.
+func newTestReplicaClient(t *testing.T, addr string, replicas ReplicaConfig) *Client[string, string] {
+	connected := make(chan struct{}, 1)
+	config := testClient.ClientConfig
+	config.ConnStateFunc = func(state ConnState, connAddr string, err error) {
+		if state == Connected && connAddr == addr {
+			select {
+			case connected <- struct{}{}:
+			default:
+			}
+		}
+	}
+	replicas.Addrs = []string{addr}
+
+	c := NewReplicaClient[string, string](config, replicas)
+	t.Cleanup(func() { c.Close() })
+	select {
+	case <-connected:
+		break
+	case <-time.After(time.Second):
+		t.Fatal("replica connect timeout")
+	}
+	return c
+}
+
+func TestReplicaClient(t *testing.T) {
+	t.Parallel()
+	c := newTestReplicaClient(t, fakeReplica(t, replicaGETReply), ReplicaConfig{})
+
+	key := randomKey("replica")
+	if err := c.SET(key, "primary"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if got, err := c.GET(key); err != nil {
+		t.Error("GET error:", err)
+	} else if got != "replica" {
+		t.Errorf("GET got %q, want the replica reply", got)
+	}
+	if got, err := c.WithPrefix("x").GET(key); err != nil {
+		t.Error("GET on view error:", err)
+	} else if got != "replica" {
+		t.Errorf("GET on view got %q, want the replica reply", got)
+	}
+
+	if stats := c.Stats(); stats.CommandsSent != 3 {
+		t.Errorf("got %d commands sent, want 3", stats.CommandsSent)
+	}
+}
+
+func TestReplicaReadAfterWrite(t *testing.T) {
+	t.Parallel()
+	c := newTestReplicaClient(t, fakeReplica(t, replicaGETReply), ReplicaConfig{
+		ReadAfterWrite: time.Hour,
+	})
+
+	key := randomKey("replica")
+	if err := c.SET(key, "primary"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if got, err := c.GET(key); err != nil {
+		t.Error("GET error:", err)
+	} else if got != "primary" {
+		t.Errorf("GET got %q, want the primary value after write", got)
+	}
+}
+
+func TestReplicaFallback(t *testing.T) {
+	t.Parallel()
+
+	// no service
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c := NewReplicaClient[string, string](testClient.ClientConfig, ReplicaConfig{
+		Addrs: []string{addr},
+	})
+	defer c.Close()
+
+	key := randomKey("replica")
+	if err := c.SET(key, "primary"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if got, err := c.GET(key); err != nil {
+		t.Error("GET error:", err)
+	} else if got != "primary" {
+		t.Errorf("GET got %q, want the primary value", got)
+	}
+}
+
+func TestReplicaMaxLag(t *testing.T) {
+	t.Parallel()
+	addr := fakeReplica(t, func(args []string) string {
+		if args[0] == "INFO" {
+			const text = "# Replication\r\nrole:slave\r\nmaster_link_status:up\r\nmaster_last_io_seconds_ago:9\r\n"
+			return "$" + strconv.Itoa(len(text)) + "\r\n" + text + "\r\n"
+		}
+		return replicaGETReply(args)
+	})
+	c := newTestReplicaClient(t, addr, ReplicaConfig{
+		MaxLag:           5 * time.Second,
+		LagCheckInterval: 10 * time.Millisecond,
+	})
+
+	key := randomKey("replica")
+	if err := c.SET(key, "primary"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	for deadline := time.Now().Add(time.Second); ; {
+		got, err := c.GET(key)
+		if err != nil {
+			t.Fatal("GET error:", err)
+		}
+		if got == "primary" {
+			break // stale replica excluded
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET got %q, want the primary value with a lagging replica", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReplicaInSync(t *testing.T) {
+	golden := []struct {
+		text string
+		want bool
+	}{
+		{"role:master\r\n", true},
+		{"role:slave\r\nmaster_link_status:down\r\nmaster_last_io_seconds_ago:-1\r\n", false},
+		{"role:slave\r\nmaster_link_status:up\r\nmaster_last_io_seconds_ago:1\r\n", true},
+		{"role:slave\r\nmaster_link_status:up\r\nmaster_last_io_seconds_ago:3\r\n", false},
+	}
+	for _, gold := range golden {
+		got := replicaInSync(parseInfo(gold.text), 2*time.Second)
+		if got != gold.want {
+			t.Errorf("got %t for %q, want %t", got, gold.text, gold.want)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// The FT commands require the RediSearch module, as included with Redis Stack.
+// Index names are no Keys, and so are the document IDs in search results, as
+// they include any prefix of the index definition.
+
+// SearchField is an attribute in the schema of an index.
+type SearchField struct {
+	// Name is a hash field, or a JSONPath for indices on JSON.
+	Name string
+	As   string // optional alias
+
+	// Type is one of TEXT, TAG, NUMERIC, GEO or GEOSHAPE.
+	Type string
+
+	Sortable bool
+	NoIndex  bool
+
+	Weight    float64 // TEXT only, with zero for the default (of 1)
+	Separator string  // TAG only, with empty for the default (of ",")
+}
+
+// SearchIndex is an index definition for FT.CREATE.
+type SearchIndex struct {
+	OnJSON   bool     // documents from JSON instead of hashes
+	Prefixes []string // Key prefixes, with none for all Keys
+	Filter   string   // optional expression on documents
+	Language string   // optional default, e.g., "dutch"
+	Schema   []SearchField
+}
+
+// FTCREATE executes <https://redis.io/commands/ft.create>.
+func (c *Client[Key, Value]) FTCREATE(index string, def SearchIndex) error {
+	args := []string{index, "ON", "HASH"}
+	if def.OnJSON {
+		args[2] = "JSON"
+	}
+	if len(def.Prefixes) != 0 {
+		args = append(args, "PREFIX", strconv.Itoa(len(def.Prefixes)))
+		args = append(args, def.Prefixes...)
+	}
+	if def.Filter != "" {
+		args = append(args, "FILTER", def.Filter)
+	}
+	if def.Language != "" {
+		args = append(args, "LANGUAGE", def.Language)
+	}
+
+	args = append(args, "SCHEMA")
+	for _, f := range def.Schema {
+		args = append(args, f.Name)
+		if f.As != "" {
+			args = append(args, "AS", f.As)
+		}
+		args = append(args, f.Type)
+		if f.Weight != 0 {
+			args = append(args, "WEIGHT", strconv.FormatFloat(f.Weight, 'f', -1, 64))
+		}
+		if f.Separator != "" {
+			args = append(args, "SEPARATOR", f.Separator)
+		}
+		if f.Sortable {
+			args = append(args, "SORTABLE")
+		}
+		if f.NoIndex {
+			args = append(args, "NOINDEX")
+		}
+	}
+	return c.commandOK(requestWithList("\r\n$9\r\nFT.CREATE", args))
+}
+
+// FTDROPINDEX executes <https://redis.io/commands/ft.dropindex>. The documents
+// of the index are deleted too when deleteDocs is set.
+func (c *Client[Key, Value]) FTDROPINDEX(index string, deleteDocs bool) error {
+	if deleteDocs {
+		return c.commandOK(requestWith2Strings("*3\r\n$12\r\nFT.DROPINDEX\r\n$", index, "DD"))
+	}
+	return c.commandOK(requestWithString("*2\r\n$12\r\nFT.DROPINDEX\r\n$", index))
+}
+
+// SearchOptions are the arguments of FT.SEARCH next to the query.
+type SearchOptions struct {
+	NoContent  bool // document IDs only
+	Verbatim   bool // no stemming
+	WithScores bool
+
+	// Return limits the fields per document. All fields load when empty.
+	Return []string
+
+	SortBy   string // optional attribute
+	SortDesc bool
+
+	// LIMIT applies when either is not zero. A negative Limit gets the
+	// total only.
+	Offset, Limit int64
+
+	// Params has values for $name references in the query.
+	Params map[string]string
+
+	// Dialect is the query syntax version, with zero for the default.
+	Dialect int64
+}
+
+// SearchResult is the reply of FT.SEARCH.
+type SearchResult struct {
+	Total int64 // number of matches, regardless of any LIMIT
+	Docs  []SearchDoc
+}
+
+// SearchDoc is a match from FT.SEARCH.
+type SearchDoc struct {
+	ID     string
+	Score  float64 // WithScores only
+	Fields map[string]string
+}
+
+// FTSEARCH executes <https://redis.io/commands/ft.search> with options o, which
+// may be nil.
+func (c *Client[Key, Value]) FTSEARCH(index, query string, o *SearchOptions) (*SearchResult, error) {
+	if o == nil {
+		o = new(SearchOptions)
+	}
+	args := []string{index, query}
+	if o.NoContent {
+		args = append(args, "NOCONTENT")
+	}
+	if o.Verbatim {
+		args = append(args, "VERBATIM")
+	}
+	if o.WithScores {
+		args = append(args, "WITHSCORES")
+	}
+	if len(o.Return) != 0 {
+		args = append(args, "RETURN", strconv.Itoa(len(o.Return)))
+		args = append(args, o.Return...)
+	}
+	if o.SortBy != "" {
+		args = append(args, "SORTBY", o.SortBy)
+		if o.SortDesc {
+			args = append(args, "DESC")
+		}
+	}
+	args = appendSearchLimit(args, o.Offset, o.Limit)
+	args = appendSearchParams(args, o.Params, o.Dialect)
+
+	req := requestWithList("\r\n$9\r\nFT.SEARCH", args).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := readSearchResult(r, o.NoContent, o.WithScores)
+	c.passRead(err)
+	return result, err
+}
+
+// AggregateOptions are the arguments of FT.AGGREGATE next to the query. The
+// pipeline applies in order of declaration, i.e., LOAD, GROUPBY with REDUCE,
+// APPLY, SORTBY, FILTER and then LIMIT.
+type AggregateOptions struct {
+	Verbatim bool // no stemming
+
+	// Load has attributes to load from the documents, e.g., "@title".
+	Load []string
+
+	// GroupBy has properties, e.g., "@city", for the Reducers.
+	GroupBy  []string
+	Reducers []Reducer
+
+	Apply []AggregateApply
+
+	// SortBy has properties, each optionally followed by ASC or DESC.
+	SortBy []string
+
+	Filter string // optional expression on results
+
+	// LIMIT applies when either is not zero.
+	Offset, Limit int64
+
+	// Params has values for $name references in the query.
+	Params map[string]string
+
+	// Dialect is the query syntax version, with zero for the default.
+	Dialect int64
+}
+
+// Reducer is a REDUCE step for GROUPBY, e.g., Func "COUNT" As "n".
+type Reducer struct {
+	Func string
+	Args []string
+	As   string // optional property name
+}
+
+// AggregateApply is an APPLY step, e.g., Expr "upper(@name)" As "name".
+type AggregateApply struct {
+	Expr string
+	As   string
+}
+
+// AggregateResult is the reply of FT.AGGREGATE.
+type AggregateResult struct {
+	Total int64
+	Rows  []map[string]string
+}
+
+// FTAGGREGATE executes <https://redis.io/commands/ft.aggregate> with options o,
+// which may be nil.
+func (c *Client[Key, Value]) FTAGGREGATE(index, query string, o *AggregateOptions) (*AggregateResult, error) {
+	if o == nil {
+		o = new(AggregateOptions)
+	}
+	args := []string{index, query}
+	if o.Verbatim {
+		args = append(args, "VERBATIM")
+	}
+	if len(o.Load) != 0 {
+		args = append(args, "LOAD", strconv.Itoa(len(o.Load)))
+		args = append(args, o.Load...)
+	}
+	if len(o.GroupBy) != 0 {
+		args = append(args, "GROUPBY", strconv.Itoa(len(o.GroupBy)))
+		args = append(args, o.GroupBy...)
+		for _, red := range o.Reducers {
+			args = append(args, "REDUCE", red.Func, strconv.Itoa(len(red.Args)))
+			args = append(args, red.Args...)
+			if red.As != "" {
+				args = append(args, "AS", red.As)
+			}
+		}
+	}
+	for _, a := range o.Apply {
+		args = append(args, "APPLY", a.Expr, "AS", a.As)
+	}
+	if len(o.SortBy) != 0 {
+		args = append(args, "SORTBY", strconv.Itoa(len(o.SortBy)))
+		args = append(args, o.SortBy...)
+	}
+	if o.Filter != "" {
+		args = append(args, "FILTER", o.Filter)
+	}
+	args = appendSearchLimit(args, o.Offset, o.Limit)
+	args = appendSearchParams(args, o.Params, o.Dialect)
+
+	req := requestWithList("\r\n$12\r\nFT.AGGREGATE", args).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := readAggregateResult(r)
+	c.passRead(err)
+	return result, err
+}
+
+func appendSearchLimit(args []string, offset, limit int64) []string {
+	switch {
+	case limit < 0:
+		return append(args, "LIMIT", "0", "0")
+	case offset != 0 || limit != 0:
+		return append(args, "LIMIT", strconv.FormatInt(offset, 10), strconv.FormatInt(limit, 10))
+	default:
+		return args
+	}
+}
+
+func appendSearchParams(args []string, params map[string]string, dialect int64) []string {
+	if len(params) != 0 {
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		args = append(args, "PARAMS", strconv.Itoa(len(params)*2))
+		for _, name := range names {
+			args = append(args, name, params[name])
+		}
+	}
+	if dialect != 0 {
+		args = append(args, "DIALECT", strconv.FormatInt(dialect, 10))
+	}
+	return args
+}
+
+// ReadSearchResult reads either the RESP2 array or the RESP3 map.
+func readSearchResult(r *connReader, noContent, withScores bool) (*SearchResult, error) {
+	head, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	var result SearchResult
+	if head[0] == '%' {
+		err := readMapFunc(r, func(name string) error {
+			switch name {
+			case "total_results":
+				var err error
+				result.Total, err = readInteger(r)
+				return err
+			case "results":
+				n, err := readArrayLen(r)
+				if err != nil {
+					return err
+				}
+				result.Docs = make([]SearchDoc, n)
+				for i := range result.Docs {
+					if err := readSearchDoc3(r, &result.Docs[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			default:
+				return discardReply(r)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("redis: FT.SEARCH reply: %w", err)
+		}
+		return &result, nil
+	}
+
+	n, err := readArrayLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("%w; FT.SEARCH reply without total", errProtocol)
+	}
+	result.Total, err = readInteger(r)
+	if err != nil {
+		return nil, fmt.Errorf("redis: FT.SEARCH total: %w", err)
+	}
+	perDoc := int64(1)
+	if withScores {
+		perDoc++
+	}
+	if !noContent {
+		perDoc++
+	}
+	if (n-1)%perDoc != 0 {
+		return nil, fmt.Errorf("%w; FT.SEARCH reply with %d elements", errProtocol, n)
+	}
+	result.Docs = make([]SearchDoc, (n-1)/perDoc)
+	for i := range result.Docs {
+		doc := &result.Docs[i]
+		doc.ID, err = readBulk[string](r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: FT.SEARCH document ID: %w", err)
+		}
+		if withScores {
+			score, err := readBulk[string](r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: FT.SEARCH score: %w", err)
+			}
+			doc.Score, _ = strconv.ParseFloat(score, 64)
+		}
+		if !noContent {
+			doc.Fields, err = readSearchFields(r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: FT.SEARCH fields: %w", err)
+			}
+		}
+	}
+	return &result, nil
+}
+
+func readSearchDoc3(r *connReader, doc *SearchDoc) error {
+	return readMapFunc(r, func(name string) error {
+		switch name {
+		case "id":
+			var err error
+			doc.ID, err = readBulk[string](r)
+			return err
+		case "score":
+			score, err := readBulk[string](r)
+			doc.Score, _ = strconv.ParseFloat(score, 64)
+			return err
+		case "extra_attributes":
+			var err error
+			doc.Fields, err = readSearchFields(r)
+			return err
+		default:
+			return discardReply(r)
+		}
+	})
+}
+
+func readSearchFields(r *connReader) (map[string]string, error) {
+	names, values, err := readMap[string, string](r)
+	if err != nil {
+		if err == errNull {
+			err = nil
+		}
+		return nil, err
+	}
+	fields := make(map[string]string, len(names))
+	for i, name := range names {
+		fields[name] = values[i]
+	}
+	return fields, nil
+}
+
+// ReadAggregateResult reads either the RESP2 array or the RESP3 map.
+func readAggregateResult(r *connReader) (*AggregateResult, error) {
+	head, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	var result AggregateResult
+	if head[0] == '%' {
+		err := readMapFunc(r, func(name string) error {
+			switch name {
+			case "total_results":
+				var err error
+				result.Total, err = readInteger(r)
+				return err
+			case "results":
+				n, err := readArrayLen(r)
+				if err != nil {
+					return err
+				}
+				result.Rows = make([]map[string]string, n)
+				for i := range result.Rows {
+					err := readMapFunc(r, func(name string) error {
+						if name != "extra_attributes" {
+							return discardReply(r)
+						}
+						var err error
+						result.Rows[i], err = readSearchFields(r)
+						return err
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			default:
+				return discardReply(r)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("redis: FT.AGGREGATE reply: %w", err)
+		}
+		return &result, nil
+	}
+
+	n, err := readArrayLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("%w; FT.AGGREGATE reply without total", errProtocol)
+	}
+	result.Total, err = readInteger(r)
+	if err != nil {
+		return nil, fmt.Errorf("redis: FT.AGGREGATE total: %w", err)
+	}
+	result.Rows = make([]map[string]string, n-1)
+	for i := range result.Rows {
+		result.Rows[i], err = readSearchFields(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: FT.AGGREGATE row: %w", err)
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadSearchResult(t *testing.T) {
+	golden := []struct {
+		reply                 string
+		noContent, withScores bool
+		want                  SearchResult
+	}{
+		{
+			reply: "*5\r\n:2\r\n$5\r\ndoc:1\r\n*2\r\n$5\r\ntitle\r\n$5\r\nhello\r\n$5\r\ndoc:2\r\n*0\r\n",
+			want: SearchResult{Total: 2, Docs: []SearchDoc{
+				{ID: "doc:1", Fields: map[string]string{"title": "hello"}},
+				{ID: "doc:2", Fields: map[string]string{}},
+			}},
+		}, {
+			reply:     "*3\r\n:9\r\n$5\r\ndoc:1\r\n$5\r\ndoc:2\r\n",
+			noContent: true,
+			want:      SearchResult{Total: 9, Docs: []SearchDoc{{ID: "doc:1"}, {ID: "doc:2"}}},
+		}, {
+			reply:      "*4\r\n:1\r\n$5\r\ndoc:1\r\n$3\r\n1.5\r\n*2\r\n$1\r\nn\r\n$1\r\n7\r\n",
+			withScores: true,
+			want: SearchResult{Total: 1, Docs: []SearchDoc{
+				{ID: "doc:1", Score: 1.5, Fields: map[string]string{"n": "7"}},
+			}},
+		}, {
+			// RESP3
+			reply: "%3\r\n+attributes\r\n*0\r\n+results\r\n*1\r\n%3\r\n+id\r\n$5\r\ndoc:1\r\n+extra_attributes\r\n%1\r\n$5\r\ntitle\r\n$5\r\nhello\r\n+values\r\n*0\r\n+total_results\r\n:4\r\n",
+			want: SearchResult{Total: 4, Docs: []SearchDoc{
+				{ID: "doc:1", Fields: map[string]string{"title": "hello"}},
+			}},
+		},
+	}
+	for _, gold := range golden {
+		r := testReader(gold.reply + "+OK\r\n")
+		got, err := readSearchResult(r, gold.noContent, gold.withScores)
+		if err != nil {
+			t.Errorf("%q got error: %s", gold.reply, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, gold.want) {
+			t.Errorf("%q got %+v, want %+v", gold.reply, *got, gold.want)
+		}
+		if err := readOK(r); err != nil {
+			t.Errorf("%q: OK after reply got error: %s", gold.reply, err)
+		}
+	}
+}
+
+func TestReadAggregateResult(t *testing.T) {
+	const reply = "*3\r\n:2\r\n*4\r\n$4\r\ncity\r\n$5\r\nDelft\r\n$1\r\nn\r\n$1\r\n3\r\n*2\r\n$4\r\ncity\r\n$6\r\nLeiden\r\n+OK\r\n"
+	r := testReader(reply)
+	got, err := readAggregateResult(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	want := AggregateResult{Total: 2, Rows: []map[string]string{
+		{"city": "Delft", "n": "3"},
+		{"city": "Leiden"},
+	}}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after FT.AGGREGATE reply got error:", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+	index := randomKey("idx")
+	prefix := index + ":"
+
+	err := testClient.FTCREATE(index, SearchIndex{
+		Prefixes: []string{prefix},
+		Schema: []SearchField{
+			{Name: "title", Type: "TEXT"},
+			{Name: "city", Type: "TAG", Sortable: true},
+		},
+	})
+	if err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("FT.CREATE error:", err)
+	}
+	defer func() {
+		if err := testClient.FTDROPINDEX(index, true); err != nil {
+			t.Error("FT.DROPINDEX error:", err)
+		}
+	}()
+
+	if _, err := testClient.HSET(prefix+"1", "title", "hello world"); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if _, err := testClient.HSET(prefix+"1", "city", "Delft"); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+
+	result, err := testClient.FTSEARCH(index, "hello", nil)
+	if err != nil {
+		t.Fatal("FT.SEARCH error:", err)
+	}
+	if result.Total != 1 || len(result.Docs) != 1 || result.Docs[0].ID != prefix+"1" || result.Docs[0].Fields["city"] != "Delft" {
+		t.Errorf("FT.SEARCH got %+v, want document %q with city Delft", result, prefix+"1")
+	}
+
+	agg, err := testClient.FTAGGREGATE(index, "*", &AggregateOptions{
+		GroupBy:  []string{"@city"},
+		Reducers: []Reducer{{Func: "COUNT", As: "n"}},
+	})
+	if err != nil {
+		t.Fatal("FT.AGGREGATE error:", err)
+	}
+	if len(agg.Rows) != 1 || agg.Rows[0]["n"] != "1" {
+		t.Errorf("FT.AGGREGATE got %+v, want one row with n 1", agg)
+	}
+}
@@ -0,0 +1,799 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CLIENTPAUSE executes <https://redis.io/commands/client-pause>. The timeout
+// is truncated to milliseconds. Mode WRITE applies when writeOnly is set, and
+// mode ALL applies otherwise. Mode WRITE requires Redis version 6.2 or later.
+func (c *Client[Key, Value]) CLIENTPAUSE(timeout time.Duration, writeOnly bool) error {
+	if !writeOnly {
+		return c.commandOK(requestWithDecimal("*3\r\n$6\r\nCLIENT\r\n$5\r\nPAUSE\r\n$", int64(timeout/time.Millisecond)))
+	}
+	r := requestWithDecimal("*4\r\n$6\r\nCLIENT\r\n$5\r\nPAUSE\r\n$", int64(timeout/time.Millisecond))
+	r.buf = append(r.buf, "$5\r\nWRITE\r\n"...)
+	return c.commandOK(r)
+}
+
+// CLIENTUNPAUSE executes <https://redis.io/commands/client-unpause>.
+// Redis version 6.2 or later is required.
+func (c *Client[Key, Value]) CLIENTUNPAUSE() error {
+	return c.commandOK(requestFix("*2\r\n$6\r\nCLIENT\r\n$7\r\nUNPAUSE\r\n"))
+}
+
+// SELECT executes <https://redis.io/commands/select>. The database applies to
+// all commands that follow, including those on reconnects, as ClientConfig DB
+// is updated on success. Note that pipelined commands from other goroutines
+// may execute on either side of the switch. Each connection of a pool switches
+// in line, and so do the replicas of NewReplicaClient. Connections of a pool
+// which fail to switch reconnect when any other connection did switch, such
+// that they end up on the new database too. The return is the first error, if
+// any, once each connection had its attempt. Dedicated connections for
+// blocking commands reconnect on their next use.
+func (c *Client[Key, Value]) SELECT(db int64) error {
+	if c.blocking != nil {
+		defer c.blocking.invalidate()
+	}
+	return c.applyAll(func(conn *Client[Key, Value]) error {
+		return conn.selectConn(db)
+	})
+}
+
+func (c *Client[Key, Value]) selectConn(db int64) error {
+	r, err := c.exchange(requestWithDecimal("*2\r\n$6\r\nSELECT\r\n$", db))
+	if err != nil {
+		return err
+	}
+	err = readOK(r)
+	if err == nil {
+		atomic.StoreInt64(&c.config.DB, db)
+	}
+	c.passRead(err)
+	return err
+}
+
+// SWAPDB executes <https://redis.io/commands/swapdb>.
+func (c *Client[Key, Value]) SWAPDB(db1, db2 int64) error {
+	return c.commandOK(requestWith2Decimals("*3\r\n$6\r\nSWAPDB\r\n$", db1, db2))
+}
+
+// RESET executes <https://redis.io/commands/reset>. The connection settings
+// from ClientConfig, i.e., AUTH, HELLO, CLIENT SETNAME and SELECT, are applied
+// again within the same request. Any errors on the latter cause a reconnect.
+// Each connection of a pool resets in line, and so do the replicas of
+// NewReplicaClient. Connections of a pool which fail to reset reconnect when
+// any other connection did reset, such that they end up in the clean state
+// too. The return is the first error, if any, once each connection had its
+// attempt. Dedicated connections for blocking commands reconnect on their next
+// use. Redis version 6.2 or later is required.
+func (c *Client[Key, Value]) RESET() error {
+	if c.blocking != nil {
+		defer c.blocking.invalidate()
+	}
+	return c.applyAll((*Client[Key, Value]).resetConn)
+}
+
+func (c *Client[Key, Value]) resetConn() error {
+	req := requestFix("*1\r\n$5\r\nRESET\r\n")
+	_, password := c.target.load()
+	auth, selectDB := c.config.addSticky(req, password)
+	r, err := c.exchange(req)
+	if err != nil {
+		return err
+	}
+
+	err = readStatus(r, "RESET")
+	if _, ok := err.(ServerError); err == nil || ok {
+		// sticky settings execute regardless
+		if stickyErr := c.config.readSticky(r, auth, selectDB, "after RESET"); stickyErr != nil {
+			err = stickyErr
+		}
+	}
+	c.passRead(err)
+	return err
+}
+
+// ApplyAll runs f on each connection of the pool, and on those of the replicas
+// of NewReplicaClient. Connections of a pool on which f fails reconnect when f
+// succeeded on any other connection of the same pool. The return is the first
+// error, if any.
+func (c *Client[Key, Value]) applyAll(f func(conn *Client[Key, Value]) error) error {
+	var err error
+	for _, r := range c.readers {
+		if applyErr := r.applyAll(f); applyErr != nil && err == nil {
+			err = applyErr
+		}
+	}
+
+	pool := c.pool()
+	var failed []*Client[Key, Value]
+	for _, m := range pool {
+		if applyErr := f(m); applyErr != nil {
+			failed = append(failed, m)
+			if err == nil {
+				err = applyErr
+			}
+		}
+	}
+	if len(failed) != 0 && len(failed) < len(pool) {
+		for _, m := range failed {
+			m.reconnectPipeline() // with settings from ClientConfig
+		}
+	}
+	return err
+}
+
+// WAIT executes <https://redis.io/commands/wait>. The return is the number of
+// replicas that acknowledged all preceding writes from this connection. A zero
+// timeout blocks until numReplicas is reached. The timeout is truncated to
+// milliseconds. Note that the pipeline stalls until WAIT returns, as WAIT
+// applies to the connection. Any CommandTimeout in ClientConfig applies on top
+// of the timeout. With PoolSize, WAIT goes to any one connection of the pool,
+// which need not be the connection of the writes. Use a Client without PoolSize
+// for WAIT to count the acknowledgements of its own writes.
+func (c *Client[Key, Value]) WAIT(numReplicas int64, timeout time.Duration) (int64, error) {
+	return c.commandInteger(requestWith2Decimals("*3\r\n$4\r\nWAIT\r\n$", numReplicas, int64(timeout/time.Millisecond)).blocking(timeout))
+}
+
+// REPLICAOF executes <https://redis.io/commands/replicaof>, which turns the
+// server into a replica of the primary at host and port. Any data on the
+// server is discarded in favour of the primary's. Redis version 5 or later is
+// required.
+func (c *Client[Key, Value]) REPLICAOF(host string, port int64) error {
+	return c.commandReplicaOf(requestWithStringAndDecimal("*3\r\n$9\r\nREPLICAOF\r\n$", host, port))
+}
+
+// REPLICAOFNOONE executes <https://redis.io/commands/replicaof> with NO ONE,
+// which promotes a replica to a primary. The data set is kept as is. Redis
+// version 5 or later is required.
+func (c *Client[Key, Value]) REPLICAOFNOONE() error {
+	return c.commandReplicaOf(requestFix("*3\r\n$9\r\nREPLICAOF\r\n$2\r\nNO\r\n$3\r\nONE\r\n"))
+}
+
+// CommandReplicaOf accepts status replies like "OK Already connected to
+// specified master" too.
+func (c *Client[Key, Value]) commandReplicaOf(req *request) error {
+	status, err := c.commandString(req)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(status, "OK") {
+		return fmt.Errorf("%w; received %.40q for REPLICAOF", errProtocol, status)
+	}
+	return nil
+}
+
+// PING executes <https://redis.io/commands/ping>.
+func (c *Client[Key, Value]) PING() error {
+	req := requestFix("*1\r\n$4\r\nPING\r\n").idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return err
+	}
+	err = readStatus(r, "PONG")
+	c.passRead(err)
+	return err
+}
+
+// PINGWithMessage executes <https://redis.io/commands/ping> with a message.
+// The return is a copy of the message.
+func (c *Client[Key, Value]) PINGWithMessage(message Value) (Value, error) {
+	return c.commandBulk(requestWithString("*2\r\n$4\r\nPING\r\n$", message).idempotent())
+}
+
+// ECHO executes <https://redis.io/commands/echo>.
+// The return is a copy of the message.
+func (c *Client[Key, Value]) ECHO(message Value) (Value, error) {
+	return c.commandBulk(requestWithString("*2\r\n$4\r\nECHO\r\n$", message).idempotent())
+}
+
+// TIME executes <https://redis.io/commands/time>. The return has microsecond
+// precision, from the clock of the server.
+func (c *Client[Key, Value]) TIME() (time.Time, error) {
+	req := requestFix("*1\r\n$4\r\nTIME\r\n").idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	parts, err := readArray[[]byte](r)
+	c.passRead(err)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("%w; TIME reply with %d elements", errProtocol, len(parts))
+	}
+	return time.Unix(ParseInt(parts[0]), ParseInt(parts[1])*1000), nil
+}
+
+// CONFIGGET executes <https://redis.io/commands/config-get> with a glob-style
+// pattern, e.g., "maxmemory*". The return maps each parameter matched to its
+// value.
+func (c *Client[Key, Value]) CONFIGGET(pattern string) (map[string]string, error) {
+	req := requestWithString("*3\r\n$6\r\nCONFIG\r\n$3\r\nGET\r\n$", pattern).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	names, values, err := readMap[string, string](r)
+	c.passRead(err)
+	if err != nil {
+		if err == errNull {
+			err = nil
+		}
+		return nil, err
+	}
+	m := make(map[string]string, len(names))
+	for i, name := range names {
+		m[name] = values[i]
+	}
+	return m, nil
+}
+
+// CONFIGSET executes <https://redis.io/commands/config-set> with each name
+// set to the value at the same index. Multiple parameters, which apply
+// atomically, require Redis version 7 or later.
+func (c *Client[Key, Value]) CONFIGSET(names, values []string) error {
+	if len(names) != len(values) {
+		return errMapSlices
+	}
+	r := requestSize("\r\n$6\r\nCONFIG\r\n$3\r\nSET", len(names)*2+2)
+	r.buf, _ = appendCRLFAndMap(r.buf, names, values)
+	return c.commandOK(r)
+}
+
+// CONFIGREWRITE executes <https://redis.io/commands/config-rewrite>.
+func (c *Client[Key, Value]) CONFIGREWRITE() error {
+	return c.commandOK(requestFix("*2\r\n$6\r\nCONFIG\r\n$7\r\nREWRITE\r\n"))
+}
+
+// CONFIGRESETSTAT executes <https://redis.io/commands/config-resetstat>.
+func (c *Client[Key, Value]) CONFIGRESETSTAT() error {
+	return c.commandOK(requestFix("*2\r\n$6\r\nCONFIG\r\n$9\r\nRESETSTAT\r\n"))
+}
+
+// Info is the state of a server, conform INFO. Fields from sections which were
+// not requested stay zero.
+type Info struct {
+	// Server
+	Version       string // redis_version
+	Mode          string // either "standalone", "sentinel" or "cluster"
+	UptimeSeconds int64
+
+	// Clients
+	ConnectedClients int64
+	BlockedClients   int64
+
+	// Memory in bytes
+	UsedMemory            int64
+	UsedMemoryRSS         int64
+	UsedMemoryPeak        int64
+	MaxMemory             int64 // zero for no limit
+	MaxMemoryPolicy       string
+	MemFragmentationRatio float64
+
+	// Replication
+	Role              string // either "master" or "slave"
+	ConnectedReplicas int64
+	PrimaryHost       string // replicas only
+	PrimaryPort       int64  // replicas only
+	PrimaryLinkStatus string // either "up" or "down" for replicas
+	ReplOffset        int64
+
+	// Stats
+	TotalConnectionsReceived int64
+	TotalCommandsProcessed   int64
+	KeyspaceHits             int64
+	KeyspaceMisses           int64
+	ExpiredKeys              int64
+	EvictedKeys              int64
+
+	// Keyspace has an entry per database number with keys.
+	Keyspace map[int64]KeyspaceInfo
+
+	// Fields has all properties as reported by the server.
+	Fields map[string]string
+}
+
+// KeyspaceInfo is a database entry from INFO.
+type KeyspaceInfo struct {
+	Keys    int64
+	Expires int64         // number of keys with an expiry
+	AvgTTL  time.Duration // estimate for keys with an expiry
+}
+
+// INFO executes <https://redis.io/commands/info>, with the default sections
+// when none are specified. Multiple sections require Redis version 7 or later.
+func (c *Client[Key, Value]) INFO(sections ...string) (*Info, error) {
+	text, err := c.commandString(requestWithList("\r\n$4\r\nINFO", sections).idempotent())
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(text), nil
+}
+
+func parseInfo(text string) *Info {
+	info := Info{
+		Keyspace: make(map[int64]KeyspaceInfo),
+		Fields:   make(map[string]string),
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" || line[0] == '#' {
+			continue // section header
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+		name, value := line[:i], line[i+1:]
+		info.Fields[name] = value
+
+		if strings.HasPrefix(name, "db") {
+			if db, err := strconv.ParseInt(name[2:], 10, 64); err == nil {
+				info.Keyspace[db] = parseKeyspaceInfo(value)
+				continue
+			}
+		}
+
+		n, _ := strconv.ParseInt(value, 10, 64)
+		switch name {
+		case "redis_version":
+			info.Version = value
+		case "redis_mode":
+			info.Mode = value
+		case "uptime_in_seconds":
+			info.UptimeSeconds = n
+		case "connected_clients":
+			info.ConnectedClients = n
+		case "blocked_clients":
+			info.BlockedClients = n
+		case "used_memory":
+			info.UsedMemory = n
+		case "used_memory_rss":
+			info.UsedMemoryRSS = n
+		case "used_memory_peak":
+			info.UsedMemoryPeak = n
+		case "maxmemory":
+			info.MaxMemory = n
+		case "maxmemory_policy":
+			info.MaxMemoryPolicy = value
+		case "mem_fragmentation_ratio":
+			info.MemFragmentationRatio, _ = strconv.ParseFloat(value, 64)
+		case "role":
+			info.Role = value
+		case "connected_slaves":
+			info.ConnectedReplicas = n
+		case "master_host":
+			info.PrimaryHost = value
+		case "master_port":
+			info.PrimaryPort = n
+		case "master_link_status":
+			info.PrimaryLinkStatus = value
+		case "master_repl_offset":
+			info.ReplOffset = n
+		case "total_connections_received":
+			info.TotalConnectionsReceived = n
+		case "total_commands_processed":
+			info.TotalCommandsProcessed = n
+		case "keyspace_hits":
+			info.KeyspaceHits = n
+		case "keyspace_misses":
+			info.KeyspaceMisses = n
+		case "expired_keys":
+			info.ExpiredKeys = n
+		case "evicted_keys":
+			info.EvictedKeys = n
+		}
+	}
+	return &info
+}
+
+// ParseKeyspaceInfo reads a value like "keys=1,expires=0,avg_ttl=0".
+func parseKeyspaceInfo(value string) KeyspaceInfo {
+	var ks KeyspaceInfo
+	for _, pair := range strings.Split(value, ",") {
+		name, v, _ := strings.Cut(pair, "=")
+		n, _ := strconv.ParseInt(v, 10, 64)
+		switch name {
+		case "keys":
+			ks.Keys = n
+		case "expires":
+			ks.Expires = n
+		case "avg_ttl":
+			ks.AvgTTL = time.Duration(n) * time.Millisecond
+		}
+	}
+	return ks
+}
+
+// SlowLogEntry is an entry from SLOWLOG GET.
+type SlowLogEntry struct {
+	ID       int64
+	Time     time.Time     // start of execution
+	Duration time.Duration // execution time in microseconds
+
+	// Args has the command plus its arguments, which may be truncated.
+	Args []string
+
+	// The client fields require Redis version 4 or later.
+	ClientAddr string
+	ClientName string
+}
+
+// SLOWLOGGET executes <https://redis.io/commands/slowlog-get>. The return
+// has up to count entries, most recent first. A negative count gets all
+// entries.
+func (c *Client[Key, Value]) SLOWLOGGET(count int64) ([]SlowLogEntry, error) {
+	req := requestWithDecimal("*3\r\n$7\r\nSLOWLOG\r\n$3\r\nGET\r\n$", count).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := readSlowLog(r)
+	c.passRead(err)
+	return entries, err
+}
+
+// SLOWLOGLEN executes <https://redis.io/commands/slowlog-len>.
+func (c *Client[Key, Value]) SLOWLOGLEN() (int64, error) {
+	return c.commandInteger(requestFix("*2\r\n$7\r\nSLOWLOG\r\n$3\r\nLEN\r\n").idempotent())
+}
+
+// SLOWLOGRESET executes <https://redis.io/commands/slowlog-reset>.
+func (c *Client[Key, Value]) SLOWLOGRESET() error {
+	return c.commandOK(requestFix("*2\r\n$7\r\nSLOWLOG\r\n$5\r\nRESET\r\n"))
+}
+
+func readSlowLog(r *connReader) ([]SlowLogEntry, error) {
+	n, err := readArrayLen(r)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]SlowLogEntry, n)
+	for i := range entries {
+		e := &entries[i]
+		elementN, err := readArrayLen(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: SLOWLOG entry: %w", err)
+		}
+		if elementN < 4 {
+			return nil, fmt.Errorf("%w; SLOWLOG entry with %d elements", errProtocol, elementN)
+		}
+		e.ID, err = readInteger(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: SLOWLOG ID: %w", err)
+		}
+		unix, err := readInteger(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: SLOWLOG timestamp: %w", err)
+		}
+		e.Time = time.Unix(unix, 0)
+		micros, err := readInteger(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: SLOWLOG duration: %w", err)
+		}
+		e.Duration = time.Duration(micros) * time.Microsecond
+		e.Args, err = readArray[string](r)
+		if err != nil {
+			return nil, fmt.Errorf("redis: SLOWLOG arguments: %w", err)
+		}
+		if elementN > 4 {
+			e.ClientAddr, err = readBulk[string](r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: SLOWLOG client address: %w", err)
+			}
+		}
+		if elementN > 5 {
+			e.ClientName, err = readBulk[string](r)
+			if err != nil {
+				return nil, fmt.Errorf("redis: SLOWLOG client name: %w", err)
+			}
+		}
+		// skip any future extensions
+		for ; elementN > 6; elementN-- {
+			if err := discardReply(r); err != nil {
+				return nil, fmt.Errorf("redis: SLOWLOG entry: %w", err)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// MEMORYUSAGE executes <https://redis.io/commands/memory-usage>. The return
+// is in bytes, and zero when the Key does not exist. Nested values are sampled
+// up to count, with zero for all of them. A negative count applies the server
+// default (of 5).
+func (c *Client[Key, Value]) MEMORYUSAGE(k Key, samples int64) (int64, error) {
+	var req *request
+	if samples < 0 {
+		req = requestWith2Strings("*3\r\n$6\r\nMEMORY\r\n$", "USAGE", k).idempotent()
+	} else {
+		req = requestWith3StringsAndDecimal("*5\r\n$6\r\nMEMORY\r\n$", "USAGE", k, "SAMPLES", samples).idempotent()
+	}
+
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return 0, err
+	}
+	n, err := readIntegerOrNull(r)
+	c.passRead(err)
+	if err == errNull {
+		err = nil
+	}
+	return n, err
+}
+
+// MemoryStats is the memory usage of a server, conform MEMORY STATS. Sizes
+// are in bytes.
+type MemoryStats struct {
+	PeakAllocated      int64 // peak.allocated
+	TotalAllocated     int64 // total.allocated
+	StartupAllocated   int64 // startup.allocated
+	ReplicationBacklog int64 // replication.backlog
+	ClientsReplicas    int64 // clients.slaves
+	ClientsNormal      int64 // clients.normal
+	AOFBuffer          int64 // aof.buffer
+	OverheadTotal      int64 // overhead.total
+	KeysCount          int64 // keys.count
+	KeysBytesPerKey    int64 // keys.bytes-per-key
+	DatasetBytes       int64 // dataset.bytes
+
+	DatasetPercentage float64 // dataset.percentage
+	PeakPercentage    float64 // peak.percentage
+	Fragmentation     float64 // fragmentation
+
+	// DBs has the hash table overhead per database number with keys.
+	DBs map[int64]MemoryDBStats
+
+	// Fields has all properties as reported by the server, except for
+	// the database entries.
+	Fields map[string]string
+}
+
+// MemoryDBStats is a database entry from MEMORY STATS.
+type MemoryDBStats struct {
+	OverheadMain    int64 // overhead.hashtable.main
+	OverheadExpires int64 // overhead.hashtable.expires
+}
+
+// MEMORYSTATS executes <https://redis.io/commands/memory-stats>.
+func (c *Client[Key, Value]) MEMORYSTATS() (*MemoryStats, error) {
+	req := requestFix("*2\r\n$6\r\nMEMORY\r\n$5\r\nSTATS\r\n").idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := readMemoryStats(r)
+	c.passRead(err)
+	return stats, err
+}
+
+// MEMORYDOCTOR executes <https://redis.io/commands/memory-doctor>. The return
+// is a report in human-readable text.
+func (c *Client[Key, Value]) MEMORYDOCTOR() (string, error) {
+	return c.commandString(requestFix("*2\r\n$6\r\nMEMORY\r\n$6\r\nDOCTOR\r\n").idempotent())
+}
+
+func readMemoryStats(r *connReader) (*MemoryStats, error) {
+	stats := MemoryStats{
+		DBs:    make(map[int64]MemoryDBStats),
+		Fields: make(map[string]string),
+	}
+	err := readMapFunc(r, func(name string) error {
+		if strings.HasPrefix(name, "db.") {
+			db, err := strconv.ParseInt(name[3:], 10, 64)
+			if err != nil {
+				return discardReply(r)
+			}
+			var entry MemoryDBStats
+			err = readMapFunc(r, func(name string) error {
+				n, err := readInteger(r)
+				switch name {
+				case "overhead.hashtable.main":
+					entry.OverheadMain = n
+				case "overhead.hashtable.expires":
+					entry.OverheadExpires = n
+				}
+				return err
+			})
+			stats.DBs[db] = entry
+			return err
+		}
+
+		if head, err := r.Peek(1); err == nil && (head[0] == '*' || head[0] == '%') {
+			return discardReply(r) // unknown structure
+		}
+		value, err := readBulk[string](r)
+		if err != nil {
+			if err == errNull {
+				return nil
+			}
+			return err
+		}
+		stats.Fields[name] = value
+
+		n, _ := strconv.ParseInt(value, 10, 64)
+		switch name {
+		case "peak.allocated":
+			stats.PeakAllocated = n
+		case "total.allocated":
+			stats.TotalAllocated = n
+		case "startup.allocated":
+			stats.StartupAllocated = n
+		case "replication.backlog":
+			stats.ReplicationBacklog = n
+		case "clients.slaves":
+			stats.ClientsReplicas = n
+		case "clients.normal":
+			stats.ClientsNormal = n
+		case "aof.buffer":
+			stats.AOFBuffer = n
+		case "overhead.total":
+			stats.OverheadTotal = n
+		case "keys.count":
+			stats.KeysCount = n
+		case "keys.bytes-per-key":
+			stats.KeysBytesPerKey = n
+		case "dataset.bytes":
+			stats.DatasetBytes = n
+		case "dataset.percentage":
+			stats.DatasetPercentage, _ = strconv.ParseFloat(value, 64)
+		case "peak.percentage":
+			stats.PeakPercentage, _ = strconv.ParseFloat(value, 64)
+		case "fragmentation":
+			stats.Fragmentation, _ = strconv.ParseFloat(value, 64)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis: MEMORY STATS: %w", err)
+	}
+	return &stats, nil
+}
+
+// CommandInfo is the specification of a command, conform COMMAND INFO.
+type CommandInfo struct {
+	Name  string // lower case
+	Arity int64  // negative for a minimum number of arguments
+
+	// Flags has properties like "readonly", "write" or "movablekeys".
+	Flags []string
+
+	// Key positions in the arguments, with index zero for the command
+	// name. Negative values for LastKey count from the end. A zero Step
+	// means no Keys.
+	FirstKey, LastKey, Step int64
+
+	// ACL categories like "@string" require Redis version 6 or later.
+	ACLCategories []string
+
+	// Subcommands, like "config|get", require Redis version 7 or later.
+	Subcommands []CommandInfo
+}
+
+// COMMANDCOUNT executes <https://redis.io/commands/command-count>.
+func (c *Client[Key, Value]) COMMANDCOUNT() (int64, error) {
+	return c.commandInteger(requestFix("*2\r\n$7\r\nCOMMAND\r\n$5\r\nCOUNT\r\n").idempotent())
+}
+
+// COMMANDINFO executes <https://redis.io/commands/command-info>. The return has
+// an entry for each name, in order of appearance. Unknown commands get a zero
+// entry. Redis version 7 or later returns all commands when none are named.
+func (c *Client[Key, Value]) COMMANDINFO(names ...string) ([]CommandInfo, error) {
+	req := requestWithList("\r\n$7\r\nCOMMAND\r\n$4\r\nINFO", names).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := readCommandInfos(r)
+	c.passRead(err)
+	return infos, err
+}
+
+// COMMANDGETKEYS executes <https://redis.io/commands/command-getkeys>. The
+// return has each Key in the arguments of a command, e.g., "a" and "b" for
+// "MSET", "a", "1", "b", "2".
+func (c *Client[Key, Value]) COMMANDGETKEYS(args ...string) ([]Key, error) {
+	req := requestWithList("\r\n$7\r\nCOMMAND\r\n$7\r\nGETKEYS", args).idempotent()
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := readArray[Key](r)
+	c.passRead(err)
+	return keys, err
+}
+
+func readCommandInfos(r *connReader) ([]CommandInfo, error) {
+	n, err := readArrayLen(r)
+	if n == 0 {
+		if err == errNull {
+			err = nil
+		}
+		return nil, err
+	}
+	infos := make([]CommandInfo, n)
+	for i := range infos {
+		err := readCommandInfo(r, &infos[i])
+		if err != nil && err != errNull {
+			return nil, err
+		}
+	}
+	return infos, nil
+}
+
+func readCommandInfo(r *connReader, info *CommandInfo) error {
+	elementN, err := readArrayLen(r)
+	if err != nil {
+		if err == errNull {
+			return err // unknown command
+		}
+		return fmt.Errorf("redis: COMMAND INFO entry: %w", err)
+	}
+	if elementN < 6 {
+		return fmt.Errorf("%w; COMMAND INFO entry with %d elements", errProtocol, elementN)
+	}
+	info.Name, err = readBulk[string](r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO name: %w", err)
+	}
+	info.Arity, err = readInteger(r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO arity: %w", err)
+	}
+	info.Flags, err = readArray[string](r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO flags: %w", err)
+	}
+	info.FirstKey, err = readInteger(r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO first key: %w", err)
+	}
+	info.LastKey, err = readInteger(r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO last key: %w", err)
+	}
+	info.Step, err = readInteger(r)
+	if err != nil {
+		return fmt.Errorf("redis: COMMAND INFO step: %w", err)
+	}
+	if elementN > 6 {
+		info.ACLCategories, err = readArray[string](r)
+		if err != nil {
+			return fmt.Errorf("redis: COMMAND INFO ACL categories: %w", err)
+		}
+	}
+	// skip tips and key specifications (since Redis 7)
+	for i := int64(7); i < elementN && i < 9; i++ {
+		if err := discardReply(r); err != nil {
+			return fmt.Errorf("redis: COMMAND INFO entry: %w", err)
+		}
+	}
+	if elementN > 9 {
+		info.Subcommands, err = readCommandInfos(r)
+		if err != nil {
+			return fmt.Errorf("redis: COMMAND INFO subcommands: %w", err)
+		}
+	}
+	// skip any future extensions
+	for ; elementN > 10; elementN-- {
+		if err := discardReply(r); err != nil {
+			return fmt.Errorf("redis: COMMAND INFO entry: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+package redis
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// SkipOnUnknownCommand skips the test when the server lacks support.
+func skipOnUnknownCommand(t *testing.T, err error) {
+	t.Helper()
+	var e ServerError
+	if errors.As(err, &e) && strings.HasPrefix(string(e), "ERR unknown") {
+		t.Skip("command not supported by server:", err)
+	}
+}
+
+func TestClientPause(t *testing.T) {
+	err := testClient.CLIENTPAUSE(10*time.Millisecond, true)
+	skipOnUnknownCommand(t, err)
+	if err != nil {
+		t.Fatal("CLIENT PAUSE 10 WRITE error:", err)
+	}
+
+	// reads pass during a write pause
+	if _, err := testClient.GET("arbitrary"); err != nil {
+		t.Error("GET during pause error:", err)
+	}
+
+	if err := testClient.CLIENTUNPAUSE(); err != nil {
+		t.Error("CLIENT UNPAUSE error:", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.DB = 2
+	config.Name = "redis-test"
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	key := randomKey("test")
+	if err := c.SET(key, "v"); err != nil {
+		t.Fatalf(`SET %q "v" error: %s`, key, err)
+	}
+
+	err := c.RESET()
+	skipOnUnknownCommand(t, err)
+	if err != nil {
+		t.Fatal("RESET error:", err)
+	}
+
+	// SELECT must apply again
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q after RESET error: %s", key, err)
+	} else if v != "v" {
+		t.Errorf("GET %q after RESET got %q, want \"v\"", key, v)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient[string, string](testClient.ClientConfig)
+	defer c.Close()
+
+	key := randomKey("test")
+	if err := c.SET(key, "0"); err != nil {
+		t.Fatalf(`SET %q "0" error: %s`, key, err)
+	}
+	if err := c.SELECT(3); err != nil {
+		t.Fatal("SELECT 3 error:", err)
+	}
+	if c.DB != 3 {
+		t.Errorf("got DB %d after SELECT 3", c.DB)
+	}
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if v != "" {
+		t.Errorf("GET %q got %q from database 0, want empty string", key, v)
+	}
+	if err := c.SET(key, "3"); err != nil {
+		t.Fatalf(`SET %q "3" error: %s`, key, err)
+	}
+
+	// break connection
+	conn := <-c.connSem
+	if conn.Conn != nil {
+		conn.Close()
+	}
+	c.connSem <- conn
+
+	// reconnect must SELECT 3 again
+	var v string
+	var err error
+	for i := 0; i < 3; i++ {
+		v, err = c.GET(key)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Errorf("GET %q after reconnect error: %s", key, err)
+	} else if v != "3" {
+		t.Errorf("GET %q after reconnect got %q, want \"3\"", key, v)
+	}
+
+	if err := c.SELECT(-1); err == nil {
+		t.Error("SELECT -1 got no error")
+	} else if c.DB != 3 {
+		t.Errorf("got DB %d after failed SELECT, want 3", c.DB)
+	}
+}
+
+func TestSelectBlocking(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.BlockingPoolSize = 1
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	list := randomKey("list")
+	// launch blocking connection on database 0
+	if _, _, err := c.BLPOP(10*time.Millisecond, list); err != nil {
+		t.Fatal("BLPOP error:", err)
+	}
+
+	if err := c.SELECT(5); err != nil {
+		t.Fatal("SELECT 5 error:", err)
+	}
+	if _, err := c.RPUSH(list, "5"); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if _, v, err := c.BLPOP(time.Second, list); err != nil {
+		t.Error("BLPOP after SELECT error:", err)
+	} else if v != "5" {
+		t.Errorf("BLPOP after SELECT got %q, want \"5\" from database 5", v)
+	}
+}
+
+func TestSelectPoolFailure(t *testing.T) {
+	t.Parallel()
+
+	// first SELECT fails
+	var selectCount int32
+	addr := fakeReplica(t, func(args []string) string {
+		if args[0] == "SELECT" && atomic.AddInt32(&selectCount, 1) == 1 {
+			return "-ERR test failure\r\n"
+		}
+		return "+OK\r\n"
+	})
+	c := NewClient[string, string](ClientConfig{Addr: addr, PoolSize: 2})
+	defer c.Close()
+
+	if err := c.SELECT(5); err == nil {
+		t.Error("SELECT got no error")
+	}
+	if c.DB != 5 {
+		t.Errorf("got DB %d after partial success, want 5", c.DB)
+	}
+	// one failed, one switched, and one on reconnect
+	if n := atomic.LoadInt32(&selectCount); n != 3 {
+		t.Errorf("got %d SELECT commands, want 3", n)
+	}
+}
+
+func TestSwapDB(t *testing.T) {
+	t.Parallel()
+
+	config := testClient.ClientConfig
+	config.DB = 14
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	key := randomKey("test")
+	if err := c.SET(key, "blue"); err != nil {
+		t.Fatalf(`SET %q "blue" error: %s`, key, err)
+	}
+
+	err := c.SWAPDB(14, 15)
+	skipOnUnknownCommand(t, err)
+	if err != nil {
+		t.Fatal("SWAPDB 14 15 error:", err)
+	}
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if v != "" {
+		t.Errorf("GET %q got %q after swap, want empty string", key, v)
+	}
+
+	if err := c.SWAPDB(14, 15); err != nil {
+		t.Fatal("SWAPDB 14 15 error:", err)
+	}
+	if v, err := c.GET(key); err != nil {
+		t.Errorf("GET %q error: %s", key, err)
+	} else if v != "blue" {
+		t.Errorf(`GET %q got %q after swap back, want "blue"`, key, v)
+	}
+}
+
+func TestWait(t *testing.T) {
+	key := randomKey("test")
+	if err := testClient.SET(key, "value"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	n, err := testClient.WAIT(0, 100*time.Millisecond)
+	skipOnUnknownCommand(t, err)
+	if err != nil {
+		t.Fatal("WAIT 0 100 error:", err)
+	}
+	if n < 0 {
+		t.Errorf("WAIT 0 100 got %d replicas", n)
+	}
+}
+
+func TestReplicaOfNoOne(t *testing.T) {
+	// no-op on a primary
+	err := testClient.REPLICAOFNOONE()
+	skipOnUnknownCommand(t, err)
+	if err != nil {
+		t.Fatal("REPLICAOF NO ONE error:", err)
+	}
+}
+
+func TestPingEcho(t *testing.T) {
+	t.Parallel()
+
+	if err := testClient.PING(); err != nil {
+		t.Error("PING error:", err)
+	}
+	if got, err := testClient.PINGWithMessage("hello"); err != nil {
+		t.Error("PING hello error:", err)
+	} else if got != "hello" {
+		t.Errorf(`PING hello got %q, want "hello"`, got)
+	}
+	if got, err := testClient.ECHO("x\r\ny"); err != nil {
+		t.Error("ECHO error:", err)
+	} else if got != "x\r\ny" {
+		t.Errorf(`ECHO got %q, want "x\r\ny"`, got)
+	}
+}
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	got, err := testClient.TIME()
+	if err != nil {
+		t.Fatal("TIME error:", err)
+	}
+	if d := time.Since(got); d > time.Minute || d < -time.Minute {
+		t.Errorf("TIME got %s, which is %s off from the local clock", got, d)
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	info := parseInfo("# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\nuptime_in_seconds:42\r\n\r\n# Memory\r\nused_memory:1048576\r\nmaxmemory_policy:allkeys-lru\r\nmem_fragmentation_ratio:1.25\r\n\r\n# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:up\r\n\r\n# Keyspace\r\ndb0:keys=12,expires=3,avg_ttl=1500\r\ndb7:keys=1,expires=0,avg_ttl=0\r\n")
+	if info.Version != "7.2.4" || info.Mode != "standalone" || info.UptimeSeconds != 42 {
+		t.Errorf("got server %q %q %d", info.Version, info.Mode, info.UptimeSeconds)
+	}
+	if info.UsedMemory != 1048576 || info.MaxMemoryPolicy != "allkeys-lru" || info.MemFragmentationRatio != 1.25 {
+		t.Errorf("got memory %d %q %f", info.UsedMemory, info.MaxMemoryPolicy, info.MemFragmentationRatio)
+	}
+	if info.Role != "slave" || info.PrimaryHost != "10.0.0.1" || info.PrimaryPort != 6379 || info.PrimaryLinkStatus != "up" {
+		t.Errorf("got replication %q %q %d %q", info.Role, info.PrimaryHost, info.PrimaryPort, info.PrimaryLinkStatus)
+	}
+	want := map[int64]KeyspaceInfo{
+		0: {Keys: 12, Expires: 3, AvgTTL: 1500 * time.Millisecond},
+		7: {Keys: 1},
+	}
+	if len(info.Keyspace) != len(want) || info.Keyspace[0] != want[0] || info.Keyspace[7] != want[7] {
+		t.Errorf("got keyspace %+v, want %+v", info.Keyspace, want)
+	}
+	if got := info.Fields["db0"]; got != "keys=12,expires=3,avg_ttl=1500" {
+		t.Errorf("got db0 field %q", got)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	t.Parallel()
+
+	info, err := testClient.INFO("clients")
+	if err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("INFO error:", err)
+	}
+	if info.ConnectedClients < 1 {
+		t.Errorf("got %d connected clients, want 1 or more", info.ConnectedClients)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	if err := testClient.CONFIGSET([]string{"a", "b"}, []string{"1"}); err == nil {
+		t.Error("CONFIG SET with 2 names and 1 value got no error")
+	}
+
+	params, err := testClient.CONFIGGET("maxmemory*")
+	if err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("CONFIG GET error:", err)
+	}
+	policy, ok := params["maxmemory-policy"]
+	if !ok {
+		t.Fatalf("CONFIG GET got %q, want a maxmemory-policy", params)
+	}
+	// apply current value
+	if err := testClient.CONFIGSET([]string{"maxmemory-policy"}, []string{policy}); err != nil {
+		t.Error("CONFIG SET error:", err)
+	}
+	if err := testClient.CONFIGRESETSTAT(); err != nil {
+		t.Error("CONFIG RESETSTAT error:", err)
+	}
+}
+
+func TestReadSlowLog(t *testing.T) {
+	const reply = "*2\r\n" +
+		"*6\r\n:14\r\n:1309448221\r\n:15\r\n*2\r\n$4\r\nPING\r\n$1\r\nx\r\n$15\r\n127.0.0.1:58217\r\n$6\r\nworker\r\n" +
+		"*4\r\n:13\r\n:1309448128\r\n:30\r\n*1\r\n$4\r\nINFO\r\n" +
+		"+OK\r\n"
+	r := testReader(reply)
+	entries, err := readSlowLog(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	want := []SlowLogEntry{
+		{ID: 14, Time: time.Unix(1309448221, 0), Duration: 15 * time.Microsecond, Args: []string{"PING", "x"}, ClientAddr: "127.0.0.1:58217", ClientName: "worker"},
+		{ID: 13, Time: time.Unix(1309448128, 0), Duration: 30 * time.Microsecond, Args: []string{"INFO"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %+v\nwant %+v", entries, want)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after SLOWLOG GET reply got error:", err)
+	}
+}
+
+func TestSlowLog(t *testing.T) {
+	t.Parallel()
+
+	n, err := testClient.SLOWLOGLEN()
+	if err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("SLOWLOG LEN error:", err)
+	}
+	entries, err := testClient.SLOWLOGGET(-1)
+	if err != nil {
+		t.Fatal("SLOWLOG GET error:", err)
+	}
+	if int64(len(entries)) < n {
+		t.Errorf("SLOWLOG GET got %d entries, want %d or more", len(entries), n)
+	}
+}
+
+func TestReadMemoryStats(t *testing.T) {
+	const reply = "*12\r\n" +
+		"$14\r\npeak.allocated\r\n:1048576\r\n" +
+		"$15\r\ntotal.allocated\r\n:524288\r\n" +
+		"$4\r\ndb.0\r\n*4\r\n$23\r\noverhead.hashtable.main\r\n:72\r\n$26\r\noverhead.hashtable.expires\r\n:32\r\n" +
+		"$18\r\ndataset.percentage\r\n$17\r\n39.84710693359375\r\n" +
+		"$13\r\nfragmentation\r\n$9\r\n1.5673981\r\n" +
+		"$10\r\nkeys.count\r\n:7\r\n" +
+		"+OK\r\n"
+	r := testReader(reply)
+	stats, err := readMemoryStats(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	if stats.PeakAllocated != 1048576 || stats.TotalAllocated != 524288 || stats.KeysCount != 7 {
+		t.Errorf("got peak %d, total %d and %d keys, want 1048576, 524288 and 7", stats.PeakAllocated, stats.TotalAllocated, stats.KeysCount)
+	}
+	if stats.DatasetPercentage != 39.84710693359375 || stats.Fragmentation != 1.5673981 {
+		t.Errorf("got dataset percentage %g and fragmentation %g, want 39.84710693359375 and 1.5673981", stats.DatasetPercentage, stats.Fragmentation)
+	}
+	if want := (MemoryDBStats{OverheadMain: 72, OverheadExpires: 32}); stats.DBs[0] != want {
+		t.Errorf("got database 0 %+v, want %+v", stats.DBs[0], want)
+	}
+	if got := stats.Fields["fragmentation"]; got != "1.5673981" {
+		t.Errorf("got fragmentation field %q, want %q", got, "1.5673981")
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after MEMORY STATS reply got error:", err)
+	}
+}
+
+func TestMemoryUsage(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test")
+
+	if n, err := testClient.MEMORYUSAGE(key, -1); err != nil {
+		skipOnUnknownCommand(t, err)
+		t.Fatal("MEMORY USAGE error:", err)
+	} else if n != 0 {
+		t.Errorf("MEMORY USAGE on absent Key got %d, want 0", n)
+	}
+
+	if err := testClient.SET(key, "value"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if n, err := testClient.MEMORYUSAGE(key, -1); err != nil {
+		t.Error("MEMORY USAGE error:", err)
+	} else if n <= 0 {
+		t.Errorf("MEMORY USAGE got %d, want a positive number of bytes", n)
+	}
+}
+
+func TestReadCommandInfos(t *testing.T) {
+	const reply = "*3\r\n" +
+		// Redis 6 entry
+		"*7\r\n$3\r\nget\r\n:2\r\n*2\r\n+readonly\r\n+fast\r\n:1\r\n:1\r\n:1\r\n*3\r\n+@read\r\n+@string\r\n+@fast\r\n" +
+		// unknown command
+		"*-1\r\n" +
+		// Redis 7 entry with a subcommand
+		"*10\r\n$6\r\nmemory\r\n:-2\r\n*0\r\n:0\r\n:0\r\n:0\r\n*1\r\n+@slow\r\n*0\r\n*0\r\n" +
+		"*1\r\n*10\r\n$12\r\nmemory|usage\r\n:-3\r\n*1\r\n+readonly\r\n:2\r\n:2\r\n:1\r\n*2\r\n+@read\r\n+@slow\r\n*0\r\n*1\r\n*2\r\n$5\r\nflags\r\n*1\r\n+RO\r\n*0\r\n" +
+		"+OK\r\n"
+	r := testReader(reply)
+	infos, err := readCommandInfos(r)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	want := []CommandInfo{
+		{Name: "get", Arity: 2, Flags: []string{"readonly", "fast"}, FirstKey: 1, LastKey: 1, Step: 1, ACLCategories: []string{"@read", "@string", "@fast"}},
+		{},
+		{Name: "memory", Arity: -2, ACLCategories: []string{"@slow"}, Subcommands: []CommandInfo{
+			{Name: "memory|usage", Arity: -3, Flags: []string{"readonly"}, FirstKey: 2, LastKey: 2, Step: 1, ACLCategories: []string{"@read", "@slow"}},
+		}},
+	}
+	if !reflect.DeepEqual(infos, want) {
+		t.Errorf("got %+v\nwant %+v", infos, want)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("OK after COMMAND INFO reply got error:", err)
+	}
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrNoCommand rejects execution without any arguments.
+var errNoCommand = errors.New("redis: no command")
+
+// ConnSetup executes commands on a new connection, before it enters service,
+// as provided to ClientConfig OnConnect. A ConnSetup is valid until return of
+// the function only.
+type ConnSetup struct {
+	conn   net.Conn
+	reader *connReader
+}
+
+// Exchange sends a command, and then it returns the reader for its reply.
+func (s *ConnSetup) exchange(args []string) (*connReader, error) {
+	if len(args) == 0 {
+		return nil, errNoCommand
+	}
+	r := requestSize("", len(args))
+	r.buf = appendCRLFAndList(r.buf, args)
+	defer r.free()
+	if _, err := s.conn.Write(r.buf); err != nil {
+		return nil, err
+	}
+	return s.reader, nil
+}
+
+// OK executes a command with an "OK" reply, e.g., OK("CLIENT", "TRACKING", "ON").
+func (s *ConnSetup) OK(args ...string) error {
+	r, err := s.exchange(args)
+	if err != nil {
+		return err
+	}
+	return readOK(r)
+}
+
+// Integer executes a command with an integer reply.
+func (s *ConnSetup) Integer(args ...string) (int64, error) {
+	r, err := s.exchange(args)
+	if err != nil {
+		return 0, err
+	}
+	return readInteger(r)
+}
+
+// String executes a command with a bulk string reply. The return is empty for
+// a null reply.
+func (s *ConnSetup) String(args ...string) (string, error) {
+	r, err := s.exchange(args)
+	if err != nil {
+		return "", err
+	}
+	v, err := readBulk[string](r)
+	if err == errNull {
+		err = nil
+	}
+	return v, err
+}
+
+// Array executes a command with an array reply of bulk strings.
+func (s *ConnSetup) Array(args ...string) ([]string, error) {
+	r, err := s.exchange(args)
+	if err != nil {
+		return nil, err
+	}
+	return readArray[string](r)
+}
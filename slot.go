@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// SlotCount is the number of hash slots in Redis Cluster.
+const SlotCount = 16384
+
+// HashSlot returns the Redis Cluster slot of a Key, which is the CRC16 of its
+// hash tag, if any, modulo SlotCount.
+func HashSlot[Key String](k Key) int {
+	tag := HashTag(k)
+	var crc uint16
+	for i := 0; i < len(tag); i++ {
+		crc ^= uint16(tag[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return int(crc) % SlotCount
+}
+
+// HashTag returns the part of a Key which determines its hash slot. That is the
+// content of the first pair of curly braces, e.g., "user42" for Key
+// "{user42}:cart", when not empty. Otherwise, the return is k as a whole.
+func HashTag[Key String](k Key) Key {
+	for i := 0; i < len(k); i++ {
+		if k[i] != '{' {
+			continue
+		}
+		for j := i + 1; j < len(k); j++ {
+			if k[j] == '}' {
+				if j == i+1 {
+					break // empty tag
+				}
+				return k[i+1 : j]
+			}
+		}
+		break
+	}
+	return k
+}
+
+// CrossSlotError rejects Keys from more than one hash slot, as Redis Cluster
+// would with a CROSSSLOT error, yet without any request sent.
+type CrossSlotError struct {
+	Command string // empty for SameSlot
+
+	// The first Key, plus the first Key of another slot.
+	Key1, Key2   string
+	Slot1, Slot2 int
+}
+
+// Error implements the error interface.
+func (e *CrossSlotError) Error() string {
+	if e.Command == "" {
+		return fmt.Sprintf("redis: CROSSSLOT keys %q (slot %d) and %q (slot %d) don't hash to the same slot", e.Key1, e.Slot1, e.Key2, e.Slot2)
+	}
+	return fmt.Sprintf("redis: CROSSSLOT %s keys %q (slot %d) and %q (slot %d) don't hash to the same slot", e.Command, e.Key1, e.Slot1, e.Key2, e.Slot2)
+}
+
+// SameSlot returns a *CrossSlotError when the Keys are not all in the same hash
+// slot, which is a requirement for multi-key commands in Redis Cluster.
+func SameSlot[Key String](keys ...Key) error {
+	if len(keys) < 2 {
+		return nil
+	}
+	slot := HashSlot(keys[0])
+	for _, k := range keys[1:] {
+		if s := HashSlot(k); s != slot {
+			return &CrossSlotError{
+				Key1: string(keys[0]), Slot1: slot,
+				Key2: string(k), Slot2: s,
+			}
+		}
+	}
+	return nil
+}
+
+// CheckSlots applies SameSlot to each command in buf. Commands with unknown Key
+// positions pass as is. A streamed payload at the end of buf is ignored.
+func checkSlots(buf []byte) error {
+	for len(buf) != 0 {
+		// array header
+		i := bytes.IndexByte(buf, '\n')
+		argCount := int(ParseInt(buf[1 : i-1]))
+		buf = buf[i+1:]
+
+		var name string
+		var spec keySpec
+		var first []byte
+		slot := -1
+		for arg := 0; arg < argCount; arg++ {
+			i := bytes.IndexByte(buf, '\n')
+			size := int(ParseInt(buf[1 : i-1]))
+			if len(buf) < i+1+size+2 {
+				return nil // streamed payload
+			}
+			value := buf[i+1 : i+1+size]
+			buf = buf[i+1+size+2:]
+
+			if arg == 0 {
+				name = string(bytes.ToUpper(value))
+				spec = keySpecs[name]
+				continue
+			}
+			if !spec.isKey(arg, argCount) {
+				continue
+			}
+			s := HashSlot(value)
+			switch {
+			case slot < 0:
+				first, slot = value, s
+			case s != slot:
+				return &CrossSlotError{
+					Command: name,
+					Key1:    string(first), Slot1: slot,
+					Key2: string(value), Slot2: s,
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHashSlot(t *testing.T) {
+	golden := []struct {
+		key  string
+		tag  string
+		slot int
+	}{
+		{"", "", 0},
+		{"123456789", "123456789", 12739},
+		{"foo", "foo", 12182},
+		{"bar", "bar", 5061},
+		{"{bar}:x", "bar", 5061},
+		{"foo{}{bar}", "foo{}{bar}", HashSlot("foo{}{bar}")},
+		{"foo{{bar}}zap", "{bar", HashSlot("{bar")},
+		{"foo{bar}{zap}", "bar", 5061},
+	}
+	for _, gold := range golden {
+		if got := HashTag(gold.key); got != gold.tag {
+			t.Errorf("HashTag(%q) got %q, want %q", gold.key, got, gold.tag)
+		}
+		if got := HashSlot([]byte(gold.key)); got != gold.slot {
+			t.Errorf("HashSlot(%q) got %d, want %d", gold.key, got, gold.slot)
+		}
+	}
+}
+
+func TestCrossSlot(t *testing.T) {
+	if err := SameSlot("{user42}:cart", "{user42}:orders"); err != nil {
+		t.Error("SameSlot with hash tag got error:", err)
+	}
+	var e *CrossSlotError
+	if err := SameSlot("foo", "foo", "bar"); !errors.As(err, &e) {
+		t.Errorf("SameSlot got error %v, want a *CrossSlotError", err)
+	} else if e.Key1 != "foo" || e.Slot1 != 12182 || e.Key2 != "bar" || e.Slot2 != 5061 {
+		t.Errorf("SameSlot got %+v", *e)
+	}
+
+	config := ClientConfig{Addr: testClient.Addr, CrossSlotCheck: true}
+	c := NewClient[string, string](config)
+	defer c.Close()
+	if _, err := c.MGET("{a}1", "{a}2"); err != nil {
+		t.Error("MGET within slot got error:", err)
+	}
+	if _, err := c.MGET("foo", "bar"); !errors.As(err, &e) {
+		t.Errorf("MGET across slots got error %v, want a *CrossSlotError", err)
+	} else if e.Command != "MGET" {
+		t.Errorf("got command %q, want MGET", e.Command)
+	}
+	if err := c.MSET([]string{"foo", "{foo}x"}, []string{"1", "2"}); err != nil {
+		t.Error("MSET within slot got error:", err)
+	}
+	if n := c.Stats().CommandsSent; n != 2 {
+		t.Errorf("got %d commands sent, want 2", n)
+	}
+}
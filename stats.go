@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of Client metrics.
+type Stats struct {
+	// Number of requests written. Each command method counts as one.
+	CommandsSent int64
+	// Number of network writes for requests, which is less than
+	// CommandsSent with ClientConfig CoalesceWrites only.
+	Writes int64
+
+	// Network traffic, including connection establishment.
+	BytesWritten int64
+	BytesRead    int64
+
+	// Number of connects after the first one.
+	Reconnects int64
+
+	// Number of commands awaiting their turn for a response in the
+	// pipeline. The command currently reading is not included.
+	Pending int
+
+	// The most recent failure on connection establishment, if any.
+	LastConnectErr     error
+	LastConnectErrTime time.Time
+
+	// Response times of commands, including any wait in the pipeline,
+	// counted per LatencyBuckets. The last count is for any overflow.
+	// Commands without a response, e.g., due to connection loss, are
+	// not included.
+	LatencyCounts [len(LatencyBuckets) + 1]int64
+	// The total of all response times in LatencyCounts.
+	LatencySum time.Duration
+}
+
+// LatencyBuckets has the upper boundaries (inclusive) of the response-time
+// histogram in Stats, in ascending order.
+var LatencyBuckets = [...]time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// Stats returns the current metrics. The counters are shared with any views.
+// Pools report the total of all connections, including any replicas.
+func (c *Client[Key, Value]) Stats() Stats {
+	var stats Stats
+	for _, m := range c.pool() {
+		m.addStats(&stats)
+	}
+	for _, r := range c.readers {
+		for _, m := range r.pool() {
+			m.addStats(&stats)
+		}
+	}
+	return stats
+}
+
+// AddStats adds the metrics of p to stats.
+func (p *pipeline) addStats(stats *Stats) {
+	stats.CommandsSent += atomic.LoadInt64(&p.commandsSent)
+	stats.Writes += atomic.LoadInt64(&p.writeCount)
+	stats.BytesWritten += atomic.LoadInt64(&p.bytesWritten)
+	stats.BytesRead += atomic.LoadInt64(&p.bytesRead)
+	stats.Pending += len(p.readQueue)
+	if n := atomic.LoadInt64(&p.connectCount); n > 1 {
+		stats.Reconnects += n - 1
+	}
+	if f, ok := p.lastConnectFailure.Load().(*connectFailure); ok && f.time.After(stats.LastConnectErrTime) {
+		stats.LastConnectErr = f.err
+		stats.LastConnectErrTime = f.time
+	}
+	for i := range stats.LatencyCounts {
+		stats.LatencyCounts[i] += atomic.LoadInt64(&p.latencyCounts[i])
+	}
+	stats.LatencySum += time.Duration(atomic.LoadInt64(&p.latencySum))
+}
+
+// ObserveLatency adds a response time to the histogram.
+func (p *pipeline) observeLatency(d time.Duration) {
+	i := 0
+	for i < len(LatencyBuckets) && d > LatencyBuckets[i] {
+		i++
+	}
+	atomic.AddInt64(&p.latencyCounts[i], 1)
+	atomic.AddInt64(&p.latencySum, int64(d))
+}
+
+// ListenerStats is a snapshot of Listener metrics.
+type ListenerStats struct {
+	// Number of messages received, including the ones that exceed the
+	// BufferSize.
+	MessagesReceived int64
+
+	// Network traffic, including connection establishment.
+	BytesWritten int64
+	BytesRead    int64
+
+	// Number of connects after the first one.
+	Reconnects int64
+
+	// Number of channels subscribed, including any pending confirmation.
+	Subscriptions int
+
+	// Number of patterns subscribed, including any pending confirmation.
+	PatternSubscriptions int
+
+	// The most recent failure on connection establishment, if any.
+	LastConnectErr     error
+	LastConnectErrTime time.Time
+}
+
+// Stats returns the current metrics.
+func (l *Listener) Stats() ListenerStats {
+	stats := ListenerStats{
+		MessagesReceived: atomic.LoadInt64(&l.messageCount),
+		BytesWritten:     atomic.LoadInt64(&l.bytesWritten),
+		BytesRead:        atomic.LoadInt64(&l.bytesRead),
+	}
+	if n := atomic.LoadInt64(&l.connectCount); n > 1 {
+		stats.Reconnects = n - 1
+	}
+	l.mutex.Lock()
+	stats.Subscriptions = len(l.subs)
+	stats.PatternSubscriptions = len(l.psubs)
+	l.mutex.Unlock()
+	if f, ok := l.lastConnectFailure.Load().(*connectFailure); ok {
+		stats.LastConnectErr = f.err
+		stats.LastConnectErrTime = f.time
+	}
+	return stats
+}
+
+type connectFailure struct {
+	err  error
+	time time.Time
+}
+
+// IOCounters track network traffic with atomic access.
+type ioCounters struct {
+	bytesWritten int64
+	bytesRead    int64
+}
+
+// CountingConn updates ioCounters on each read and write.
+type countingConn struct {
+	net.Conn
+	*ioCounters
+}
+
+// Read implements io.Reader.
+func (c countingConn) Read(p []byte) (n int, err error) {
+	n, err = c.Conn.Read(p)
+	atomic.AddInt64(&c.bytesRead, int64(n))
+	return
+}
+
+// Write implements io.Writer.
+func (c countingConn) Write(p []byte) (n int, err error) {
+	n, err = c.Conn.Write(p)
+	atomic.AddInt64(&c.bytesWritten, int64(n))
+	return
+}
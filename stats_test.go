@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient[string, string](testClient.ClientConfig)
+	defer c.Close()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GET("arbitrary"); err != nil {
+			t.Fatal("GET error:", err)
+		}
+	}
+	stats := c.Stats()
+	if stats.CommandsSent != 3 {
+		t.Errorf("got %d commands sent, want 3", stats.CommandsSent)
+	}
+	if stats.Writes != 3 {
+		t.Errorf("got %d writes, want 3", stats.Writes)
+	}
+	if stats.BytesWritten < 3*int64(len("*2\r\n$3\r\nGET\r\n$9\r\narbitrary\r\n")) {
+		t.Errorf("got %d bytes written", stats.BytesWritten)
+	}
+	if stats.BytesRead < 3*int64(len("$-1\r\n")) {
+		t.Errorf("got %d bytes read", stats.BytesRead)
+	}
+	if stats.Reconnects != 0 || stats.Pending != 0 || stats.LastConnectErr != nil {
+		t.Errorf("got stats %+v, want no reconnects, pending nor connect error", stats)
+	}
+	var latencyN int64
+	for _, n := range stats.LatencyCounts {
+		latencyN += n
+	}
+	if latencyN != 3 || stats.LatencySum <= 0 {
+		t.Errorf("got latency counts %d with sum %s, want 3 with a positive sum", stats.LatencyCounts, stats.LatencySum)
+	}
+
+	// break connection
+	conn := <-c.connSem
+	conn.Close()
+	c.connSem <- conn
+	c.GET("arbitrary")
+	if _, err := c.GET("arbitrary"); err != nil {
+		t.Fatal("GET after reconnect error:", err)
+	}
+	if got := c.Stats().Reconnects; got != 1 {
+		t.Errorf("got %d reconnects, want 1", got)
+	}
+
+	c = NewClient[string, string](ClientConfig{Addr: "doesnotexist.example.com"})
+	defer c.Close()
+	if _, err := c.GET("arbitrary"); err == nil {
+		t.Fatal("GET on unknown host got no error")
+	}
+	if stats := c.Stats(); stats.LastConnectErr == nil || stats.LastConnectErrTime.IsZero() {
+		t.Errorf("got stats %+v, want a connect error", stats)
+	}
+}
@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrStructPointer rejects destinations other than a pointer to a struct.
+var errStructPointer = errors.New("redis: hash destination must be a pointer to a struct")
+
+// HashField is a struct field mapped to a hash field.
+type hashField struct {
+	name  string // in hash
+	index []int  // in struct
+}
+
+// HashFields returns the mapping of a struct type. The hash field names come
+// from the "redis" tag, e.g., `redis:"name"`, and they default to the name of
+// the struct field. Tag "-" omits the field, just as unexported fields are.
+// Embedded structs are not flattened.
+func hashFields(t reflect.Type) []hashField {
+	fields := make([]hashField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("redis"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, hashField{name: name, index: f.Index})
+	}
+	return fields
+}
+
+// HSETStruct executes <https://redis.io/commands/hset> with each field from
+// struct v, or from the struct v points to. Strings and byte slices apply as
+// is, booleans as "1" or "0", and numbers in decimal notation. The return is
+// the number of fields created. Redis version 4 or later is required.
+func (c *Client[Key, Value]) HSETStruct(k Key, v any) (newFields int64, err error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("redis: HSET of %T; want a struct", v)
+	}
+	fields := hashFields(rv.Type())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("redis: HSET of %T without fields", v)
+	}
+
+	names := make([]string, len(fields))
+	values := make([][]byte, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+		values[i], err = formatHashField(rv.FieldByIndex(f.index))
+		if err != nil {
+			return 0, fmt.Errorf("redis: HSET field %q: %w", f.name, err)
+		}
+	}
+	r, err := requestWithStringAndMap("\r\n$4\r\nHSET\r\n$", k, names, values)
+	if err != nil {
+		return 0, err
+	}
+	return c.commandInteger(r)
+}
+
+// HGETALLStruct executes <https://redis.io/commands/hgetall> with each field
+// that matches a field of the struct dst points to. See HSETStruct for the
+// mapping. Struct fields which are absent in the hash are not modified.
+func (c *Client[Key, Value]) HGETALLStruct(k Key, dst any) error {
+	rv, err := structDst(dst)
+	if err != nil {
+		return err
+	}
+	names, values, err := c.HGETALL(k)
+	if err != nil {
+		return err
+	}
+
+	fields := hashFields(rv.Type())
+	for i, name := range names {
+		for _, f := range fields {
+			if f.name != string(name) {
+				continue
+			}
+			if err := parseHashField(rv.FieldByIndex(f.index), []byte(values[i])); err != nil {
+				return fmt.Errorf("redis: HGETALL field %q: %w", f.name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// HMGETStruct executes <https://redis.io/commands/hmget> with the fields of
+// the struct dst points to. See HSETStruct for the mapping. Struct fields with
+// an empty value, including those absent in the hash, are not modified.
+func (c *Client[Key, Value]) HMGETStruct(k Key, dst any) error {
+	rv, err := structDst(dst)
+	if err != nil {
+		return err
+	}
+	fields := hashFields(rv.Type())
+	if len(fields) == 0 {
+		return nil
+	}
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+
+	values, err := c.commandArray(requestWithStringAndList("\r\n$5\r\nHMGET\r\n$", k, names).idempotent())
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		if err := parseHashField(rv.FieldByIndex(fields[i].index), []byte(v)); err != nil {
+			return fmt.Errorf("redis: HMGET field %q: %w", fields[i].name, err)
+		}
+	}
+	return nil
+}
+
+func structDst(dst any) (reflect.Value, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errStructPointer
+	}
+	return rv.Elem(), nil
+}
+
+func formatHashField(v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return []byte{'1'}, nil
+		}
+		return []byte{'0'}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(nil, v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.AppendFloat(nil, v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.AppendFloat(nil, v.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseHashField(v reflect.Value, b []byte) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(string(b))
+		return nil
+	case reflect.Bool:
+		switch string(b) {
+		case "1":
+			v.SetBool(true)
+			return nil
+		case "0":
+			v.SetBool(false)
+			return nil
+		}
+		t, err := strconv.ParseBool(string(b))
+		v.SetBool(t)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(b), 10, v.Type().Bits())
+		v.SetInt(n)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(string(b), 10, v.Type().Bits())
+		v.SetUint(n)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(b), v.Type().Bits())
+		v.SetFloat(f)
+		return err
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
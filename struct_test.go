@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHashStruct(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		Name    string  `redis:"name"`
+		Age     uint8   `redis:"age"`
+		Score   float64 `redis:"score"`
+		Admin   bool    `redis:"admin"`
+		Avatar  []byte
+		Ignored string `redis:"-"`
+		private int
+	}
+	key := randomKey("hash")
+	in := User{Name: "Ann", Age: 42, Score: -0.5, Admin: true, Avatar: []byte{0, 1}, Ignored: "x"}
+	if n, err := testClient.HSETStruct(key, &in); err != nil {
+		t.Fatal("HSET error:", err)
+	} else if n != 5 {
+		t.Errorf("HSET got %d new fields, want 5", n)
+	}
+
+	want := in
+	want.Ignored = ""
+	var out User
+	if err := testClient.HGETALLStruct(key, &out); err != nil {
+		t.Error("HGETALL error:", err)
+	} else if !reflect.DeepEqual(out, want) {
+		t.Errorf("HGETALL got %+v, want %+v", out, want)
+	}
+
+	out = User{Ignored: "y"}
+	want.Ignored = "y"
+	if err := testClient.HMGETStruct(key, &out); err != nil {
+		t.Error("HMGET error:", err)
+	} else if !reflect.DeepEqual(out, want) {
+		t.Errorf("HMGET got %+v, want %+v", out, want)
+	}
+
+	if err := testClient.HGETALLStruct(key, out); err == nil {
+		t.Error("HGETALL into non-pointer got no error")
+	}
+	if _, err := testClient.HSET(key, "age", "-1"); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if err := testClient.HMGETStruct(key, &out); err == nil {
+		t.Error("HMGET of negative age got no error")
+	}
+}
@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// TraceArgMax is the number of bytes shown per argument in request traces.
+const traceArgMax = 64
+
+// TraceConn writes the protocol exchange in a human-readable format.
+// Requests show with their arguments, and replies show with their
+// header lines only, i.e., without the payload of bulk strings.
+type traceConn struct {
+	net.Conn
+	w      io.Writer
+	prefix string // remote address
+
+	// Lines from both directions are written as a whole.
+	mutex sync.Mutex
+
+	// Reply parse state is owned by the read routine.
+	line []byte // pending header line
+	skip int    // remaining payload bytes, including CRLF
+}
+
+func newTraceConn(conn net.Conn, w io.Writer) *traceConn {
+	return &traceConn{
+		Conn:   conn,
+		w:      w,
+		prefix: conn.RemoteAddr().String(),
+	}
+}
+
+// Read implements io.Reader.
+func (c *traceConn) Read(p []byte) (n int, err error) {
+	n, err = c.Conn.Read(p)
+	c.traceReplies(p[:n])
+	return
+}
+
+// Write implements io.Writer.
+func (c *traceConn) Write(p []byte) (n int, err error) {
+	c.traceRequests(p)
+	return c.Conn.Write(p)
+}
+
+func (c *traceConn) traceReplies(p []byte) {
+	var out []byte
+	for len(p) != 0 {
+		if c.skip != 0 {
+			n := c.skip
+			if n > len(p) {
+				n = len(p)
+			}
+			c.skip -= n
+			p = p[n:]
+			continue
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			c.line = append(c.line, p...)
+			break
+		}
+		c.line = append(c.line, p[:i+1]...)
+		p = p[i+1:]
+
+		line := bytes.TrimRight(c.line, "\r\n")
+		out = append(out, c.prefix...)
+		out = append(out, " < "...)
+		out = append(out, line...)
+		out = append(out, '\n')
+
+		if len(line) > 1 {
+			switch line[0] {
+			case '$', '=', '!':
+				size, err := strconv.Atoi(string(line[1:]))
+				if err == nil && size >= 0 {
+					c.skip = size + 2
+				}
+			}
+		}
+		c.line = c.line[:0]
+	}
+
+	if len(out) != 0 {
+		c.mutex.Lock()
+		c.w.Write(out)
+		c.mutex.Unlock()
+	}
+}
+
+// TraceRequests formats each command in p on a line. The arguments of AUTH
+// are redacted, and so are the credentials of HELLO. Payloads in separate
+// writes, as with SETReader, show with their size only. Malformed commands
+// end their line with an ellipsis.
+func (c *traceConn) traceRequests(p []byte) {
+	var out []byte
+requests:
+	for len(p) != 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 3 || p[0] != '*' {
+			break // not a request array
+		}
+		argN, err := strconv.Atoi(string(p[1 : i-1]))
+		if err != nil {
+			break
+		}
+		p = p[i+1:]
+
+		out = append(out, c.prefix...)
+		out = append(out, " >"...)
+		var hello bool
+		var redact int // number of arguments
+		for argI := 0; argI < argN; argI++ {
+			i = bytes.IndexByte(p, '\n')
+			if i < 3 || p[0] != '$' {
+				out = append(out, " …\n"...)
+				break requests
+			}
+			size, err := strconv.Atoi(string(p[1 : i-1]))
+			if err != nil {
+				out = append(out, " …\n"...)
+				break requests
+			}
+			if i+1+size+2 > len(p) {
+				if argI != argN-1 || i+1 != len(p) {
+					out = append(out, " …\n"...)
+					break requests
+				}
+				// payload streams in separate writes
+				out = append(out, " <"...)
+				out = strconv.AppendInt(out, int64(size), 10)
+				out = append(out, " bytes>\n"...)
+				break requests
+			}
+			arg := p[i+1 : i+1+size]
+			p = p[i+1+size+2:]
+
+			switch {
+			case argI == 0:
+				out = append(out, ' ')
+				out = append(out, arg...)
+				switch string(bytes.ToUpper(arg)) {
+				case "AUTH":
+					redact = argN
+				case "HELLO":
+					hello = true
+				}
+			case redact != 0:
+				redact--
+				out = append(out, " <redacted>"...)
+			case len(arg) > traceArgMax:
+				out = append(out, ' ')
+				out = strconv.AppendQuote(out, string(arg[:traceArgMax]))
+				out = append(out, "…"...)
+			default:
+				out = append(out, ' ')
+				out = strconv.AppendQuote(out, string(arg))
+			}
+			if hello && argI != 0 && bytes.EqualFold(arg, []byte("AUTH")) {
+				redact = 2 // username and password
+			}
+		}
+		out = append(out, '\n')
+	}
+
+	if len(out) != 0 {
+		c.mutex.Lock()
+		c.w.Write(out)
+		c.mutex.Unlock()
+	}
+}
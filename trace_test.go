@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTraceRequests(t *testing.T) {
+	var buf bytes.Buffer
+	c := &traceConn{w: &buf, prefix: "node"}
+
+	req := requestWith2Strings("*3\r\n$4\r\nAUTH\r\n$", "user", "secret")
+	defer req.free()
+	req.buf = append(req.buf, "*2\r\n$3\r\nGET\r\n$70\r\n"+strings.Repeat("k", 70)+"\r\n"...)
+	c.traceRequests(req.buf)
+	// streamed payload
+	c.traceRequests([]byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$5\r\n"))
+	c.traceRequests([]byte("hello\r\n"))
+	c.traceRequests([]byte("*6\r\n$5\r\nHELLO\r\n$1\r\n3\r\n$4\r\nauth\r\n$4\r\nuser\r\n$6\r\nsecret\r\n$7\r\nSETNAME\r\n"))
+	// malformed
+	c.traceRequests([]byte("*1\r\n$4\r\nPING\r\n*2\r\n$3\r\nGET\r\nk\r\n"))
+
+	want := "node > AUTH <redacted> <redacted>\n" +
+		`node > GET "` + strings.Repeat("k", traceArgMax) + `"…` + "\n" +
+		`node > SET "k" <5 bytes>` + "\n" +
+		`node > HELLO "3" "auth" <redacted> <redacted> "SETNAME"` + "\n" +
+		"node > PING\n" +
+		"node > GET …\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got trace:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTraceReplies(t *testing.T) {
+	var buf bytes.Buffer
+	c := &traceConn{w: &buf, prefix: "node"}
+
+	const replies = "+OK\r\n*2\r\n$5\r\nhel\nl\r\n:42\r\n$-1\r\n-ERR x\r\n"
+	// feed in small chunks to cover partial lines and payloads
+	for i := 0; i < len(replies); i += 3 {
+		end := i + 3
+		if end > len(replies) {
+			end = len(replies)
+		}
+		c.traceReplies([]byte(replies[i:end]))
+	}
+
+	const want = "node < +OK\nnode < *2\nnode < $5\nnode < :42\nnode < $-1\nnode < -ERR x\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got trace:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	config := testClient.ClientConfig
+	config.Trace = &buf
+	c := NewClient[string, string](config)
+	defer c.Close()
+
+	key := randomKey("test")
+	if _, err := c.GET(key); err != nil {
+		t.Fatal("GET error:", err)
+	}
+
+	got := buf.String()
+	if want := ` > GET "` + key + "\"\n"; !strings.Contains(got, want) {
+		t.Errorf("trace %q does not contain %q", got, want)
+	}
+	if want := " < $-1\n"; !strings.Contains(got, want) && !strings.Contains(got, " < _\n") {
+		t.Errorf("trace %q does not contain %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// UNLINKMatchOptions define the execution of UNLINKMatch.
+type UNLINKMatchOptions struct {
+	// Count hints the number of Keys per SCAN when positive.
+	Count int64
+
+	// BatchSize limits the number of Keys per UNLINK. Zero defaults to
+	// 100.
+	BatchSize int
+
+	// Pipeline limits the number of UNLINK commands in progress. Zero
+	// defaults to 4.
+	Pipeline int
+
+	// RateLimit caps the number of Keys submitted to UNLINK per second
+	// when positive, which spreads the load on the node.
+	RateLimit int
+
+	// ProgressFunc receives the totals after each UNLINK when not nil.
+	// Calls are sequential, and slow or blocking receivers delay the run.
+	ProgressFunc func(UNLINKMatchProgress)
+}
+
+// UNLINKMatchProgress has the totals of an UNLINKMatch in progress.
+type UNLINKMatchProgress struct {
+	Matched int64 // Keys from SCAN, including any duplicates
+	Removed int64 // Keys removed by UNLINK
+}
+
+// UNLINKMatch removes each Key which matches the glob-style pattern, with SCAN
+// iteration, and with UNLINK in batches. The return is the number of Keys
+// removed. An empty pattern is rejected. Use FLUSHDB for all Keys instead.
+// Keys created during the run may remain. The first error stops the run, once
+// the UNLINK commands in progress are done. The context of WithContext stops
+// the run too, including any wait for RateLimit. Views from WithPrefix remove
+// Keys within their namespace only, like SCAN.
+func (c *Client[Key, Value]) UNLINKMatch(pattern Key, o UNLINKMatchOptions) (removed int64, err error) {
+	if len(pattern) == 0 {
+		return 0, errors.New("redis: UNLINKMatch without pattern; use FLUSHDB for all keys")
+	}
+	batchSize := o.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	pipeline := o.Pipeline
+	if pipeline <= 0 {
+		pipeline = 4
+	}
+	var done <-chan struct{}
+	if c.ctx != nil {
+		done = c.ctx.Done()
+	}
+
+	var (
+		mutex    sync.Mutex // guards progress & firstErr
+		progress UNLINKMatchProgress
+		firstErr error
+	)
+	fail := func(err error) {
+		mutex.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mutex.Unlock()
+	}
+	failed := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return firstErr != nil
+	}
+
+	var inProgress sync.WaitGroup
+	slots := make(chan struct{}, pipeline)
+	start := time.Now()
+	var submitted int64
+
+	// Submit launches UNLINK for keys. The return is false on abort.
+	submit := func(keys []Key) bool {
+		if o.RateLimit > 0 {
+			due := start.Add(time.Duration(submitted) * time.Second / time.Duration(o.RateLimit))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+					break
+				case <-done:
+					timer.Stop()
+					fail(c.ctx.Err())
+					return false
+				}
+			}
+		}
+
+		select {
+		case slots <- struct{}{}:
+			break
+		case <-done:
+			fail(c.ctx.Err())
+			return false
+		}
+		submitted += int64(len(keys))
+
+		inProgress.Add(1)
+		go func() {
+			defer inProgress.Done()
+			n, err := c.UNLINKArgs(keys...)
+			<-slots
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			progress.Removed += n
+			if o.ProgressFunc != nil {
+				o.ProgressFunc(progress)
+			}
+		}()
+		return true
+	}
+
+	var batch []Key
+	var cursor uint64
+	for !failed() {
+		next, keys, err := c.SCAN(cursor, pattern, o.Count)
+		if err != nil {
+			fail(err)
+			break
+		}
+		mutex.Lock()
+		progress.Matched += int64(len(keys))
+		mutex.Unlock()
+
+		batch = append(batch, keys...)
+		for len(batch) >= batchSize && submit(batch[:batchSize:batchSize]) {
+			batch = batch[batchSize:]
+		}
+		cursor = next
+		if cursor == 0 {
+			if len(batch) != 0 && !failed() {
+				submit(batch)
+			}
+			break
+		}
+	}
+
+	inProgress.Wait()
+	return progress.Removed, firstErr
+}
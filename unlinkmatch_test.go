@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// SetTestKeys creates n Keys with prefix.
+func setTestKeys(t *testing.T, prefix string, n int) {
+	keys := make([]string, n)
+	values := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	if err := testClient.MSET(keys, values); err != nil {
+		t.Fatal("MSET error:", err)
+	}
+}
+
+func TestUNLINKMatch(t *testing.T) {
+	t.Parallel()
+	prefix := randomKey("unlink") + ":"
+	setTestKeys(t, prefix, 250)
+	other := prefix[:len(prefix)-1]
+	if err := testClient.SET(other, "stays"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+
+	var calls []UNLINKMatchProgress
+	removed, err := testClient.UNLINKMatch(prefix+"*", UNLINKMatchOptions{
+		Count:        100,
+		BatchSize:    40,
+		ProgressFunc: func(p UNLINKMatchProgress) { calls = append(calls, p) },
+	})
+	if err != nil {
+		t.Fatal("UNLINKMatch error:", err)
+	}
+	if removed != 250 {
+		t.Errorf("UNLINKMatch got %d removed, want 250", removed)
+	}
+	if len(calls) != 7 {
+		t.Errorf("got %d progress calls, want 7", len(calls))
+	} else if last := calls[len(calls)-1]; last.Removed != 250 || last.Matched < 250 {
+		t.Errorf("got last progress %+v, want 250 removed", last)
+	}
+
+	_, keys, err := testClient.SCAN(0, prefix+"*", 1000)
+	if err != nil {
+		t.Fatal("SCAN error:", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("got %d keys remaining, want none", len(keys))
+	}
+	if v, err := testClient.GET(other); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "stays" {
+		t.Errorf("GET %q got %q, want \"stays\"", other, v)
+	}
+}
+
+func TestUNLINKMatchRateLimit(t *testing.T) {
+	t.Parallel()
+	prefix := randomKey("unlink") + ":"
+	setTestKeys(t, prefix, 100)
+
+	start := time.Now()
+	removed, err := testClient.UNLINKMatch(prefix+"*", UNLINKMatchOptions{
+		Count:     1000,
+		BatchSize: 25,
+		RateLimit: 500,
+	})
+	if err != nil {
+		t.Fatal("UNLINKMatch error:", err)
+	}
+	if removed != 100 {
+		t.Errorf("UNLINKMatch got %d removed, want 100", removed)
+	}
+	// last batch due after 75 keys at 500 per second
+	if d := time.Since(start); d < 150*time.Millisecond {
+		t.Errorf("UNLINKMatch took %s, want at least 150ms with rate limit", d)
+	}
+
+	// abort on context
+	setTestKeys(t, prefix, 100)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	removed, err = testClient.WithContext(ctx).UNLINKMatch(prefix+"*", UNLINKMatchOptions{
+		Count:     1000,
+		BatchSize: 10,
+		RateLimit: 100,
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("UNLINKMatch with context got error %v, want context.DeadlineExceeded", err)
+	}
+	if removed == 0 || removed >= 100 {
+		t.Errorf("UNLINKMatch with context got %d removed, want a partial run", removed)
+	}
+}
+
+func TestUNLINKMatchPrefix(t *testing.T) {
+	t.Parallel()
+	prefix := randomKey("unlink") + ":"
+	setTestKeys(t, prefix, 10)
+	other := prefix[:len(prefix)-1]
+	if err := testClient.SET(other, "stays"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+
+	removed, err := testClient.WithPrefix(prefix).UNLINKMatch("*", UNLINKMatchOptions{})
+	if err != nil {
+		t.Fatal("UNLINKMatch on prefixed view error:", err)
+	}
+	if removed != 10 {
+		t.Errorf("UNLINKMatch on prefixed view got %d removed, want 10", removed)
+	}
+	if v, err := testClient.GET(other); err != nil {
+		t.Error("GET error:", err)
+	} else if v != "stays" {
+		t.Errorf("GET %q got %q, want \"stays\"", other, v)
+	}
+}
+
+func TestUNLINKMatchNoPattern(t *testing.T) {
+	if _, err := testClient.UNLINKMatch("", UNLINKMatchOptions{}); err == nil {
+		t.Error("UNLINKMatch without pattern got no error")
+	}
+}
@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// VisitFunc receives a bulk string directly from the read buffer, without any
+// allocation. The payload is valid for the duration of the call only, i.e., it
+// must not be retained, nor modified. Slow visitors stall the pipeline.
+type VisitFunc func(payload []byte) error
+
+// GETVisit executes <https://redis.io/commands/get> with the value passed to
+// f. The return is false if the Key does not exist, in which case f is not
+// called. Errors from f are returned as is.
+func (c *Client[Key, Value]) GETVisit(k Key, f VisitFunc) (ok bool, err error) {
+	return c.commandVisit(requestWithString("*2\r\n$3\r\nGET\r\n$", k).idempotent(), f)
+}
+
+// HGETVisit executes <https://redis.io/commands/hget> with the value passed to
+// f. The return is false if the Key or the field does not exist, in which case
+// f is not called. Errors from f are returned as is.
+func (c *Client[Key, Value]) HGETVisit(k, field Key, f VisitFunc) (ok bool, err error) {
+	return c.commandVisit(requestWith2Strings("*3\r\n$4\r\nHGET\r\n$", k, field).idempotent(), f)
+}
+
+// LPOPVisit executes <https://redis.io/commands/lpop> with the element passed
+// to f. The return is false if the Key does not exist, in which case f is not
+// called. Errors from f are returned as is. The element is removed regardless.
+func (c *Client[Key, Value]) LPOPVisit(k Key, f VisitFunc) (ok bool, err error) {
+	return c.commandVisit(requestWithString("*2\r\n$4\r\nLPOP\r\n$", k), f)
+}
+
+// ElementFunc receives each element of an array reply as it is decoded, with
+// its index, directly from the read buffer. Null elements are nil. Element is
+// valid for the duration of the call only, i.e., it must not be retained, nor
+// modified. Slow receivers stall the pipeline.
+type ElementFunc func(index int, element []byte) error
+
+// MGETEach executes <https://redis.io/commands/mget> with each Value passed to
+// f, in order of appearance. Non-existing Keys get nil. The first error from f
+// stops any further calls, and it is returned as is.
+func (c *Client[Key, Value]) MGETEach(f ElementFunc, m ...Key) error {
+	return c.commandEach(requestWithList("\r\n$4\r\nMGET", m).idempotent(), f)
+}
+
+// LRANGEEach executes <https://redis.io/commands/lrange> with each element
+// passed to f, in order of appearance. The first error from f stops any further
+// calls, and it is returned as is.
+func (c *Client[Key, Value]) LRANGEEach(k Key, start, stop int64, f ElementFunc) error {
+	return c.commandEach(requestWithStringAnd2Decimals("*4\r\n$6\r\nLRANGE\r\n$", k, start, stop).idempotent(), f)
+}
+
+func (c *Client[Key, Value]) commandVisit(req *request, f VisitFunc) (bool, error) {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return false, err
+	}
+	visitErr, err := readBulkVisit(r, f)
+	c.passRead(err)
+	switch err {
+	case nil:
+		return true, visitErr
+	case errNull:
+		return false, nil
+	}
+	return false, err
+}
+
+func (c *Client[Key, Value]) commandEach(req *request, f ElementFunc) error {
+	c = c.route(req)
+	r, err := c.exchange(req)
+	if err != nil {
+		return err
+	}
+	visitErr, err := readArrayEach(r, f)
+	c.passRead(err)
+	switch err {
+	case nil:
+		return visitErr
+	case errNull:
+		return nil
+	}
+	return err
+}
+
+// ReadArrayEach is like readArray, yet it passes each element to f. The reply
+// is consumed in full, regardless of any visit error.
+func readArrayEach(r *connReader, f ElementFunc) (visitErr, err error) {
+	l, err := readArrayLen(r)
+	if l == 0 {
+		return nil, err
+	}
+	for i := 0; i < int(l); i++ {
+		if visitErr != nil {
+			// consume remainder
+			err := discardReply(r)
+			if _, ok := err.(ServerError); err != nil && !ok {
+				return nil, err
+			}
+			continue
+		}
+
+		var elementErr error
+		elementErr, err = readBulkVisit(r, func(element []byte) error {
+			return f(i, element)
+		})
+		switch err {
+		case nil:
+			visitErr = elementErr
+		case errNull:
+			visitErr = f(i, nil)
+		default:
+			return nil, err
+		}
+	}
+	return visitErr, nil
+}
+
+// VisitBufPool has buffers for bulk strings which exceed the read buffer.
+var visitBufPool = sync.Pool{
+	New: func() any { return new([]byte) },
+}
+
+// ReadBulkVisit is like readBulk, yet it passes the string to f. The reply is
+// consumed in full, regardless of the visit error.
+func readBulkVisit(r *connReader, f VisitFunc) (visitErr, err error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	switch {
+	case len(line) > 3 && line[0] == '$':
+		size = ParseInt(line[1 : len(line)-2])
+		if size < 0 || size > r.sizeMax {
+			if size == -1 {
+				// "null bulk string"
+				return nil, errNull
+			}
+			return nil, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+		}
+
+	case len(line) > 3 && line[0] == '=':
+		// RESP3 verbatim string has a 3-byte format prefix plus colon
+		size = ParseInt(line[1:len(line)-2]) - 4
+		if size < 0 || size > r.sizeMax {
+			return nil, fmt.Errorf("%w; received %.40q for verbatim string", errProtocol, line)
+		}
+		if _, err := r.Discard(4); err != nil {
+			return nil, err
+		}
+
+	case len(line) == 3 && line[0] == '_':
+		// RESP3 null
+		return nil, errNull
+
+	case len(line) > 3 && line[0] == '-':
+		return nil, ServerError(line[1 : len(line)-2])
+
+	case len(line) > 3 && (line[0] == '+' || line[0] == ':' || line[0] == ',' || line[0] == '('):
+		// simple string, integer, RESP3 double or RESP3 big number
+		return f(line[1 : len(line)-2]), nil
+
+	default:
+		return nil, fmt.Errorf("%w; received %.40q for bulk string", errProtocol, line)
+	}
+
+	if size+2 <= int64(r.Size()) {
+		// zero copy
+		payload, err := r.Peek(int(size) + 2)
+		if err != nil {
+			return nil, err
+		}
+		visitErr = f(payload[:size:size])
+		_, err = r.Discard(int(size) + 2) // including CRLF
+		return visitErr, err
+	}
+
+	// exceeds read buffer
+	bufp := visitBufPool.Get().(*[]byte)
+	defer func() {
+		if cap(*bufp) <= 1024*1024 {
+			visitBufPool.Put(bufp)
+		} // else oversized for reuse
+	}()
+	if int64(cap(*bufp)) < size {
+		*bufp = make([]byte, size)
+	}
+	payload := (*bufp)[:size]
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	if _, err := r.Discard(2); err != nil { // skip CRLF
+		return nil, err
+	}
+	return f(payload), nil
+}
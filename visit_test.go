@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReadBulkVisit(t *testing.T) {
+	large := strings.Repeat("x", 5000) // exceeds read buffer
+	r := testReader("$3\r\nabc\r\n$-1\r\n:42\r\n$5000\r\n" + large + "\r\n$1\r\nz\r\n-ERR x\r\n")
+
+	var got []string
+	visit := func(payload []byte) error {
+		got = append(got, string(payload))
+		return nil
+	}
+	if visitErr, err := readBulkVisit(r, visit); visitErr != nil || err != nil {
+		t.Errorf("bulk got errors %v and %v", visitErr, err)
+	}
+	if _, err := readBulkVisit(r, visit); err != errNull {
+		t.Errorf("null got error %v, want errNull", err)
+	}
+	if visitErr, err := readBulkVisit(r, visit); visitErr != nil || err != nil {
+		t.Errorf("integer got errors %v and %v", visitErr, err)
+	}
+	if visitErr, err := readBulkVisit(r, visit); visitErr != nil || err != nil {
+		t.Errorf("large bulk got errors %v and %v", visitErr, err)
+	}
+	if want := []string{"abc", "42", large}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("got %.20q, want %.20q", got, want)
+	}
+
+	fail := errors.New("visit failure")
+	visitErr, err := readBulkVisit(r, func([]byte) error { return fail })
+	if visitErr != fail || err != nil {
+		t.Errorf("failing visit got errors %v and %v, want %v and nil", visitErr, err, fail)
+	}
+	var e ServerError
+	if _, err := readBulkVisit(r, visit); !errors.As(err, &e) {
+		t.Errorf("error reply got error %v, want a ServerError", err)
+	}
+}
+
+func TestVisit(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test")
+
+	var got string
+	visit := func(payload []byte) error {
+		got = string(payload)
+		return nil
+	}
+	if ok, err := testClient.GETVisit(key, visit); err != nil || ok {
+		t.Errorf("GETVisit on absent key got %t with error %v", ok, err)
+	}
+	if err := testClient.SET(key, "v"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+	if ok, err := testClient.GETVisit(key, visit); err != nil || !ok || got != "v" {
+		t.Errorf(`GETVisit got %q, %t with error %v, want "v", true`, got, ok, err)
+	}
+
+	fail := errors.New("visit failure")
+	if _, err := testClient.GETVisit(key, func([]byte) error { return fail }); err != fail {
+		t.Errorf("GETVisit got error %v, want %v", err, fail)
+	}
+
+	hash := randomKey("test-hash")
+	if _, err := testClient.HSET(hash, "f", "hv"); err != nil {
+		t.Fatal("HSET error:", err)
+	}
+	if ok, err := testClient.HGETVisit(hash, "f", visit); err != nil || !ok || got != "hv" {
+		t.Errorf(`HGETVisit got %q, %t with error %v, want "hv", true`, got, ok, err)
+	}
+
+	list := randomKey("test-list")
+	if _, err := testClient.RPUSH(list, "lv"); err != nil {
+		t.Fatal("RPUSH error:", err)
+	}
+	if ok, err := testClient.LPOPVisit(list, visit); err != nil || !ok || got != "lv" {
+		t.Errorf(`LPOPVisit got %q, %t with error %v, want "lv", true`, got, ok, err)
+	}
+	if ok, err := testClient.LPOPVisit(list, visit); err != nil || ok {
+		t.Errorf("LPOPVisit on empty list got %t with error %v", ok, err)
+	}
+}
+
+func TestReadArrayEach(t *testing.T) {
+	r := testReader("*3\r\n$1\r\na\r\n$-1\r\n$0\r\n\r\n*2\r\n$1\r\nb\r\n$1\r\nc\r\n+OK\r\n")
+
+	var got []string
+	visitErr, err := readArrayEach(r, func(index int, element []byte) error {
+		if element == nil {
+			got = append(got, "<nil>")
+		} else {
+			got = append(got, string(element))
+		}
+		return nil
+	})
+	if visitErr != nil || err != nil {
+		t.Fatalf("got errors %v and %v", visitErr, err)
+	}
+	if want := []string{"a", "<nil>", ""}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// visit error consumes the remainder
+	fail := errors.New("visit failure")
+	var calls int
+	visitErr, err = readArrayEach(r, func(index int, element []byte) error {
+		calls++
+		return fail
+	})
+	if visitErr != fail || err != nil || calls != 1 {
+		t.Errorf("got errors %v and %v after %d calls, want %v after 1 call", visitErr, err, calls, fail)
+	}
+	if err := readOK(r); err != nil {
+		t.Error("reply after visit error got error:", err)
+	}
+}
+
+func TestEach(t *testing.T) {
+	t.Parallel()
+	key := randomKey("test")
+	if err := testClient.SET(key, "v"); err != nil {
+		t.Fatal("SET error:", err)
+	}
+
+	var got []string
+	collect := func(index int, element []byte) error {
+		got = append(got, fmt.Sprintf("%d:%q", index, element))
+		return nil
+	}
+	if err := testClient.MGETEach(collect, randomKey("absent"), key); err != nil {
+		t.Fatal("MGETEach error:", err)
+	}
+	if want := `0:"" 1:"v"`; strings.Join(got, " ") != want {
+		t.Errorf("MGETEach got %q, want %s", got, want)
+	}
+
+	list := randomKey("test-list")
+	for _, v := range []string{"a", "b"} {
+		if _, err := testClient.RPUSH(list, v); err != nil {
+			t.Fatal("RPUSH error:", err)
+		}
+	}
+	got = got[:0]
+	if err := testClient.LRANGEEach(list, 0, -1, collect); err != nil {
+		t.Fatal("LRANGEEach error:", err)
+	}
+	if want := `0:"a" 1:"b"`; strings.Join(got, " ") != want {
+		t.Errorf("LRANGEEach got %q, want %s", got, want)
+	}
+
+	fail := errors.New("visit failure")
+	if err := testClient.LRANGEEach(list, 0, -1, func(int, []byte) error { return fail }); err != fail {
+		t.Errorf("LRANGEEach got error %v, want %v", err, fail)
+	}
+	if v, err := testClient.GET(key); err != nil || v != "v" {
+		t.Errorf(`GET after visit error got %q with error %v, want "v"`, v, err)
+	}
+}